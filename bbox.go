@@ -0,0 +1,125 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BoundingBox is an area delimited by two parallels and two meridians, in decimal degrees.
+// MinLon is always lower than MaxLon: a box crossing the antimeridian is described by two boxes.
+type BoundingBox struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+// RadiusBoundingBoxes returns the smallest boxes containing all points within radius meters of the center,
+// following the spherical model of Distance. It is useful to prefilter rows in a database before calling Distance.
+// It returns two boxes when the circle crosses the antimeridian and a box covering all longitudes
+// when the circle contains a pole.
+// Details http://janmatuschek.de/LatitudeLongitudeBoundingCoordinates
+//  boxes := geo.RadiusBoundingBoxes(geo.Point{Lat: 13.7665217, Lon: 100.6068431}, 5000)
+func RadiusBoundingBoxes(center Point, radius float64) []BoundingBox {
+	r := radius / EarthRadius // angular radius
+	lat := toRadians(center.Lat)
+	minLat := lat - r
+	maxLat := lat + r
+
+	if r >= math.Pi || maxLat >= math.Pi/2 || minLat <= -math.Pi/2 {
+		// A pole is inside the circle, all longitudes are included
+		return []BoundingBox{{
+			MinLat: math.Max(-90, toDegrees(minLat)),
+			MinLon: -180,
+			MaxLat: math.Min(90, toDegrees(maxLat)),
+			MaxLon: 180,
+		}}
+	}
+
+	deltaLon := toDegrees(math.Asin(math.Sin(r) / math.Cos(lat)))
+	minLon := center.Lon - deltaLon
+	maxLon := center.Lon + deltaLon
+	box := BoundingBox{MinLat: toDegrees(minLat), MinLon: minLon, MaxLat: toDegrees(maxLat), MaxLon: maxLon}
+	switch {
+	case minLon < -180:
+		east, west := box, box
+		east.MinLon, east.MaxLon = minLon+360, 180
+		west.MinLon = -180
+		return []BoundingBox{west, east}
+	case maxLon > 180:
+		east, west := box, box
+		east.MaxLon = 180
+		west.MinLon, west.MaxLon = -180, maxLon-360
+		return []BoundingBox{west, east}
+	}
+	return []BoundingBox{box}
+}
+
+// RadiusSQL returns a SQL condition selecting rows in the bounding boxes of the circle of radius meters
+// around the center. latColumn and lonColumn are the names of the columns holding coordinates.
+// Rows selected must then be filtered with Distance.
+//  where := geo.RadiusSQL(geo.Point{Lat: 13.7665217, Lon: 100.6068431}, 5000, "lat", "lon")
+//  // (lat BETWEEN 13.7215 AND 13.8115 AND lon BETWEEN 100.5606 AND 100.6531)
+func RadiusSQL(center Point, radius float64, latColumn, lonColumn string) string {
+	boxes := RadiusBoundingBoxes(center, radius)
+	conditions := make([]string, len(boxes))
+	for i, b := range boxes {
+		conditions[i] = "(" + b.SQL(latColumn, lonColumn) + ")"
+	}
+	return strings.Join(conditions, " OR ")
+}
+
+// SQL returns a SQL condition selecting rows inside the box.
+// latColumn and lonColumn are the names of the columns holding coordinates.
+//  b.SQL("lat", "lon") // lat BETWEEN 13.7 AND 13.8 AND lon BETWEEN 100.5 AND 100.6
+func (b BoundingBox) SQL(latColumn, lonColumn string) string {
+	return latColumn + " BETWEEN " + formatFloat(b.MinLat) + " AND " + formatFloat(b.MaxLat) +
+		" AND " + lonColumn + " BETWEEN " + formatFloat(b.MinLon) + " AND " + formatFloat(b.MaxLon)
+}
+
+// Contains returns true if the point is inside the box or on its border.
+func (b BoundingBox) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+}
+
+// Center returns the point at the middle of the box.
+func (b BoundingBox) Center() Point {
+	return Point{Lat: (b.MinLat + b.MaxLat) / 2, Lon: (b.MinLon + b.MaxLon) / 2}
+}
+
+// DistanceTo returns the shortest distance in meters between the point and the box, on the sphere.
+// It is 0 if the point is inside the box.
+func (b BoundingBox) DistanceTo(p Point) float64 {
+	if b.Contains(p) {
+		return 0
+	}
+	// The closest point of a parallel is at the same longitude,
+	// so if the longitude is inside the box the closest point is on the top or bottom edge.
+	if p.Lon >= b.MinLon && p.Lon <= b.MaxLon {
+		return math.Min(p.Distance(Point{b.MinLat, p.Lon}), p.Distance(Point{b.MaxLat, p.Lon}))
+	}
+	return math.Min(distanceToMeridian(p, b.MinLon, b.MinLat, b.MaxLat),
+		distanceToMeridian(p, b.MaxLon, b.MinLat, b.MaxLat))
+}
+
+// distanceToMeridian returns the distance in meters between the point and
+// the segment of meridian lon between latitudes minLat and maxLat.
+func distanceToMeridian(p Point, lon, minLat, maxLat float64) float64 {
+	deltaLambda := toRadians(angleDiff(p.Lon, lon))
+	lat := 90.0
+	if p.Lat < 0 {
+		lat = -90
+	}
+	if math.Abs(deltaLambda) < math.Pi/2 {
+		// Latitude of the foot of the perpendicular from the point to the meridian
+		lat = toDegrees(math.Atan(math.Tan(toRadians(p.Lat)) / math.Cos(deltaLambda)))
+	}
+	lat = math.Max(minLat, math.Min(maxLat, lat))
+	return p.Distance(Point{lat, lon})
+}
+
+// formatFloat formats a float with the minimal number of digits.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRadiusBoundingBoxes(t *testing.T) {
+	tests := []struct {
+		name   string
+		center Point
+		radius float64
+		boxes  int
+	}{
+		{name: "Office", center: Point{13.7665217, 100.6068431}, radius: 5000, boxes: 1},
+		{name: "Large", center: Point{13.7665217, 100.6068431}, radius: 2000000, boxes: 1},
+		{name: "Antimeridian east", center: Point{-17.7134, 178.065}, radius: 300000, boxes: 2},
+		{name: "Antimeridian west", center: Point{65.5, -179.9}, radius: 10000, boxes: 2},
+		{name: "North pole", center: Point{89.99, 45}, radius: 5000, boxes: 1},
+		{name: "South pole", center: Point{-89.5, -120}, radius: 100000, boxes: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes := RadiusBoundingBoxes(tt.center, tt.radius)
+			if len(boxes) != tt.boxes {
+				t.Fatalf("RadiusBoundingBoxes() = %v, want %v boxes", boxes, tt.boxes)
+			}
+			for _, b := range boxes {
+				if b.MinLon > b.MaxLon || b.MinLon < -180 || b.MaxLon > 180 || b.MinLat < -90 || b.MaxLat > 90 {
+					t.Errorf("RadiusBoundingBoxes() invalid box %v", b)
+				}
+			}
+			// All points on the circle must be in a box
+			for bearing := 0.0; bearing < 360; bearing += 5 {
+				p := tt.center.Destination(bearing, tt.radius*0.999999)
+				inside := false
+				for _, b := range boxes {
+					inside = inside || b.Contains(p)
+				}
+				if !inside {
+					t.Errorf("RadiusBoundingBoxes() = %v does not contain %v", boxes, p)
+				}
+			}
+		})
+	}
+}
+
+func TestRadiusSQL(t *testing.T) {
+	got := RadiusSQL(Point{0, 180}, EarthRadius*math.Pi/180, "lat", "lon")
+	want := "(lat BETWEEN -1 AND 1 AND lon BETWEEN -180 AND -179) OR (lat BETWEEN -1 AND 1 AND lon BETWEEN 179 AND 180)"
+	if got != want {
+		t.Errorf("RadiusSQL() = %v, want %v", got, want)
+	}
+}
+
+func TestBoundingBox_DistanceTo(t *testing.T) {
+	b := BoundingBox{MinLat: -10, MinLon: 100, MaxLat: 20, MaxLon: 110}
+	tests := []struct {
+		name string
+		p    Point
+		want float64
+	}{
+		{name: "Inside", p: Point{15, 105}, want: 0},
+		{name: "North", p: Point{21, 105}, want: Distance(21, 105, 20, 105)},
+		{name: "West", p: Point{0, 99}, want: Distance(0, 99, 0, 100)},
+		{name: "Corner", p: Point{-11, 111}, want: Distance(-11, 111, -10, 110)},
+		{name: "Meridian", p: Point{15, 99}, want: 107525.38},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.DistanceTo(tt.p); math.Abs(got-tt.want) > 0.1 {
+				t.Errorf("DistanceTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package geo
+
+import "math"
+
+// Bearing returns the initial bearing (forward azimuth) in degrees from the first point to the second point,
+// following the great circle. Bearing is in the range [0, 360), clockwise from the north.
+//  geo.Bearing(13.7665217, 100.6068431, 13.7199345, 100.5197898)
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := toRadians(lat1)
+	phi2 := toRadians(lat2)
+	deltaLambda := toRadians(lon2 - lon1)
+
+	y := math.Sin(deltaLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(deltaLambda)
+
+	return normalizeBearing(toDegrees(math.Atan2(y, x)))
+}
+
+// FinalBearing returns the bearing in degrees when arriving at the second point from the first point.
+// On a great circle the bearing changes along the path, except for meridians and the equator.
+//  geo.FinalBearing(13.7665217, 100.6068431, 13.7199345, 100.5197898)
+func FinalBearing(lat1, lon1, lat2, lon2 float64) float64 {
+	return normalizeBearing(Bearing(lat2, lon2, lat1, lon1) + 180)
+}
+
+// Destination returns the point reached when travelling the given distance in meters
+// from a start point with an initial bearing in degrees along a great circle.
+//  lat, lon := geo.Destination(13.7665217, 100.6068431, 90, 5000)
+func Destination(lat, lon, bearing, distance float64) (lat2, lon2 float64) {
+	phi1 := toRadians(lat)
+	lambda1 := toRadians(lon)
+	theta := toRadians(bearing)
+	delta := distance / EarthRadius // angular distance
+
+	sinPhi2 := math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta)
+	phi2 := math.Asin(sinPhi2)
+	y := math.Sin(theta) * math.Sin(delta) * math.Cos(phi1)
+	x := math.Cos(delta) - math.Sin(phi1)*sinPhi2
+	lambda2 := lambda1 + math.Atan2(y, x)
+
+	return toDegrees(phi2), normalizeLongitude(toDegrees(lambda2))
+}
+
+// Midpoint returns the point half way between two points along the great circle.
+//  lat, lon := geo.Midpoint(13.7665217, 100.6068431, 13.7199345, 100.5197898)
+func Midpoint(lat1, lon1, lat2, lon2 float64) (lat, lon float64) {
+	phi1 := toRadians(lat1)
+	phi2 := toRadians(lat2)
+	lambda1 := toRadians(lon1)
+	deltaLambda := toRadians(lon2 - lon1)
+
+	bx := math.Cos(phi2) * math.Cos(deltaLambda)
+	by := math.Cos(phi2) * math.Sin(deltaLambda)
+	phi := math.Atan2(math.Sin(phi1)+math.Sin(phi2), math.Hypot(math.Cos(phi1)+bx, by))
+	lambda := lambda1 + math.Atan2(by, math.Cos(phi1)+bx)
+
+	return toDegrees(phi), normalizeLongitude(toDegrees(lambda))
+}
+
+// Intermediate returns the point at the given fraction of the great circle path between two points.
+// Fraction 0 is the first point and fraction 1 the second one.
+// Antipodal points are joined by every meridian, we follow the meridian of the first point
+// to the north, or to the south from the north pole.
+//  lat, lon := geo.Intermediate(13.7665217, 100.6068431, 13.7199345, 100.5197898, 0.25)
+func Intermediate(lat1, lon1, lat2, lon2, fraction float64) (lat, lon float64) {
+	delta := Distance(lat1, lon1, lat2, lon2) / EarthRadius // angular distance
+	sinDelta := math.Sin(delta)
+	if math.Abs(sinDelta) < 1e-12 {
+		if delta < math.Pi/2 {
+			// Same points
+			return lat1, lon1
+		}
+		if lat1 > 90-1e-9 {
+			return 90 - 180*fraction, lon1
+		}
+		lat = lat1 + 180*fraction
+		if lat > 90 {
+			// Beyond the north pole
+			return 180 - lat, normalizeLongitude(lon1 + 180)
+		}
+		return lat, lon1
+	}
+
+	phi1 := toRadians(lat1)
+	phi2 := toRadians(lat2)
+	lambda1 := toRadians(lon1)
+	lambda2 := toRadians(lon2)
+
+	a := math.Sin((1-fraction)*delta) / sinDelta
+	b := math.Sin(fraction*delta) / sinDelta
+	x := a*math.Cos(phi1)*math.Cos(lambda1) + b*math.Cos(phi2)*math.Cos(lambda2)
+	y := a*math.Cos(phi1)*math.Sin(lambda1) + b*math.Cos(phi2)*math.Sin(lambda2)
+	z := a*math.Sin(phi1) + b*math.Sin(phi2)
+
+	return toDegrees(math.Atan2(z, math.Hypot(x, y))), toDegrees(math.Atan2(y, x))
+}
+
+// Interpolate returns n points evenly spaced along the great circle between two points,
+// the first and the last ones being the given points. Each point is a {lat, lon} pair.
+// If n is lower than 2, only the two given points are returned.
+//  route := geo.Interpolate(13.7665217, 100.6068431, 13.7199345, 100.5197898, 10)
+func Interpolate(lat1, lon1, lat2, lon2 float64, n int) [][2]float64 {
+	if n < 2 {
+		n = 2
+	}
+	points := make([][2]float64, n)
+	points[0] = [2]float64{lat1, lon1}
+	for i := 1; i < n-1; i++ {
+		lat, lon := Intermediate(lat1, lon1, lat2, lon2, float64(i)/float64(n-1))
+		points[i] = [2]float64{lat, lon}
+	}
+	points[n-1] = [2]float64{lat2, lon2}
+	return points
+}
+
+// normalizeBearing reduces a bearing in degrees to the range [0, 360).
+func normalizeBearing(bearing float64) float64 {
+	bearing = math.Mod(bearing, 360)
+	if bearing < 0 {
+		bearing += 360
+	}
+	return bearing
+}
@@ -0,0 +1,111 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBearing(t *testing.T) {
+	type args struct {
+		lat1 float64
+		lon1 float64
+		lat2 float64
+		lon2 float64
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  float64
+		final float64
+	}{
+		{name: "North", args: args{lat1: 0, lon1: 100, lat2: 10, lon2: 100}, want: 0, final: 0},
+		{name: "South", args: args{lat1: 10, lon1: 100, lat2: 0, lon2: 100}, want: 180, final: 180},
+		{name: "East on equator", args: args{lat1: 0, lon1: 100, lat2: 0, lon2: 110}, want: 90, final: 90},
+		{name: "West across antimeridian", args: args{lat1: 0, lon1: -179, lat2: 0, lon2: 179}, want: 270, final: 270},
+		{name: "Office to BiGC", args: args{lat1: 13.7665217, lon1: 100.6068431, lat2: 13.7199345, lon2: 100.5197898},
+			want: 241.159, final: 241.138},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Bearing(tt.args.lat1, tt.args.lon1, tt.args.lat2, tt.args.lon2); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Bearing() = %v, want %v", got, tt.want)
+			}
+			if got := FinalBearing(tt.args.lat1, tt.args.lon1, tt.args.lat2, tt.args.lon2); math.Abs(got-tt.final) > 0.01 {
+				t.Errorf("FinalBearing() = %v, want %v", got, tt.final)
+			}
+		})
+	}
+}
+
+func TestDestination(t *testing.T) {
+	lat1, lon1 := 13.7665217, 100.6068431
+	for _, bearing := range []float64{0, 45, 90, 135, 180, 270, 359} {
+		lat2, lon2 := Destination(lat1, lon1, bearing, 10000)
+		if d := Distance(lat1, lon1, lat2, lon2); math.Abs(d-10000) > 1e-6 {
+			t.Errorf("Destination(%v) distance = %v, want 10000", bearing, d)
+		}
+		if b := Bearing(lat1, lon1, lat2, lon2); math.Abs(angleDiff(b, bearing)) > 1e-6 {
+			t.Errorf("Destination(%v) bearing = %v", bearing, b)
+		}
+	}
+	if _, lon := Destination(0, 179.9, 90, 50000); lon > -179 || lon < -180 {
+		t.Errorf("Destination() across antimeridian = %v", lon)
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	lat1, lon1, lat2, lon2 := 13.7665217, 100.6068431, 48.8566, 2.3522
+	lat, lon := Midpoint(lat1, lon1, lat2, lon2)
+	d1 := Distance(lat1, lon1, lat, lon)
+	d2 := Distance(lat, lon, lat2, lon2)
+	if math.Abs(d1-d2) > 1e-6 || math.Abs(d1+d2-Distance(lat1, lon1, lat2, lon2)) > 1e-6 {
+		t.Errorf("Midpoint() = %v,%v: %v and %v", lat, lon, d1, d2)
+	}
+	ilat, ilon := Intermediate(lat1, lon1, lat2, lon2, 0.5)
+	if math.Abs(ilat-lat) > 1e-9 || math.Abs(ilon-lon) > 1e-9 {
+		t.Errorf("Intermediate() = %v,%v, want %v,%v", ilat, ilon, lat, lon)
+	}
+
+	// Antipodal points
+	tests := []struct {
+		lat1, lon1, lat2, lon2, fraction float64
+		lat, lon                         float64
+	}{
+		{0, 0, 0, 180, 0, 0, 0},
+		{0, 0, 0, 180, 0.25, 45, 0},
+		{0, 0, 0, 180, 0.75, 45, 180},
+		{0, 0, 0, 180, 1, 0, 180},
+		{90, 0, -90, 0, 0.25, 45, 0},
+		{90, 30, -90, 0, 0.5, 0, 30},
+		{-90, 0, 90, 0, 0.25, -45, 0},
+		{-30, 100, 30, -80, 0.5, 60, 100},
+		{-30, 100, 30, -80, 0.75, 75, -80},
+		{13.75, 100.5, 13.75, 100.5, 0.5, 13.75, 100.5},
+	}
+	for _, tt := range tests {
+		lat, lon := Intermediate(tt.lat1, tt.lon1, tt.lat2, tt.lon2, tt.fraction)
+		if math.Abs(lat-tt.lat) > 1e-9 || math.Abs(normalizeLongitude(lon-tt.lon)) > 1e-9 {
+			t.Errorf("Intermediate(%v, %v, %v, %v, %v) = %v,%v, want %v,%v", tt.lat1, tt.lon1, tt.lat2, tt.lon2, tt.fraction, lat, lon, tt.lat, tt.lon)
+		}
+	}
+	if lat, _ := Intermediate(0, 0, 0, 180, 0.5); math.Abs(lat-90) > 1e-9 {
+		t.Errorf("Intermediate(antipodes, 0.5) = %v, want the north pole", lat)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	lat1, lon1, lat2, lon2 := 13.7665217, 100.6068431, 48.8566, 2.3522
+	points := Interpolate(lat1, lon1, lat2, lon2, 11)
+	if len(points) != 11 {
+		t.Fatalf("Interpolate() returned %v points, want 11", len(points))
+	}
+	if points[0] != [2]float64{lat1, lon1} || points[10] != [2]float64{lat2, lon2} {
+		t.Errorf("Interpolate() does not start and end on given points")
+	}
+	step := Distance(lat1, lon1, lat2, lon2) / 10
+	for i := 1; i < len(points); i++ {
+		if d := Distance(points[i-1][0], points[i-1][1], points[i][0], points[i][1]); math.Abs(d-step) > 1e-3 {
+			t.Errorf("Interpolate() step %v = %v, want %v", i, d, step)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+type (
+	// Cluster is a group of items close to each other, such as venues shown as one marker on a map.
+	Cluster struct {
+		Point   Point       // Point is the centroid of the items
+		Count   int         // Count is the number of items
+		Members []IndexItem // Members are the items of the cluster with their payloads, such as place IDs
+	}
+
+	// ClusterOptions are the settings of a Clusterer.
+	ClusterOptions struct {
+		Radius    float64 // Radius of a cluster in pixels of 256 pixels tiles, 40 if 0 or less
+		MinZoom   int     // MinZoom is the lowest zoom level with clusters, from 0 to 30
+		MaxZoom   int     // MaxZoom is the highest zoom level with clusters, 16 if 0, from MinZoom to 30. Items are not clustered above it.
+		MinPoints int     // MinPoints is the minimum number of items of a cluster, 2 if 0 or less
+	}
+
+	// Clusterer clusters items for all zoom levels of a map at once, as the supercluster library does:
+	// clusters of a zoom level are made of the clusters of the next zoom level,
+	// so a cluster splits into its children when the user zooms in.
+	// Details https://github.com/mapbox/supercluster
+	Clusterer struct {
+		options ClusterOptions
+		items   []IndexItem
+		levels  [][]clusterNode // levels[i] are the clusters of zoom MinZoom+i, the last level holds the items
+	}
+
+	// clusterNode is a cluster of a zoom level.
+	clusterNode struct {
+		v        vector // sum of the positions of the items on the unit sphere
+		x, y     float64
+		count    int
+		children []int // clusters of the next level, or the item for the last level
+	}
+)
+
+// IndexItem returns the place as an item with its place ID as payload, to index or cluster places.
+func (p Place) IndexItem() IndexItem {
+	return IndexItem{Point: p.Point(), Data: p.PlaceID}
+}
+
+// IndexItem returns the Google place as an item with its place ID as payload, to index or cluster places.
+func (g GooglePlace) IndexItem() IndexItem {
+	return IndexItem{Point: g.Point(), Data: g.PlaceID}
+}
+
+// GridClusters groups the items in square cells of size pixels on the Web Mercator map at the zoom level,
+// with 256 pixels tiles. It is the fastest clustering but clusters follow the grid, not the items.
+//  clusters := geo.GridClusters(venues, 12, 60)
+func GridClusters(items []IndexItem, zoom int, size float64) []Cluster {
+	cell := size / (256 * math.Exp2(float64(zoom)))
+	var clusters []Cluster
+	cells := map[[2]int]int{}
+	for _, item := range items {
+		x, y := mercatorUnit(item.Point)
+		key := [2]int{int(math.Floor(x / cell)), int(math.Floor(y / cell))}
+		i, ok := cells[key]
+		if !ok {
+			i = len(clusters)
+			cells[key] = i
+			clusters = append(clusters, Cluster{})
+		}
+		clusters[i].Members = append(clusters[i].Members, item)
+	}
+	for i := range clusters {
+		clusters[i].Count = len(clusters[i].Members)
+		clusters[i].Point = itemsCentroid(clusters[i].Members)
+	}
+	return clusters
+}
+
+// DBSCAN groups the items with DBSCAN algorithm: an item with at least minPoints items,
+// itself included, closer than eps meters is a core item, and core items closer than eps meters
+// are in the same cluster with their neighbors. Items in no cluster are returned as noise.
+// Unlike grid clustering, clusters can have any shape and the number of clusters is not known in advance.
+// Details https://en.wikipedia.org/wiki/DBSCAN
+//  clusters, noise := geo.DBSCAN(venues, 500, 5)
+func DBSCAN(items []IndexItem, eps float64, minPoints int) (clusters []Cluster, noise []IndexItem) {
+	indexed := make([]IndexItem, len(items))
+	for i, item := range items {
+		indexed[i] = IndexItem{Point: item.Point, Data: i}
+	}
+	index := NewSpatialIndex(indexed)
+
+	// labels are 0 for unvisited items, -1 for noise and the cluster number + 1 for the others
+	labels := make([]int, len(items))
+	for i, item := range items {
+		if labels[i] != 0 {
+			continue
+		}
+		neighbors := index.Radius(item.Point, eps)
+		if len(neighbors) < minPoints {
+			labels[i] = -1
+			continue
+		}
+		clusters = append(clusters, Cluster{})
+		label := len(clusters)
+		labels[i] = label
+		for len(neighbors) > 0 {
+			j := neighbors[0].Data.(int)
+			neighbors = neighbors[1:]
+			if labels[j] == -1 {
+				// Noise close to a core item is a border item of the cluster
+				labels[j] = label
+			}
+			if labels[j] != 0 {
+				continue
+			}
+			labels[j] = label
+			if next := index.Radius(items[j].Point, eps); len(next) >= minPoints {
+				neighbors = append(neighbors, next...)
+			}
+		}
+	}
+
+	for i, item := range items {
+		if labels[i] == -1 {
+			noise = append(noise, item)
+			continue
+		}
+		c := &clusters[labels[i]-1]
+		c.Members = append(c.Members, item)
+	}
+	for i := range clusters {
+		clusters[i].Count = len(clusters[i].Members)
+		clusters[i].Point = itemsCentroid(clusters[i].Members)
+	}
+	return
+}
+
+// NewClusterer returns a clusterer of the items for zoom levels from options MinZoom to MaxZoom.
+// Zoom levels out of range are clamped: MaxZoom lower than MinZoom is MinZoom.
+//  clusterer := geo.NewClusterer(venues, geo.ClusterOptions{Radius: 60, MaxZoom: 18})
+//  clusters := clusterer.Clusters(view, 12)
+func NewClusterer(items []IndexItem, options ClusterOptions) *Clusterer {
+	if options.Radius <= 0 {
+		options.Radius = 40
+	}
+	if options.MaxZoom == 0 {
+		options.MaxZoom = 16
+	}
+	if options.MinPoints <= 0 {
+		options.MinPoints = 2
+	}
+	options.MinZoom = clampTile(options.MinZoom, tileMaxZoom+1)
+	options.MaxZoom = options.MinZoom + clampTile(options.MaxZoom-options.MinZoom, tileMaxZoom-options.MinZoom+1)
+	c := &Clusterer{options: options, items: items}
+
+	leaves := make([]clusterNode, len(items))
+	for i, item := range items {
+		leaves[i] = clusterNode{v: pointToVector(item.Point), count: 1, children: []int{i}}
+		leaves[i].x, leaves[i].y = mercatorUnit(item.Point)
+	}
+	c.levels = make([][]clusterNode, options.MaxZoom-options.MinZoom+2)
+	c.levels[len(c.levels)-1] = leaves
+	for z := options.MaxZoom; z >= options.MinZoom; z-- {
+		c.levels[z-options.MinZoom] = c.cluster(c.levels[z-options.MinZoom+1], z)
+	}
+	return c
+}
+
+// Clusters returns the clusters of the zoom level whose centroid is inside the box.
+// Above MaxZoom, each item is a cluster of one item.
+func (c *Clusterer) Clusters(b BoundingBox, zoom int) (clusters []Cluster) {
+	level := zoom - c.options.MinZoom
+	if level < 0 {
+		level = 0
+	} else if level >= len(c.levels) {
+		level = len(c.levels) - 1
+	}
+	for _, n := range c.levels[level] {
+		p := vectorToPoint(n.v)
+		if n.count == 1 || n.v.norm() < 1e-9 {
+			// We keep the exact position of single items, and of items all around the globe
+			p = c.items[c.leaf(level, n)].Point
+		}
+		if !b.Contains(p) {
+			continue
+		}
+		cluster := Cluster{Point: p, Count: n.count, Members: make([]IndexItem, 0, n.count)}
+		c.members(level, n, &cluster.Members)
+		clusters = append(clusters, cluster)
+	}
+	return
+}
+
+// cluster returns the clusters of zoom level z, made of the clusters of the next level.
+func (c *Clusterer) cluster(next []clusterNode, z int) []clusterNode {
+	// The radius in Web Mercator units, where the world is 1 wide
+	r := c.options.Radius / (256 * math.Exp2(float64(z)))
+	// Clusters are put in a grid of cells at least r wide, so neighbors are in the 9 cells around.
+	// Columns fill the world exactly, to find neighbors across the antimeridian.
+	columns := int64(math.Max(1, math.Floor(1/r)))
+	cellOf := func(n clusterNode) (col, row int64) {
+		return int64(n.x*float64(columns)) % columns, int64(n.y * float64(columns))
+	}
+	// The clusters sorted by cell, faster than a map of cells
+	keys := make([]int64, len(next))
+	order := make([]int, len(next))
+	for i, n := range next {
+		col, row := cellOf(n)
+		keys[i] = row*columns + col
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+	sorted := make([]int64, len(order))
+	for k, i := range order {
+		sorted[k] = keys[i]
+	}
+	// cells returns the clusters of the cells of the row from column first to last
+	cells := func(row, first, last int64) []int {
+		start := sort.Search(len(sorted), func(k int) bool { return sorted[k] >= row*columns+first })
+		end := start
+		for end < len(sorted) && sorted[end] <= row*columns+last {
+			end++
+		}
+		return order[start:end]
+	}
+
+	var level []clusterNode
+	visited := make([]bool, len(next))
+	// found[j] is i+1 once j is a neighbor of i, as the 9 cells are not distinct for big radiuses
+	found := make([]int, len(next))
+	for i, n := range next {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+		cluster := clusterNode{v: n.v, x: n.x, y: n.y, count: n.count, children: []int{i}}
+
+		col, row := cellOf(n)
+		var neighbors []int
+		// Columns around are contiguous, except across the antimeridian
+		ranges := [][2]int64{{col - 1, col + 1}}
+		if col == 0 {
+			ranges = [][2]int64{{0, 1}, {columns - 1, columns - 1}}
+		} else if col == columns-1 {
+			ranges = [][2]int64{{col - 1, col}, {0, 0}}
+		}
+		for y := row - 1; y <= row+1; y++ {
+			for _, cols := range ranges {
+				for _, j := range cells(y, cols[0], cols[1]) {
+					if visited[j] || found[j] == i+1 {
+						continue
+					}
+					found[j] = i + 1
+					dx := math.Abs(n.x - next[j].x)
+					// Distances go across the antimeridian
+					dx = math.Min(dx, 1-dx)
+					if dy := n.y - next[j].y; dx*dx+dy*dy <= r*r {
+						neighbors = append(neighbors, j)
+					}
+				}
+			}
+		}
+		count := n.count
+		for _, j := range neighbors {
+			count += next[j].count
+		}
+		if len(neighbors) > 0 && count >= c.options.MinPoints {
+			for _, j := range neighbors {
+				visited[j] = true
+				cluster.v = cluster.v.add(next[j].v)
+				cluster.children = append(cluster.children, j)
+			}
+			cluster.count = count
+			if cluster.v.norm() >= 1e-9 {
+				cluster.x, cluster.y = mercatorUnit(vectorToPoint(cluster.v))
+			}
+		}
+		level = append(level, cluster)
+	}
+	return level
+}
+
+// members appends the items of the cluster n of the level to members.
+func (c *Clusterer) members(level int, n clusterNode, members *[]IndexItem) {
+	if level == len(c.levels)-1 {
+		*members = append(*members, c.items[n.children[0]])
+		return
+	}
+	for _, child := range n.children {
+		c.members(level+1, c.levels[level+1][child], members)
+	}
+}
+
+// leaf returns the index of the first item of the cluster n of the level.
+func (c *Clusterer) leaf(level int, n clusterNode) int {
+	for ; level < len(c.levels)-1; level++ {
+		n = c.levels[level+1][n.children[0]]
+	}
+	return n.children[0]
+}
+
+// itemsCentroid returns the centroid of the items on the sphere.
+func itemsCentroid(items []IndexItem) Point {
+	if len(items) == 1 {
+		return items[0].Point
+	}
+	var v vector
+	for _, item := range items {
+		v = v.add(pointToVector(item.Point))
+	}
+	if v.norm() < 1e-9 {
+		// Items are spread all around the globe
+		return items[0].Point
+	}
+	return vectorToPoint(v)
+}
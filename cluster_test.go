@@ -0,0 +1,204 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// venues returns 3 venues in Bangkok, 1 in Chiang Mai and 2 on each side of the antimeridian in Fiji.
+func venues() []IndexItem {
+	return []IndexItem{
+		{Point{13.7563, 100.5018}, "bkk1"},
+		{Point{18.7883, 98.9853}, "cnx"},
+		{Point{13.7460, 100.5350}, "bkk2"},
+		{Point{-16.8, 179.999}, "fiji1"},
+		{Point{13.7367, 100.5232}, "bkk3"},
+		{Point{-16.8, -179.999}, "fiji2"},
+	}
+}
+
+// memberIDs returns the payloads of the members of the cluster.
+func memberIDs(c Cluster) (ids []interface{}) {
+	for _, m := range c.Members {
+		ids = append(ids, m.Data)
+	}
+	return
+}
+
+func TestGridClusters(t *testing.T) {
+	tests := []struct {
+		zoom int
+		want [][]interface{}
+	}{
+		{3, [][]interface{}{{"bkk1", "cnx", "bkk2", "bkk3"}, {"fiji1"}, {"fiji2"}}},
+		// Cells split close items, even on both sides of the antimeridian
+		{5, [][]interface{}{{"bkk1"}, {"cnx"}, {"bkk2", "bkk3"}, {"fiji1"}, {"fiji2"}}},
+		{18, [][]interface{}{{"bkk1"}, {"cnx"}, {"bkk2"}, {"fiji1"}, {"bkk3"}, {"fiji2"}}},
+	}
+	for _, tt := range tests {
+		got := GridClusters(venues(), tt.zoom, 60)
+		if len(got) != len(tt.want) {
+			t.Errorf("GridClusters(%v) returned %v clusters, want %v", tt.zoom, len(got), len(tt.want))
+			continue
+		}
+		for i, c := range got {
+			ids := memberIDs(c)
+			if c.Count != len(tt.want[i]) || len(ids) != len(tt.want[i]) {
+				t.Errorf("GridClusters(%v)[%v] = %v, want %v", tt.zoom, i, ids, tt.want[i])
+				continue
+			}
+			for j := range ids {
+				if ids[j] != tt.want[i][j] {
+					t.Errorf("GridClusters(%v)[%v] = %v, want %v", tt.zoom, i, ids, tt.want[i])
+					break
+				}
+			}
+		}
+	}
+
+	got := GridClusters(venues(), 5, 60)
+	if c := got[2].Point; math.Abs(c.Lat-13.74135) > 1e-5 || math.Abs(c.Lon-100.52910) > 1e-5 {
+		t.Errorf("GridClusters() centroid = %v", c)
+	}
+	if got[1].Point != (Point{18.7883, 98.9853}) {
+		t.Errorf("GridClusters() single item centroid = %v", got[1].Point)
+	}
+}
+
+func TestDBSCAN(t *testing.T) {
+	var items []IndexItem
+	// A dense group of 5 venues
+	for i := 0; i < 5; i++ {
+		items = append(items, IndexItem{Point{13.7563 + float64(i)*0.0005, 100.5018}, i})
+	}
+	// A street of venues 300 meters apart, about 3 km long
+	for i := 0; i < 10; i++ {
+		p := Point{13.70, 100.60}.Destination(90, float64(i)*300)
+		items = append(items, IndexItem{p, 10 + i})
+	}
+	// Isolated venues
+	items = append(items, IndexItem{Point{13.80, 100.70}, 20}, IndexItem{Point{18.7883, 98.9853}, 21})
+
+	clusters, noise := DBSCAN(items, 400, 3)
+	if len(clusters) != 2 {
+		t.Fatalf("DBSCAN() returned %v clusters, want 2", len(clusters))
+	}
+	if clusters[0].Count != 5 || clusters[1].Count != 10 {
+		t.Errorf("DBSCAN() cluster counts = %v and %v, want 5 and 10", clusters[0].Count, clusters[1].Count)
+	}
+	for i, c := range clusters {
+		for j, id := range memberIDs(c) {
+			if id != i*10+j {
+				t.Errorf("DBSCAN() cluster %v = %v", i, memberIDs(c))
+				break
+			}
+		}
+	}
+	if c := clusters[1].Point; c.Distance(items[5].Point.Destination(90, 1350)) > 1 {
+		t.Errorf("DBSCAN() centroid = %v", c)
+	}
+	if len(noise) != 2 || noise[0].Data != 20 || noise[1].Data != 21 {
+		t.Errorf("DBSCAN() noise = %v", noise)
+	}
+
+	// Venues of the street have only 2 neighbors
+	if clusters, noise := DBSCAN(items, 400, 4); len(clusters) != 1 || clusters[0].Count != 5 || len(noise) != 12 {
+		t.Errorf("DBSCAN(4) returned %v clusters and %v noise", len(clusters), len(noise))
+	}
+	if clusters, noise := DBSCAN(nil, 400, 3); clusters != nil || noise != nil {
+		t.Errorf("DBSCAN(nil) = %v, %v", clusters, noise)
+	}
+}
+
+func TestClusterer(t *testing.T) {
+	items := randomItems(3000)
+	items = append(items, venues()...)
+	clusterer := NewClusterer(items, ClusterOptions{})
+	world := BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+
+	previous := 0
+	for zoom := 0; zoom <= 17; zoom++ {
+		clusters := clusterer.Clusters(world, zoom)
+		total := 0
+		seen := map[interface{}]bool{}
+		for _, c := range clusters {
+			if c.Count != len(c.Members) {
+				t.Errorf("zoom %v: cluster Count = %v with %v members", zoom, c.Count, len(c.Members))
+			}
+			for _, m := range c.Members {
+				seen[m.Data] = true
+			}
+			total += c.Count
+		}
+		if total != len(items) || len(seen) != len(items) {
+			t.Errorf("zoom %v: clusters hold %v items, want %v", zoom, total, len(items))
+		}
+		if len(clusters) < previous {
+			t.Errorf("zoom %v: %v clusters, less than the lower zoom", zoom, len(clusters))
+		}
+		previous = len(clusters)
+	}
+	if n := len(clusterer.Clusters(world, 0)); n > 200 {
+		t.Errorf("zoom 0: %v clusters, want less", n)
+	}
+	if n := len(clusterer.Clusters(world, 17)); n != len(items) {
+		t.Errorf("zoom 17: %v clusters, want %v", n, len(items))
+	}
+
+	thailand := BoundingBox{MinLat: 5, MinLon: 97, MaxLat: 21, MaxLon: 106}
+	for _, c := range clusterer.Clusters(thailand, 3) {
+		if !thailand.Contains(c.Point) {
+			t.Errorf("Clusters() returned %v outside the box", c.Point)
+		}
+	}
+
+	// Venues on both sides of the antimeridian are clustered together
+	clusterer = NewClusterer(venues(), ClusterOptions{Radius: 60, MaxZoom: 18})
+	if n := len(clusterer.Clusters(world, 12)); n != 4 {
+		t.Errorf("Clusters(12) returned %v clusters, want 4", n)
+	}
+	clusters := clusterer.Clusters(world, 10)
+	want := [][]interface{}{{"bkk1", "bkk2", "bkk3"}, {"cnx"}, {"fiji1", "fiji2"}}
+	if len(clusters) != len(want) {
+		t.Fatalf("Clusters(10) returned %v clusters, want %v", len(clusters), len(want))
+	}
+	for i, c := range clusters {
+		if len(c.Members) != len(want[i]) {
+			t.Errorf("Clusters(10)[%v] = %v, want %v", i, memberIDs(c), want[i])
+		}
+	}
+	if p := clusters[2].Point; math.Abs(p.Lat+16.8) > 1e-6 || math.Abs(math.Abs(p.Lon)-180) > 1e-6 {
+		t.Errorf("Clusters(10) centroid = %v, want (-16.8, 180)", p)
+	}
+	// Options out of range are clamped
+	for _, options := range []ClusterOptions{{MinZoom: 20}, {MinZoom: -3, MaxZoom: -1}, {MinZoom: 50, MaxZoom: 100}, {Radius: -1, MinPoints: -1}} {
+		clusterer = NewClusterer(venues(), options)
+		if n := len(clusterer.Clusters(world, 0)); n == 0 || n > len(venues()) {
+			t.Errorf("NewClusterer(%+v).Clusters(0) returned %v clusters", options, n)
+		}
+		if n := len(clusterer.Clusters(world, 40)); n != len(venues()) {
+			t.Errorf("NewClusterer(%+v).Clusters(40) returned %v clusters, want %v", options, n, len(venues()))
+		}
+	}
+}
+
+func TestPlace_IndexItem(t *testing.T) {
+	p := Place{Lat: 13.7563, Long: 100.5018, PlaceID: "123"}
+	if item := p.IndexItem(); item.Point != (Point{13.7563, 100.5018}) || item.Data != "123" {
+		t.Errorf("Place.IndexItem() = %v", item)
+	}
+	var g GooglePlace
+	g.Geometry.Location.Lat, g.Geometry.Location.Lng = 13.7563, 100.5018
+	g.PlaceID = "ChIJ"
+	if item := g.IndexItem(); item.Point != (Point{13.7563, 100.5018}) || item.Data != "ChIJ" {
+		t.Errorf("GooglePlace.IndexItem() = %v", item)
+	}
+}
+
+func BenchmarkNewClusterer(b *testing.B) {
+	items := randomItems(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewClusterer(items, ClusterOptions{})
+	}
+}
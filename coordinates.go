@@ -0,0 +1,363 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidCoordinates is wrapped by the errors of ParsePoint, ParseLatitude and ParseLongitude.
+var ErrInvalidCoordinates = errors.New("Invalid coordinates")
+
+type (
+	// CoordinatesError is returned when coordinates can not be parsed, it explains what failed.
+	// errors.Is(err, geo.ErrInvalidCoordinates) is true for such errors.
+	CoordinatesError struct {
+		Input  string // Input is the parsed text
+		Reason string // Reason tells what is wrong in the text
+	}
+
+	// coordinate is a latitude or a longitude being parsed.
+	coordinate struct {
+		values     []float64 // degrees, then minutes and seconds if any
+		units      bool      // values are followed by °, ' or " symbols
+		fraction   bool      // the last value has decimals
+		negative   bool
+		hemisphere byte // N, S, E, W or 0 if there is no hemisphere letter
+	}
+
+	// coordinateToken is a number, a symbol, a word or a separator of parsed coordinates.
+	coordinateToken struct {
+		text     string
+		number   float64
+		isNumber bool
+		signed   bool
+		unit     int  // 0 for °, 1 for ', 2 for " and -1 if the number has no symbol
+		letter   byte // hemisphere letter
+		isComma  bool
+	}
+)
+
+// hemisphereWords are the hemisphere letters and words accepted by the parser.
+var hemisphereWords = map[string]byte{
+	"N": 'N', "S": 'S', "E": 'E', "W": 'W',
+	"NORTH": 'N', "SOUTH": 'S', "EAST": 'E', "WEST": 'W',
+}
+
+func (e *CoordinatesError) Error() string {
+	return ErrInvalidCoordinates.Error() + " " + strconv.Quote(e.Input) + ": " + e.Reason
+}
+
+// Unwrap returns ErrInvalidCoordinates.
+func (e *CoordinatesError) Unwrap() error {
+	return ErrInvalidCoordinates
+}
+
+// ParsePoint parses coordinates written by people, latitude first unless hemisphere letters tell otherwise.
+// It accepts decimal degrees, degrees minutes seconds (DMS) and degrees decimal minutes (DDM),
+// with signs or hemisphere letters before or after the values. Without symbols, letters or commas,
+// 2 numbers are decimal degrees, 4 numbers are DDM and 6 numbers are DMS.
+//  p, err := geo.ParsePoint(`13°46'00.0"N 100°36'24.6"E`)
+//  p, err = geo.ParsePoint("N 13 46.0 E 100 36.41")
+//  p, err = geo.ParsePoint("13.766667, 100.606833")
+//  address, err := p.Reverse()
+func ParsePoint(s string) (p Point, err error) {
+	coords, err := parseCoordinates(s, 2)
+	if err != nil {
+		return
+	}
+	switch len(coords) {
+	case 0:
+		return p, &CoordinatesError{s, "no coordinates found"}
+	case 1:
+		return p, &CoordinatesError{s, "longitude is missing"}
+	case 2:
+	default:
+		return p, &CoordinatesError{s, "too many values, only latitude and longitude are expected"}
+	}
+	lat, lon := coords[0], coords[1]
+	if lat.hemisphere == 'E' || lat.hemisphere == 'W' || lon.hemisphere == 'N' || lon.hemisphere == 'S' {
+		lat, lon = lon, lat
+	}
+	if lat.hemisphere == 'E' || lat.hemisphere == 'W' {
+		return p, &CoordinatesError{s, "both coordinates are longitudes"}
+	}
+	if lon.hemisphere == 'N' || lon.hemisphere == 'S' {
+		return p, &CoordinatesError{s, "both coordinates are latitudes"}
+	}
+	if p.Lat, err = lat.degrees(s, 90, "latitude"); err != nil {
+		return
+	}
+	p.Lon, err = lon.degrees(s, 180, "longitude")
+	return
+}
+
+// ParseLatitude parses a latitude in any format accepted by ParsePoint.
+//  lat, err := geo.ParseLatitude(`13°46'00.0"N`)
+func ParseLatitude(s string) (float64, error) {
+	return parseCoordinate(s, "NS", 90, "latitude")
+}
+
+// ParseLongitude parses a longitude in any format accepted by ParsePoint.
+//  lon, err := geo.ParseLongitude("E 100 36.41")
+func ParseLongitude(s string) (float64, error) {
+	return parseCoordinate(s, "EW", 180, "longitude")
+}
+
+// Decimal returns the point in signed decimal degrees with precision decimals, latitude first.
+// A negative precision uses the smallest number of decimals needed to represent the point exactly.
+//  geo.Point{Lat: 13.766667, Lon: 100.606833}.Decimal(4) // 13.7667, 100.6068
+func (p Point) Decimal(precision int) string {
+	return formatDecimalDegrees(p.Lat, precision) + ", " + formatDecimalDegrees(p.Lon, precision)
+}
+
+// DMS returns the point in degrees, minutes and seconds with hemisphere letters, seconds with precision decimals.
+//  geo.Point{Lat: 13.766667, Lon: 100.606833}.DMS(1) // 13°46'00.0"N 100°36'24.6"E
+func (p Point) DMS(precision int) string {
+	return formatSexagesimal(p.Lat, "NS", 3, precision) + " " + formatSexagesimal(p.Lon, "EW", 3, precision)
+}
+
+// DDM returns the point in degrees and decimal minutes with hemisphere letters, minutes with precision decimals.
+//  geo.Point{Lat: 13.766667, Lon: 100.606833}.DDM(3) // 13°46.000'N 100°36.410'E
+func (p Point) DDM(precision int) string {
+	return formatSexagesimal(p.Lat, "NS", 2, precision) + " " + formatSexagesimal(p.Lon, "EW", 2, precision)
+}
+
+// parseCoordinate parses a single latitude or longitude, whose hemisphere letters are in hemispheres.
+func parseCoordinate(s, hemispheres string, max float64, name string) (float64, error) {
+	coords, err := parseCoordinates(s, 1)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case len(coords) == 0:
+		return 0, &CoordinatesError{s, "no " + name + " found"}
+	case len(coords) > 1:
+		return 0, &CoordinatesError{s, "too many values, a single " + name + " is expected"}
+	case coords[0].hemisphere != 0 && strings.IndexByte(hemispheres, coords[0].hemisphere) < 0:
+		return 0, &CoordinatesError{s, "hemisphere " + string(coords[0].hemisphere) + " is not valid for a " + name}
+	}
+	return coords[0].degrees(s, max, name)
+}
+
+// parseCoordinates splits the text in latitudes and longitudes, count is the number of coordinates expected.
+func parseCoordinates(s string, count int) ([]coordinate, error) {
+	tokens, err := coordinateTokens(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers without symbols, letters or commas are split in count coordinates of the same size
+	plain := len(tokens)%count == 0 && len(tokens) <= 3*count
+	for _, t := range tokens {
+		plain = plain && t.isNumber && t.unit < 0
+	}
+	if plain && len(tokens) > 0 {
+		size := len(tokens) / count
+		coords := make([]coordinate, count)
+		for i, t := range tokens {
+			c := &coords[i/size]
+			if i%size > 0 && t.signed {
+				return nil, &CoordinatesError{s, "unexpected sign in " + strconv.Quote(t.text)}
+			}
+			c.add(t)
+		}
+		return coords, nil
+	}
+
+	var coords []coordinate
+	var c coordinate
+	next := func() {
+		if len(c.values) > 0 || c.hemisphere != 0 {
+			coords = append(coords, c)
+		}
+		c = coordinate{}
+	}
+	for _, t := range tokens {
+		switch {
+		case t.isComma:
+			if len(c.values) == 0 && c.hemisphere != 0 {
+				return nil, &CoordinatesError{s, "hemisphere " + string(c.hemisphere) + " has no value"}
+			}
+			next()
+		case t.letter != 0:
+			if len(c.values) == 0 {
+				if c.hemisphere != 0 {
+					return nil, &CoordinatesError{s, "hemisphere " + string(c.hemisphere) + " has no value"}
+				}
+				c.hemisphere = t.letter
+				continue
+			}
+			if c.hemisphere != 0 {
+				// Hemisphere letter of the next coordinate
+				next()
+				c.hemisphere = t.letter
+				continue
+			}
+			c.hemisphere = t.letter
+			next()
+		default:
+			// The number starts the next coordinate if it can not be minutes or seconds of this one
+			pos := len(c.values)
+			if pos > 0 && (pos == 3 || c.fraction || t.signed || t.unit >= 0 && t.unit < pos ||
+				t.unit < 0 && (c.units || t.number >= 60)) {
+				next()
+				pos = 0
+			}
+			if t.unit > pos {
+				names := []string{"degrees", "minutes", "seconds"}
+				return nil, &CoordinatesError{s, names[t.unit] + " " + strconv.Quote(t.text) + " must follow " + names[t.unit-1]}
+			}
+			c.add(t)
+		}
+	}
+	if len(c.values) == 0 && c.hemisphere != 0 {
+		return nil, &CoordinatesError{s, "hemisphere " + string(c.hemisphere) + " has no value"}
+	}
+	next()
+	return coords, nil
+}
+
+// coordinateTokens splits the text in numbers with their symbols, hemisphere words and commas.
+func coordinateTokens(s string) (tokens []coordinateToken, err error) {
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == ',' || r == ';' || r == '/':
+			tokens = append(tokens, coordinateToken{text: string(r), isComma: true})
+			i++
+		case r == '+' || r == '-' || r == '−' || r == '.' || unicode.IsDigit(r):
+			start := i
+			if r == '+' || r == '-' || r == '−' {
+				i++
+			}
+			for i < len(runes) && (runes[i] == '.' || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			text := string(runes[start:i])
+			number := strings.Replace(text, "−", "-", 1)
+			t := coordinateToken{text: text, unit: -1, isNumber: true, signed: i > start && runes[start] != '.' && !unicode.IsDigit(runes[start])}
+			if t.number, err = strconv.ParseFloat(number, 64); err != nil || math.IsInf(t.number, 0) {
+				return nil, &CoordinatesError{s, strconv.Quote(text) + " is not a number"}
+			}
+			t.number = math.Abs(t.number)
+			// Symbol of the number
+			for i < len(runes) && unicode.IsSpace(runes[i]) && i+1 < len(runes) && coordinateUnit(runes[i+1]) >= 0 {
+				i++
+			}
+			if i < len(runes) {
+				if t.unit = coordinateUnit(runes[i]); t.unit >= 0 {
+					i++
+					// Seconds written with 2 single quotes
+					if t.unit == 1 && i < len(runes) && coordinateUnit(runes[i]) == 1 {
+						t.unit = 2
+						i++
+					}
+					t.text = string(runes[start:i])
+				}
+			}
+			tokens = append(tokens, t)
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			letter, ok := hemisphereWords[strings.ToUpper(word)]
+			if !ok {
+				return nil, &CoordinatesError{s, "unexpected " + strconv.Quote(word) + ", hemispheres are N, S, E or W"}
+			}
+			tokens = append(tokens, coordinateToken{text: word, letter: letter})
+		default:
+			return nil, &CoordinatesError{s, "unexpected " + strconv.Quote(string(r))}
+		}
+	}
+	return
+}
+
+// coordinateUnit returns 0 for degree symbols, 1 for minute symbols, 2 for second symbols and -1 otherwise.
+func coordinateUnit(r rune) int {
+	switch r {
+	case '°', 'º', '˚':
+		return 0
+	case '\'', '′', '’', '‘':
+		return 1
+	case '"', '″', '”', '“':
+		return 2
+	}
+	return -1
+}
+
+// add adds the number to the values of the coordinate.
+func (c *coordinate) add(t coordinateToken) {
+	if len(c.values) == 0 {
+		c.negative = t.signed && t.text[0] != '+'
+	}
+	c.values = append(c.values, t.number)
+	c.units = c.units || t.unit >= 0
+	c.fraction = strings.ContainsRune(t.text, '.')
+}
+
+// degrees returns the coordinate in decimal degrees after checking its values.
+func (c coordinate) degrees(s string, max float64, name string) (float64, error) {
+	if c.negative && c.hemisphere != 0 {
+		return 0, &CoordinatesError{s, name + " has both a minus sign and hemisphere " + string(c.hemisphere)}
+	}
+	if len(c.values) == 0 {
+		return 0, &CoordinatesError{s, name + " has no value"}
+	}
+	var deg float64
+	for i, v := range c.values {
+		if i > 0 && v >= 60 {
+			return 0, &CoordinatesError{s, []string{"", "minutes", "seconds"}[i] + " of " + name + " must be less than 60"}
+		}
+		deg += v / math.Pow(60, float64(i))
+	}
+	if deg > max {
+		return 0, &CoordinatesError{s, name + " must be between -" + formatFloat(max) + " and " + formatFloat(max)}
+	}
+	if c.negative || c.hemisphere == 'S' || c.hemisphere == 'W' {
+		deg = -deg
+	}
+	return deg, nil
+}
+
+// formatDecimalDegrees returns the degrees with precision decimals, without negative zero.
+func formatDecimalDegrees(deg float64, precision int) string {
+	s := strconv.FormatFloat(deg, 'f', precision, 64)
+	if strings.Trim(s, "-0.") == "" {
+		s = strings.TrimPrefix(s, "-")
+	}
+	return s
+}
+
+// formatSexagesimal returns the degrees as degrees and minutes (parts = 2) or degrees, minutes
+// and seconds (parts = 3) with precision decimals for the last part, followed by the hemisphere letter.
+func formatSexagesimal(deg float64, hemispheres string, parts, precision int) string {
+	if precision < 0 {
+		precision = 0
+	}
+	// We round the value in units of the last decimal, to carry 60 seconds to minutes and so on
+	unit := math.Pow(10, float64(precision))
+	total := math.Round(math.Abs(deg) * math.Pow(60, float64(parts-1)) * unit)
+	hemisphere := hemispheres[0]
+	if deg < 0 && total > 0 {
+		hemisphere = hemispheres[1]
+	}
+	last := math.Mod(total, 60*unit) / unit
+	rest := math.Floor(total / (60 * unit))
+	width := precision + 3
+	if precision == 0 {
+		width = 2
+	}
+	if parts == 2 {
+		return fmt.Sprintf("%.0f°%0*.*f'%c", rest, width, precision, last, hemisphere)
+	}
+	return fmt.Sprintf("%.0f°%02.0f'%0*.*f\"%c", math.Floor(rest/60), math.Mod(rest, 60), width, precision, last, hemisphere)
+}
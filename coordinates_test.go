@@ -0,0 +1,163 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParsePoint(t *testing.T) {
+	office := Point{dms(13, 46, 0), dms(100, 36, 24.6)}
+	tests := []struct {
+		s    string
+		want Point
+	}{
+		{`13°46'00.0"N 100°36'24.6"E`, office},
+		{`13° 46' 00.0" N, 100° 36' 24.6" E`, office},
+		{`13°46′00″N 100°36′24.6″E`, office},
+		{`13º46'0''N 100º36'24.6''E`, office},
+		{"13 46 0 N 100 36 24.6 E", office},
+		{"N 13 46.0 E 100 36.41", Point{dms(13, 46, 0), dms(100, 36.41, 0)}},
+		{"N13°46.000' E100°36.410'", Point{dms(13, 46, 0), dms(100, 36.41, 0)}},
+		{"13 46.0 100 36.41", Point{dms(13, 46, 0), dms(100, 36.41, 0)}},
+		{"13 46 0 100 36 24.6", office},
+		{"13.7665217, 100.6068431", Point{13.7665217, 100.6068431}},
+		{"13.7665217 100.6068431", Point{13.7665217, 100.6068431}},
+		{"13.7665217;100.6068431", Point{13.7665217, 100.6068431}},
+		{"13.7665217N 100.6068431E", Point{13.7665217, 100.6068431}},
+		{"13.7665217° N, 100.6068431° E", Point{13.7665217, 100.6068431}},
+		{"-33.8688, 151.2093", Point{-33.8688, 151.2093}},
+		{"+48.8566 +2.3522", Point{48.8566, 2.3522}},
+		{"−41.3 174.8", Point{-41.3, 174.8}},
+		{"33°52'7.7\"S 151°12'33.5\"E", Point{-dms(33, 52, 7.7), dms(151, 12, 33.5)}},
+		{"S 33 52.128 W 70 40.0", Point{-dms(33, 52.128, 0), -dms(70, 40, 0)}},
+		{"33 south 70 west", Point{-33, -70}},
+		// Longitude first with hemisphere letters
+		{`100°36'24.6"E 13°46'00.0"N`, office},
+		{"W 70 40.0 S 33 52.128", Point{-dms(33, 52.128, 0), -dms(70, 40, 0)}},
+		{"13 100", Point{13, 100}},
+		{"13 46 100", Point{dms(13, 46, 0), 100}},
+		{"0 0", Point{}},
+		{"90 -180", Point{90, -180}},
+		{".5 .25", Point{0.5, 0.25}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePoint(tt.s)
+		if err != nil {
+			t.Errorf("ParsePoint(%q) error = %v", tt.s, err)
+			continue
+		}
+		if math.Abs(got.Lat-tt.want.Lat) > 1e-12 || math.Abs(got.Lon-tt.want.Lon) > 1e-12 {
+			t.Errorf("ParsePoint(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParsePoint_Invalid(t *testing.T) {
+	tests := []struct {
+		s      string
+		reason string
+	}{
+		{"", "no coordinates found"},
+		{"13.76", "longitude is missing"},
+		{"13.76, 100.5, 12", "too many values, only latitude and longitude are expected"},
+		{"13 -46 100 36", `unexpected sign in "-46"`},
+		{"91 100", "latitude must be between -90 and 90"},
+		{"13 181", "longitude must be between -180 and 180"},
+		{"13°60.5'N 100°E", "minutes of latitude must be less than 60"},
+		{`13°46'61"N 100°E`, "seconds of latitude must be less than 60"},
+		{"13N 14S", "both coordinates are latitudes"},
+		{"100E 100W", "both coordinates are longitudes"},
+		{"-13 S 100 E", "latitude has both a minus sign and hemisphere S"},
+		{"N, 100 E", "hemisphere N has no value"},
+		{"13 N 100 E W", "hemisphere W has no value"},
+		{`13° 30" N 100 E`, `seconds "30\"" must follow minutes`},
+		{"13.5° 30' N 100 E", `minutes "30'" must follow degrees`},
+		{"13.5 NE 100", `unexpected "NE", hemispheres are N, S, E or W`},
+		{"13.5 # 100", `unexpected "#"`},
+		{"13.5.1 100", `"13.5.1" is not a number`},
+		{"13.5 - 100", `"-" is not a number`},
+	}
+	for _, tt := range tests {
+		_, err := ParsePoint(tt.s)
+		var e *CoordinatesError
+		if !errors.As(err, &e) || !errors.Is(err, ErrInvalidCoordinates) {
+			t.Errorf("ParsePoint(%q) error = %v", tt.s, err)
+			continue
+		}
+		if e.Reason != tt.reason || e.Input != tt.s {
+			t.Errorf("ParsePoint(%q) reason = %q, want %q", tt.s, e.Reason, tt.reason)
+		}
+	}
+	_, err := ParsePoint("91 100")
+	if err.Error() != `Invalid coordinates "91 100": latitude must be between -90 and 90` {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestParseLatitude(t *testing.T) {
+	tests := []struct {
+		s       string
+		lat     bool
+		want    float64
+		wantErr bool
+	}{
+		{`13°46'00.0"N`, true, dms(13, 46, 0), false},
+		{"S 33 52.128", true, -dms(33, 52.128, 0), false},
+		{"-33.8688", true, -33.8688, false},
+		{"E 100 36.41", false, dms(100, 36.41, 0), false},
+		{"100.5W", false, -100.5, false},
+		{"180", false, 180, false},
+		{"13 E", true, 0, true},
+		{"100 N", false, 0, true},
+		{"95", true, 0, true},
+		{"13 46", true, dms(13, 46, 0), false},
+		{"13 46 1 2", true, 0, true},
+		{"", false, 0, true},
+	}
+	for _, tt := range tests {
+		parse := ParseLongitude
+		if tt.lat {
+			parse = ParseLatitude
+		}
+		got, err := parse(tt.s)
+		if (err != nil) != tt.wantErr || math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("parse(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestPoint_Format(t *testing.T) {
+	tests := []struct {
+		p         Point
+		precision int
+		decimal   string
+		dms       string
+		ddm       string
+	}{
+		{Point{13.766667, 100.606833}, 1, "13.8, 100.6", `13°46'00.0"N 100°36'24.6"E`, `13°46.0'N 100°36.4'E`},
+		{Point{13.766667, 100.606833}, 3, "13.767, 100.607", `13°46'00.001"N 100°36'24.599"E`, `13°46.000'N 100°36.410'E`},
+		{Point{-33.8688, -70.5}, 0, "-34, -70", `33°52'08"S 70°30'00"W`, `33°52'S 70°30'W`},
+		{Point{-0.00001, 0}, 2, "0.00, 0.00", `0°00'00.04"S 0°00'00.00"E`, `0°00.00'N 0°00.00'E`},
+		// Rounding carries seconds to minutes and degrees
+		{Point{59.99999999, 179.9999999}, 1, "60.0, 180.0", `60°00'00.0"N 180°00'00.0"E`, `60°00.0'N 180°00.0'E`},
+		{Point{90, -180}, -1, "90, -180", `90°00'00"N 180°00'00"W`, `90°00'N 180°00'W`},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Decimal(tt.precision); got != tt.decimal {
+			t.Errorf("%v.Decimal(%v) = %v, want %v", tt.p, tt.precision, got, tt.decimal)
+		}
+		if got := tt.p.DMS(tt.precision); got != tt.dms {
+			t.Errorf("%v.DMS(%v) = %v, want %v", tt.p, tt.precision, got, tt.dms)
+		}
+		if got := tt.p.DDM(tt.precision); got != tt.ddm {
+			t.Errorf("%v.DDM(%v) = %v, want %v", tt.p, tt.precision, got, tt.ddm)
+		}
+		// Formatted points can be parsed back
+		for _, s := range []string{tt.p.Decimal(-1), tt.p.DMS(3), tt.p.DDM(5)} {
+			if p, err := ParsePoint(s); err != nil || p.Distance(tt.p) > 0.1 {
+				t.Errorf("ParsePoint(%q) = %v, %v, want %v", s, p, err, tt.p)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package geo
+
+import "math"
+
+// PathPosition is the projection of a point on a path, such as a GPS fix on a planned route.
+type PathPosition struct {
+	Point    Point   // Point is the closest point of the path
+	Index    int     // Index of the segment of the path holding the closest point: between points Index and Index+1
+	Fraction float64 // Fraction of the segment before the closest point, from 0 to 1
+	Distance float64 // Distance in meters between the point and the path
+	Along    float64 // Along is the distance in meters from the start of the path to the closest point
+}
+
+// CrossTrackDistance returns the distance in meters between the point and the great circle going through start and end.
+// The distance is positive when the point is on the right of the path and negative on the left.
+// Details https://www.movable-type.co.uk/scripts/latlong.html#cross-track
+//  geo.CrossTrackDistance(fix, routeStart, routeEnd)
+func CrossTrackDistance(p, start, end Point) float64 {
+	delta13 := start.Distance(p) / EarthRadius
+	theta13 := toRadians(start.Bearing(p))
+	theta12 := toRadians(start.Bearing(end))
+	return math.Asin(math.Sin(delta13)*math.Sin(theta13-theta12)) * EarthRadius
+}
+
+// AlongTrackDistance returns the distance in meters from start to the projection of the point
+// on the great circle going through start and end. It is negative if the projection is before start.
+//  geo.AlongTrackDistance(fix, routeStart, routeEnd)
+func AlongTrackDistance(p, start, end Point) float64 {
+	delta13 := start.Distance(p) / EarthRadius
+	theta13 := toRadians(start.Bearing(p))
+	theta12 := toRadians(start.Bearing(end))
+	deltaXT := math.Asin(math.Sin(delta13) * math.Sin(theta13-theta12))
+
+	cos := math.Cos(delta13) / math.Cos(deltaXT)
+	along := math.Acos(math.Max(-1, math.Min(1, cos))) * EarthRadius
+	if math.Cos(theta13-theta12) < 0 {
+		along = -along
+	}
+	return along
+}
+
+// ClosestPointOnSegment returns the point of the segment (great circle arc) from start to end closest to p,
+// and the fraction of the segment before this point.
+//  closest, fraction := geo.ClosestPointOnSegment(fix, routeStart, routeEnd)
+func ClosestPointOnSegment(p, start, end Point) (closest Point, fraction float64) {
+	length := start.Distance(end)
+	if length == 0 {
+		return start, 0
+	}
+	along := AlongTrackDistance(p, start, end)
+	switch {
+	case along <= 0:
+		return start, 0
+	case along >= length:
+		return end, 1
+	}
+	fraction = along / length
+	closest.Lat, closest.Lon = Intermediate(start.Lat, start.Lon, end.Lat, end.Lon, fraction)
+	return
+}
+
+// ClosestPoint returns the position on the line closest to the point.
+// For an empty line the position is the zero value.
+//  pos := route.ClosestPoint(fix)
+//  fmt.Println(pos.Distance, "meters from the route,", pos.Along, "meters from the start")
+func (l LineString) ClosestPoint(p Point) (pos PathPosition) {
+	if len(l) == 0 {
+		return
+	}
+	pos = PathPosition{Point: l[0], Distance: p.Distance(l[0])}
+	var along float64
+	for i := 1; i < len(l); i++ {
+		closest, fraction := ClosestPointOnSegment(p, l[i-1], l[i])
+		length := l[i-1].Distance(l[i])
+		if d := p.Distance(closest); d < pos.Distance {
+			pos = PathPosition{
+				Point:    closest,
+				Index:    i - 1,
+				Fraction: fraction,
+				Distance: d,
+				Along:    along + fraction*length,
+			}
+		}
+		along += length
+	}
+	return
+}
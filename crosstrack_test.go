@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCrossTrackDistance(t *testing.T) {
+	degree := EarthRadius * math.Pi / 180
+	start, end := Point{0, 0}, Point{0, 10}
+	tests := []struct {
+		name  string
+		p     Point
+		cross float64
+		along float64
+	}{
+		{name: "Left", p: Point{1, 5}, cross: -degree, along: 5 * degree},
+		{name: "Right", p: Point{-2, 3}, cross: 2 * degree, along: 3 * degree},
+		{name: "On path", p: Point{0, 7}, cross: 0, along: 7 * degree},
+		{name: "Behind", p: Point{1, -1}, cross: -degree, along: -Distance(0, 0, 0, -1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CrossTrackDistance(tt.p, start, end); math.Abs(got-tt.cross) > 1e-6 {
+				t.Errorf("CrossTrackDistance() = %v, want %v", got, tt.cross)
+			}
+			if got := AlongTrackDistance(tt.p, start, end); math.Abs(got-tt.along) > 1e-6 {
+				t.Errorf("AlongTrackDistance() = %v, want %v", got, tt.along)
+			}
+		})
+	}
+}
+
+func TestClosestPointOnSegment(t *testing.T) {
+	start, end := Point{13.7665217, 100.6068431}, Point{13.7199345, 100.5197898}
+	tests := []struct {
+		name     string
+		p        Point
+		want     Point
+		fraction float64
+	}{
+		{name: "Before start", p: Point{13.78, 100.62}, want: start, fraction: 0},
+		{name: "After end", p: Point{13.7, 100.5}, want: end, fraction: 1},
+		{name: "On segment", p: start, want: start, fraction: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fraction := ClosestPointOnSegment(tt.p, start, end)
+			if !got.Equal(tt.want, 1e-6) || math.Abs(fraction-tt.fraction) > 1e-9 {
+				t.Errorf("ClosestPointOnSegment() = %v, %v, want %v, %v", got, fraction, tt.want, tt.fraction)
+			}
+		})
+	}
+
+	// A point at the side of the middle of the segment projects on the middle
+	mid := Point{}
+	mid.Lat, mid.Lon = Midpoint(start.Lat, start.Lon, end.Lat, end.Lon)
+	side := mid.Destination(mid.Bearing(end)+90, 500)
+	got, fraction := ClosestPointOnSegment(side, start, end)
+	if !got.Equal(mid, 0.01) || math.Abs(fraction-0.5) > 1e-6 {
+		t.Errorf("ClosestPointOnSegment() = %v, %v, want %v, 0.5", got, fraction, mid)
+	}
+	if d := side.Distance(got); math.Abs(d-500) > 0.01 {
+		t.Errorf("ClosestPointOnSegment() is %v meters away, want 500", d)
+	}
+}
+
+func TestLineString_ClosestPoint(t *testing.T) {
+	route := LineString{{0, 0}, {0, 1}, {1, 1}, {1, 2}}
+	pos := route.ClosestPoint(Point{0.5, 1.1})
+	if pos.Index != 1 || math.Abs(pos.Fraction-0.5) > 1e-3 {
+		t.Errorf("ClosestPoint() = %+v", pos)
+	}
+	if want := Distance(0, 0, 0, 1) + pos.Fraction*Distance(0, 1, 1, 1); math.Abs(pos.Along-want) > 1e-6 {
+		t.Errorf("ClosestPoint() along = %v, want %v", pos.Along, want)
+	}
+	if want := math.Abs(CrossTrackDistance(Point{0.5, 1.1}, Point{0, 1}, Point{1, 1})); math.Abs(pos.Distance-want) > 1e-6 {
+		t.Errorf("ClosestPoint() distance = %v, want %v", pos.Distance, want)
+	}
+
+	pos = route.ClosestPoint(Point{2, 3})
+	if pos.Index != 2 || pos.Fraction != 1 || pos.Point != (Point{1, 2}) || math.Abs(pos.Along-route.Length()) > 1e-6 {
+		t.Errorf("ClosestPoint() = %+v", pos)
+	}
+
+	if pos := (LineString{{1, 1}}).ClosestPoint(Point{2, 2}); pos.Point != (Point{1, 1}) || pos.Index != 0 {
+		t.Errorf("ClosestPoint() = %+v", pos)
+	}
+}
@@ -0,0 +1,151 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type (
+	// CRS is a coordinate reference system converting its coordinates to and from WGS84 points.
+	// Coordinates are x, y in the GIS order: longitude and latitude in degrees for geographic systems,
+	// easting and northing in meters for projected systems. Heights are not carried between datums.
+	CRS interface {
+		ToWGS84(x, y float64) Point
+		FromWGS84(p Point) (x, y float64)
+	}
+
+	// Projection converts points of the ellipsoid of a datum to easting and northing in meters and back.
+	// TransverseMercator and PseudoMercator are projections.
+	Projection interface {
+		Forward(p Point) (easting, northing float64)
+		Inverse(easting, northing float64) Point
+	}
+
+	// GeographicCRS is a coordinate reference system of longitudes and latitudes on a datum.
+	GeographicCRS struct {
+		Name  string
+		Datum Datum
+	}
+
+	// ProjectedCRS is a coordinate reference system of eastings and northings projected from a datum.
+	// The projection must use the ellipsoid of the datum.
+	ProjectedCRS struct {
+		Name       string
+		Datum      Datum
+		Projection Projection
+	}
+
+	// PseudoMercator is the spherical Mercator projection of WGS84 points used by web maps, EPSG:3857.
+	PseudoMercator struct{}
+)
+
+// ErrUnknownEPSG is returned for EPSG codes missing from the registry.
+var ErrUnknownEPSG = errors.New("Unknown EPSG code")
+
+// epsgRegistry holds the coordinate reference systems by EPSG code, UTM zones are added on the fly.
+var epsgRegistry = struct {
+	sync.RWMutex
+	crs map[int]CRS
+}{crs: map[int]CRS{
+	4326:  GeographicCRS{Name: "WGS 84", Datum: WGS84Datum},
+	4240:  GeographicCRS{Name: "Indian 1975", Datum: Indian1975},
+	3857:  ProjectedCRS{Name: "WGS 84 / Pseudo-Mercator", Datum: WGS84Datum, Projection: PseudoMercator{}},
+	24047: ProjectedCRS{Name: "Indian 1975 / UTM zone 47N", Datum: Indian1975, Projection: indian1975UTM(47)},
+	24048: ProjectedCRS{Name: "Indian 1975 / UTM zone 48N", Datum: Indian1975, Projection: indian1975UTM(48)},
+}}
+
+// indian1975UTM returns the projection of a northern UTM zone on the ellipsoid of Indian 1975.
+func indian1975UTM(zone int) TransverseMercator {
+	tm := UTMProjection(zone, true)
+	tm.Ellipsoid = Indian1975.Ellipsoid
+	return tm
+}
+
+// EPSG returns the coordinate reference system of an EPSG code: 4326 (WGS84), 3857 (Web Mercator),
+// 32601 to 32660 and 32701 to 32760 (WGS84 UTM zones), 4240 (Indian 1975), 24047 and 24048
+// (Indian 1975 UTM zones of Thailand) and the systems added by RegisterEPSG.
+// Details https://epsg.io
+//  crs, err := geo.EPSG(24047)
+func EPSG(code int) (CRS, error) {
+	epsgRegistry.RLock()
+	crs, ok := epsgRegistry.crs[code]
+	epsgRegistry.RUnlock()
+	if ok {
+		return crs, nil
+	}
+	if code > 32600 && code <= 32660 {
+		zone := code - 32600
+		return ProjectedCRS{Name: fmt.Sprintf("WGS 84 / UTM zone %dN", zone), Datum: WGS84Datum, Projection: UTMProjection(zone, true)}, nil
+	}
+	if code > 32700 && code <= 32760 {
+		zone := code - 32700
+		return ProjectedCRS{Name: fmt.Sprintf("WGS 84 / UTM zone %dS", zone), Datum: WGS84Datum, Projection: UTMProjection(zone, false)}, nil
+	}
+	return nil, ErrUnknownEPSG
+}
+
+// RegisterEPSG adds a coordinate reference system to the registry, or replaces an existing one.
+//  geo.RegisterEPSG(4269, geo.GeographicCRS{Name: "NAD83", Datum: geo.Datum{Name: "NAD83", Ellipsoid: geo.GRS80}})
+func RegisterEPSG(code int, crs CRS) {
+	epsgRegistry.Lock()
+	epsgRegistry.crs[code] = crs
+	epsgRegistry.Unlock()
+}
+
+// EPSGCodes returns the sorted EPSG codes of the registry, without the UTM zones of WGS84.
+func EPSGCodes() []int {
+	epsgRegistry.RLock()
+	codes := make([]int, 0, len(epsgRegistry.crs))
+	for code := range epsgRegistry.crs {
+		codes = append(codes, code)
+	}
+	epsgRegistry.RUnlock()
+	sort.Ints(codes)
+	return codes
+}
+
+// Transform converts coordinates from a coordinate reference system to another one through WGS84.
+//  from, _ := geo.EPSG(24047)
+//  to, _ := geo.EPSG(32647)
+//  e, n := geo.Transform(from, to, 662000, 1521000)
+func Transform(from, to CRS, x, y float64) (float64, float64) {
+	return to.FromWGS84(from.ToWGS84(x, y))
+}
+
+// ToWGS84 returns the WGS84 point at the longitude x and latitude y.
+func (c GeographicCRS) ToWGS84(x, y float64) Point {
+	p, _ := c.Datum.ToWGS84(Point{Lat: y, Lon: x}, 0)
+	return p
+}
+
+// FromWGS84 returns the longitude and latitude of the WGS84 point.
+func (c GeographicCRS) FromWGS84(p Point) (x, y float64) {
+	p, _ = c.Datum.FromWGS84(p, 0)
+	return p.Lon, p.Lat
+}
+
+// ToWGS84 returns the WGS84 point at the easting x and northing y.
+//  crs, _ := geo.EPSG(32647)
+//  p := crs.ToWGS84(662366.598, 1521280.655)
+func (c ProjectedCRS) ToWGS84(x, y float64) Point {
+	p, _ := c.Datum.ToWGS84(c.Projection.Inverse(x, y), 0)
+	return p
+}
+
+// FromWGS84 returns the easting and northing of the WGS84 point.
+func (c ProjectedCRS) FromWGS84(p Point) (x, y float64) {
+	p, _ = c.Datum.FromWGS84(p, 0)
+	return c.Projection.Forward(p)
+}
+
+// Forward returns the EPSG:3857 coordinates of the point in meters, like Point.WebMercator.
+func (PseudoMercator) Forward(p Point) (easting, northing float64) {
+	return p.WebMercator()
+}
+
+// Inverse returns the point at the EPSG:3857 coordinates in meters, like WebMercatorPoint.
+func (PseudoMercator) Inverse(easting, northing float64) Point {
+	return WebMercatorPoint(easting, northing)
+}
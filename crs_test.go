@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEPSG(t *testing.T) {
+	bangkok := Point{13.7563, 100.5018}
+	tests := []struct {
+		code int
+		x, y float64
+	}{
+		{4326, 100.5018, 13.7563},
+		{3857, 11187809.200, 1546272.215},
+		{32647, 662366.598, 1521280.655},
+		{32648, 13281.777, 1525324.618},
+		{32747, 662366.598, 11521280.655},
+	}
+	for _, tt := range tests {
+		crs, err := EPSG(tt.code)
+		if err != nil {
+			t.Errorf("EPSG(%v) error = %v", tt.code, err)
+			continue
+		}
+		if x, y := crs.FromWGS84(bangkok); math.Abs(x-tt.x) > 1e-2 || math.Abs(y-tt.y) > 1e-2 {
+			t.Errorf("EPSG(%v).FromWGS84() = %v, %v, want %v, %v", tt.code, x, y, tt.x, tt.y)
+		}
+		if p := crs.ToWGS84(tt.x, tt.y); p.Distance(bangkok) > 1e-2 {
+			t.Errorf("EPSG(%v).ToWGS84() = %v, want %v", tt.code, p, bangkok)
+		}
+	}
+
+	for _, code := range []int{0, 32600, 32661, 32700, 32761, 2000} {
+		if _, err := EPSG(code); err != ErrUnknownEPSG {
+			t.Errorf("EPSG(%v) error = %v", code, err)
+		}
+	}
+	if crs, _ := EPSG(32601); crs.(ProjectedCRS).Name != "WGS 84 / UTM zone 1N" {
+		t.Errorf("EPSG(32601) name = %v", crs.(ProjectedCRS).Name)
+	}
+}
+
+func TestEPSG_Indian1975(t *testing.T) {
+	indian, _ := EPSG(24047)
+	utm, _ := EPSG(32647)
+	geographic, _ := EPSG(4240)
+
+	// The projection of the point on the Everest ellipsoid matches a UTM projection of Indian 1975 coordinates
+	p, _ := Indian1975.FromWGS84(Point{13.7563, 100.5018}, 0)
+	tm := UTMProjection(47, true)
+	tm.Ellipsoid = Everest1830
+	e, n := tm.Forward(p)
+	x, y := Transform(utm, indian, 662366.598, 1521280.655)
+	if math.Abs(x-e) > 1e-3 || math.Abs(y-n) > 1e-3 {
+		t.Errorf("Transform(32647, 24047) = %v, %v, want %v, %v", x, y, e, n)
+	}
+	// Heights are dropped on the way through WGS84, they move points by a few millimeters
+	if lon, lat := Transform(indian, geographic, x, y); math.Abs(lon-p.Lon) > 1e-7 || math.Abs(lat-p.Lat) > 1e-7 {
+		t.Errorf("Transform(24047, 4240) = %v, %v, want %v", lon, lat, p)
+	}
+	if x, y := Transform(indian, utm, x, y); math.Abs(x-662366.598) > 1e-2 || math.Abs(y-1521280.655) > 1e-2 {
+		t.Errorf("Transform(24047, 32647) = %v, %v", x, y)
+	}
+}
+
+func TestRegisterEPSG(t *testing.T) {
+	nad83 := GeographicCRS{Name: "NAD83", Datum: Datum{Name: "NAD83", Ellipsoid: GRS80}}
+	RegisterEPSG(4269, nad83)
+	defer func() {
+		epsgRegistry.Lock()
+		delete(epsgRegistry.crs, 4269)
+		epsgRegistry.Unlock()
+	}()
+
+	crs, err := EPSG(4269)
+	if err != nil || crs != nad83 {
+		t.Errorf("EPSG(4269) = %v, %v", crs, err)
+	}
+	codes := EPSGCodes()
+	want := []int{3857, 4240, 4269, 4326, 24047, 24048}
+	if len(codes) != len(want) {
+		t.Fatalf("EPSGCodes() = %v, want %v", codes, want)
+	}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("EPSGCodes() = %v, want %v", codes, want)
+			break
+		}
+	}
+}
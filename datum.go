@@ -0,0 +1,153 @@
+package geo
+
+import "math"
+
+type (
+	// Helmert is a 7 parameter transformation of geocentric coordinates,
+	// with the position vector rotation convention (EPSG method 9606).
+	// Details https://en.wikipedia.org/wiki/Helmert_transformation
+	Helmert struct {
+		TX, TY, TZ float64 // Translations in meters
+		RX, RY, RZ float64 // Rotations in arc-seconds
+		S          float64 // Scale difference in parts per million
+	}
+
+	// Datum is a geodetic datum: a reference ellipsoid and the Helmert transformation
+	// of its geocentric coordinates to WGS84.
+	Datum struct {
+		Name      string
+		Ellipsoid Ellipsoid
+		Shift     Helmert // Shift transforms geocentric coordinates of the datum to WGS84
+	}
+)
+
+// Geodetic datums with their transformation to WGS84.
+var (
+	WGS84Datum = Datum{Name: "WGS 84", Ellipsoid: WGS84}
+	// Indian1975 is the datum of old maps of Thailand, with the translations to WGS84 for Thailand
+	// of the NIMA TR8350.2 report, accurate to about 10 meters. Copy the datum with the Helmert
+	// parameters of the survey of the data for a better accuracy.
+	Indian1975 = Datum{
+		Name:      "Indian 1975",
+		Ellipsoid: Everest1830,
+		Shift:     Helmert{TX: 209, TY: 818, TZ: 290},
+	}
+)
+
+// arcSecond is an arc-second in radians.
+const arcSecond = math.Pi / (180 * 3600)
+
+// E2 returns the square of the first eccentricity of the ellipsoid.
+func (e Ellipsoid) E2() float64 {
+	return e.F * (2 - e.F)
+}
+
+// Geocentric returns the geocentric cartesian coordinates in meters of the point at height h
+// in meters above the ellipsoid. Z points to the north pole and X to longitude 0.
+//  x, y, z := geo.WGS84.Geocentric(geo.Point{Lat: 13.7563, Lon: 100.5018}, 0)
+func (e Ellipsoid) Geocentric(p Point, h float64) (x, y, z float64) {
+	e2 := e.E2()
+	lat, lon := toRadians(p.Lat), toRadians(p.Lon)
+	sinLat, cosLat := math.Sincos(lat)
+	// Radius of curvature in the prime vertical
+	n := e.A / math.Sqrt(1-e2*sinLat*sinLat)
+	x = (n + h) * cosLat * math.Cos(lon)
+	y = (n + h) * cosLat * math.Sin(lon)
+	z = (n*(1-e2) + h) * sinLat
+	return
+}
+
+// Geodetic returns the point and its height in meters above the ellipsoid at geocentric cartesian
+// coordinates. The latitude of Bowring's formula is refined to the double precision.
+func (e Ellipsoid) Geodetic(x, y, z float64) (p Point, h float64) {
+	e2 := e.E2()
+	b := e.B()
+	// Square of the second eccentricity
+	ep2 := e2 / (1 - e2)
+	r := math.Hypot(x, y)
+	sinTheta, cosTheta := math.Sincos(math.Atan2(z*e.A, r*b))
+	lat := math.Atan2(z+ep2*b*sinTheta*sinTheta*sinTheta, r-e2*e.A*cosTheta*cosTheta*cosTheta)
+	sinLat, cosLat := math.Sincos(lat)
+	// Refine the latitude to the double precision
+	for i := 0; i < 2; i++ {
+		n := e.A / math.Sqrt(1-e2*sinLat*sinLat)
+		lat = math.Atan2(z+e2*n*sinLat, r)
+		sinLat, cosLat = math.Sincos(lat)
+	}
+	h = r*cosLat + z*sinLat - e.A*math.Sqrt(1-e2*sinLat*sinLat)
+	p = Point{Lat: toDegrees(lat), Lon: toDegrees(math.Atan2(y, x))}
+	return
+}
+
+// Transform applies the transformation to geocentric coordinates in meters.
+func (t Helmert) Transform(x, y, z float64) (x2, y2, z2 float64) {
+	s := 1 + t.S*1e-6
+	rx, ry, rz := t.RX*arcSecond, t.RY*arcSecond, t.RZ*arcSecond
+	x2 = s*(x-rz*y+ry*z) + t.TX
+	y2 = s*(rz*x+y-rx*z) + t.TY
+	z2 = s*(-ry*x+rx*y+z) + t.TZ
+	return
+}
+
+// Inverse applies the reverse transformation to geocentric coordinates in meters,
+// so that Inverse(Transform(x, y, z)) returns x, y, z.
+func (t Helmert) Inverse(x, y, z float64) (x2, y2, z2 float64) {
+	s := 1 + t.S*1e-6
+	rx, ry, rz := t.RX*arcSecond, t.RY*arcSecond, t.RZ*arcSecond
+	x, y, z = (x-t.TX)/s, (y-t.TY)/s, (z-t.TZ)/s
+	// Rotations are tiny, the fixed point iteration converges to the double precision in a few steps
+	x2, y2, z2 = x, y, z
+	for i := 0; i < 3; i++ {
+		x2, y2, z2 = x+rz*y2-ry*z2, y-rz*x2+rx*z2, z+ry*x2-rx*y2
+	}
+	return
+}
+
+// ToWGS84 returns the WGS84 point and height of a point at height h in meters above the ellipsoid of the datum.
+//  p, _ := geo.Indian1975.ToWGS84(geo.Point{Lat: 13.7556, Lon: 100.5050}, 0)
+func (d Datum) ToWGS84(p Point, h float64) (Point, float64) {
+	if d.Shift == (Helmert{}) && d.Ellipsoid == WGS84 {
+		return p, h
+	}
+	x, y, z := d.Ellipsoid.Geocentric(p, h)
+	return WGS84.Geodetic(d.Shift.Transform(x, y, z))
+}
+
+// FromWGS84 returns the point and height above the ellipsoid of the datum of a WGS84 point at height h in meters.
+func (d Datum) FromWGS84(p Point, h float64) (Point, float64) {
+	if d.Shift == (Helmert{}) && d.Ellipsoid == WGS84 {
+		return p, h
+	}
+	x, y, z := WGS84.Geocentric(p, h)
+	return d.Ellipsoid.Geodetic(d.Shift.Inverse(x, y, z))
+}
+
+// Molodensky shifts a point at height h in meters from a datum on the ellipsoid from to a datum on
+// the ellipsoid to, with the translations dx, dy, dz in meters between their geocentric coordinates.
+// The standard Molodensky formulas work on geodetic coordinates directly, they are accurate to about
+// a meter for the translations of most datums.
+// Details https://en.wikipedia.org/wiki/Geographic_coordinate_conversion#Molodensky_transformation
+//  p, h := geo.Molodensky(geo.Point{Lat: 13.7531, Lon: 100.5048}, 0, geo.Everest1830, geo.WGS84, 209, 818, 290)
+func Molodensky(p Point, h float64, from, to Ellipsoid, dx, dy, dz float64) (Point, float64) {
+	a := from.A
+	b := from.B()
+	e2 := from.E2()
+	da := to.A - from.A
+	df := to.F - from.F
+
+	lat, lon := toRadians(p.Lat), toRadians(p.Lon)
+	sinLat, cosLat := math.Sincos(lat)
+	sinLon, cosLon := math.Sincos(lon)
+	w := math.Sqrt(1 - e2*sinLat*sinLat)
+	// Radii of curvature in the meridian and in the prime vertical
+	m := a * (1 - e2) / (w * w * w)
+	n := a / w
+
+	dLat := (-dx*sinLat*cosLon - dy*sinLat*sinLon + dz*cosLat +
+		da*n*e2*sinLat*cosLat/a +
+		df*(m*a/b+n*b/a)*sinLat*cosLat) / (m + h)
+	dLon := (-dx*sinLon + dy*cosLon) / ((n + h) * cosLat)
+	dh := dx*cosLat*cosLon + dy*cosLat*sinLon + dz*sinLat - da*a/n + df*b/a*n*sinLat*sinLat
+
+	return Point{Lat: toDegrees(lat + dLat), Lon: normalizeLongitude(toDegrees(lon + dLon))}, h + dh
+}
@@ -0,0 +1,73 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEllipsoid_Geocentric(t *testing.T) {
+	// Example of the EPSG guidance note 7-2
+	p := Point{dms(53, 48, 33.82), dms(2, 7, 46.38)}
+	x, y, z := WGS84.Geocentric(p, 73)
+	if math.Abs(x-3771793.968) > 1e-3 || math.Abs(y-140253.342) > 1e-3 || math.Abs(z-5124304.349) > 1e-3 {
+		t.Errorf("Geocentric() = %v, %v, %v, want 3771793.968, 140253.342, 5124304.349", x, y, z)
+	}
+	q, h := WGS84.Geodetic(x, y, z)
+	if q.Distance(p) > 1e-6 || math.Abs(h-73) > 1e-6 {
+		t.Errorf("Geodetic() = %v, %v, want %v, 73", q, h, p)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{Lat: r.Float64()*180 - 90, Lon: r.Float64()*360 - 180}
+		h := r.Float64()*10000 - 500
+		q, qh := Everest1830.Geodetic(Everest1830.Geocentric(p, h))
+		if q.Distance(p) > 1e-4 || math.Abs(qh-h) > 1e-4 {
+			t.Fatalf("Geodetic(Geocentric(%v, %v)) = %v, %v", p, h, q, qh)
+		}
+	}
+}
+
+func TestHelmert(t *testing.T) {
+	// WGS72 to WGS84, example of the EPSG guidance note 7-2
+	wgs72 := Helmert{TZ: 4.5, RZ: 0.554, S: 0.219}
+	x, y, z := wgs72.Transform(3657660.66, 255768.55, 5201382.11)
+	if math.Abs(x-3657660.77) > 1e-2 || math.Abs(y-255778.43) > 1e-2 || math.Abs(z-5201387.75) > 1e-2 {
+		t.Errorf("Transform() = %v, %v, %v, want 3657660.77, 255778.43, 5201387.75", x, y, z)
+	}
+
+	h := Helmert{TX: 446.448, TY: -125.157, TZ: 542.06, RX: 0.15, RY: 0.247, RZ: 0.842, S: -20.489}
+	x, y, z = h.Inverse(h.Transform(3657660.66, 255768.55, 5201382.11))
+	if math.Abs(x-3657660.66) > 1e-6 || math.Abs(y-255768.55) > 1e-6 || math.Abs(z-5201382.11) > 1e-6 {
+		t.Errorf("Inverse(Transform()) = %v, %v, %v", x, y, z)
+	}
+}
+
+func TestMolodensky(t *testing.T) {
+	// WGS84 to ED50, example of the EPSG guidance note 7-2
+	p, h := Molodensky(Point{dms(53, 48, 33.82), dms(2, 7, 46.38)}, 73, WGS84, International1924, 84.87, 96.49, 116.95)
+	want := Point{dms(53, 48, 36.565), dms(2, 7, 51.477)}
+	if math.Abs(p.Lat-want.Lat) > 1e-3/3600 || math.Abs(p.Lon-want.Lon) > 1e-3/3600 || math.Abs(h-28.02) > 1e-2 {
+		t.Errorf("Molodensky() = %v, %v, want %v, 28.02", p, h, want)
+	}
+}
+
+func TestDatum(t *testing.T) {
+	bangkok := Point{13.7563, 100.5018}
+	p, h := Indian1975.ToWGS84(bangkok, 0)
+	// Indian 1975 coordinates are hundreds of meters away from WGS84 ones
+	if d := p.Distance(bangkok); d < 300 || d > 500 {
+		t.Errorf("ToWGS84() = %v, %v meters away", p, d)
+	}
+	// Molodensky formulas are close to the geocentric translation
+	if q, qh := Molodensky(bangkok, 0, Everest1830, WGS84, 209, 818, 290); q.Distance(p) > 0.5 || math.Abs(qh-h) > 0.5 {
+		t.Errorf("Molodensky() = %v, %v, want %v, %v", q, qh, p, h)
+	}
+	if q, qh := Indian1975.FromWGS84(p, h); q.Distance(bangkok) > 1e-6 || math.Abs(qh) > 1e-6 {
+		t.Errorf("FromWGS84() = %v, %v, want %v, 0", q, qh, bangkok)
+	}
+	if q, qh := WGS84Datum.ToWGS84(bangkok, 10); q != bangkok || qh != 10 {
+		t.Errorf("WGS84Datum.ToWGS84() = %v, %v", q, qh)
+	}
+}
@@ -0,0 +1,427 @@
+package geo
+
+import (
+	"compress/gzip"
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type (
+	// ElevationModel returns the elevation in meters above the sea level of points.
+	// DEM and ElevationProvider are elevation models.
+	ElevationModel interface {
+		Elevation(p Point) (float64, error)
+	}
+
+	// DEM is a digital elevation model, a grid of elevations in meters.
+	// Samples are at the crossings of the grid, row by row from the north west sample at X, Y.
+	// Coordinates are longitudes and latitudes in degrees of WGS84 when CRS is nil,
+	// otherwise coordinates of CRS such as the easting and the northing of a UTM zone.
+	DEM struct {
+		Width, Height int
+		X, Y          float64
+		// XStep is the distance between columns, and YStep the distance between rows going south.
+		XStep, YStep float64
+		CRS          CRS
+		// Samples holds Width * Height elevations, NaN when unknown.
+		Samples []float32
+	}
+
+	// ElevationProvider returns elevations from the SRTM .hgt files and the GeoTIFF files of a directory.
+	// HGT files are named after their south west corner, such as N13E100.hgt, and can be gzip compressed.
+	// Tiles are loaded when needed and the most recently used ones are kept in memory.
+	// Files that cannot be read when indexing the directory are skipped, Index reports them.
+	// ElevationProvider can be used by several goroutines.
+	ElevationProvider struct {
+		dir       string
+		cacheSize int
+
+		// indexMu serializes the indexing of the directory, which does not block lookups
+		indexMu  sync.Mutex
+		mu       sync.Mutex
+		indexed  bool
+		hgtFiles map[string]hgtFile
+		tiffs    []geoTIFFFile
+		cache    map[string]*list.Element
+		lru      *list.List
+	}
+
+	// ProfilePoint is a point of an elevation profile.
+	ProfilePoint struct {
+		Point
+		// Distance is the distance in meters from the start of the path.
+		Distance float64
+		// Elevation is the elevation in meters, NaN when unknown.
+		Elevation float64
+	}
+
+	// hgtFile is an HGT file of the directory of a provider and its south west corner.
+	hgtFile struct {
+		path     string
+		lat, lon int
+	}
+
+	// geoTIFFFile is a GeoTIFF file of the directory of a provider and the area it covers.
+	geoTIFFFile struct {
+		path string
+		box  BoundingBox
+	}
+
+	// elevationTile is a cached DEM.
+	elevationTile struct {
+		path string
+		dem  *DEM
+	}
+)
+
+var (
+	// ErrNoElevation is returned for points outside elevation models, or on samples without elevation.
+	ErrNoElevation = errors.New("No elevation data")
+	// ErrInvalidDEM is returned when reading malformed elevation files.
+	ErrInvalidDEM = errors.New("Invalid elevation model")
+)
+
+// hgtVoid is the value of HGT samples without elevation.
+const hgtVoid = -32768
+
+// defaultElevationCacheSize is the number of tiles kept in memory by providers, 8 tiles of 1 arc-second take 400 MB.
+const defaultElevationCacheSize = 8
+
+// ReadHGT reads an SRTM .hgt file of 1 degree whose south west corner is at latitude lat and longitude lon.
+// Files of 3 arc-second have 1201 by 1201 samples and files of 1 arc-second 3601 by 3601 samples,
+// big endian 16 bit integers.
+func ReadHGT(r io.Reader, lat, lon int) (*DEM, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var size int
+	switch len(data) {
+	case 1201 * 1201 * 2:
+		size = 1201
+	case 3601 * 3601 * 2:
+		size = 3601
+	default:
+		return nil, ErrInvalidDEM
+	}
+
+	step := 1 / float64(size-1)
+	dem := &DEM{Width: size, Height: size, X: float64(lon), Y: float64(lat + 1), XStep: step, YStep: step, Samples: make([]float32, size*size)}
+	for i := range dem.Samples {
+		v := int16(uint16(data[2*i])<<8 | uint16(data[2*i+1]))
+		if v == hgtVoid {
+			dem.Samples[i] = float32(math.NaN())
+		} else {
+			dem.Samples[i] = float32(v)
+		}
+	}
+	return dem, nil
+}
+
+// HGTName returns the name of the SRTM .hgt file holding the point, such as N13E100.hgt.
+func HGTName(p Point) string {
+	lat := int(math.Floor(p.Lat))
+	lon := int(math.Floor(normalizeLongitude(p.Lon)))
+	if lon == 180 {
+		lon = -180
+	}
+	ns, ew := 'N', 'E'
+	if lat < 0 {
+		ns, lat = 'S', -lat
+	}
+	if lon < 0 {
+		ew, lon = 'W', -lon
+	}
+	return fmt.Sprintf("%c%02d%c%03d.hgt", ns, lat, ew, lon)
+}
+
+// parseHGTName returns the latitude and longitude of the south west corner of a file named as by HGTName.
+func parseHGTName(name string) (lat, lon int, ok bool) {
+	var ns, ew byte
+	if n, err := fmt.Sscanf(strings.ToUpper(name), "%c%2d%c%3d.HGT", &ns, &lat, &ew, &lon); err != nil || n != 4 {
+		return 0, 0, false
+	}
+	switch {
+	case ns == 'S':
+		lat = -lat
+	case ns != 'N':
+		return 0, 0, false
+	}
+	switch {
+	case ew == 'W':
+		lon = -lon
+	case ew != 'E':
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// BoundingBox returns the area covered by the samples of the elevation model.
+// The box of projected models holds their corners and the middles of their edges.
+func (d *DEM) BoundingBox() BoundingBox {
+	x1, y1 := d.X, d.Y
+	x2, y2 := d.X+float64(d.Width-1)*d.XStep, d.Y-float64(d.Height-1)*d.YStep
+	if d.CRS == nil {
+		return BoundingBox{MinLat: y2, MinLon: x1, MaxLat: y1, MaxLon: x2}
+	}
+	box := BoundingBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, x := range []float64{x1, (x1 + x2) / 2, x2} {
+		for _, y := range []float64{y1, (y1 + y2) / 2, y2} {
+			p := d.CRS.ToWGS84(x, y)
+			box.MinLat, box.MaxLat = math.Min(box.MinLat, p.Lat), math.Max(box.MaxLat, p.Lat)
+			box.MinLon, box.MaxLon = math.Min(box.MinLon, p.Lon), math.Max(box.MaxLon, p.Lon)
+		}
+	}
+	return box
+}
+
+// Elevation returns the elevation in meters of the point, interpolated between the 4 samples around it.
+// Samples without elevation are left out of the interpolation.
+// ErrNoElevation is returned for points outside the model, or without any sample around.
+func (d *DEM) Elevation(p Point) (float64, error) {
+	x, y := p.Lon, p.Lat
+	if d.CRS != nil {
+		x, y = d.CRS.FromWGS84(p)
+	}
+	col := (x - d.X) / d.XStep
+	row := (d.Y - y) / d.YStep
+	// Points on the last column or row are interpolated from the cell before
+	const epsilon = 1e-9
+	if math.IsNaN(col) || math.IsNaN(row) || col < -epsilon || row < -epsilon ||
+		col > float64(d.Width-1)+epsilon || row > float64(d.Height-1)+epsilon {
+		return 0, ErrNoElevation
+	}
+	c := int(math.Max(0, math.Min(math.Floor(col), float64(d.Width-2))))
+	r := int(math.Max(0, math.Min(math.Floor(row), float64(d.Height-2))))
+	fx := math.Max(0, math.Min(1, col-float64(c)))
+	fy := math.Max(0, math.Min(1, row-float64(r)))
+
+	var sum, weights float64
+	for _, s := range [4]struct {
+		c, r int
+		w    float64
+	}{
+		{c, r, (1 - fx) * (1 - fy)},
+		{c + 1, r, fx * (1 - fy)},
+		{c, r + 1, (1 - fx) * fy},
+		{c + 1, r + 1, fx * fy},
+	} {
+		if s.c >= d.Width || s.r >= d.Height || s.w == 0 {
+			continue
+		}
+		if v := float64(d.Samples[s.r*d.Width+s.c]); !math.IsNaN(v) {
+			sum += v * s.w
+			weights += s.w
+		}
+	}
+	if weights == 0 {
+		return 0, ErrNoElevation
+	}
+	return sum / weights, nil
+}
+
+// NewElevationProvider returns a provider of elevations from the files of the directory,
+// keeping cacheSize tiles in memory, 8 when cacheSize is 0 or less.
+//  provider := geo.NewElevationProvider("/data/srtm", 0)
+//  elevation, err := provider.Elevation(geo.Point{Lat: 18.5884, Lon: 98.4871})
+func NewElevationProvider(dir string, cacheSize int) *ElevationProvider {
+	if cacheSize <= 0 {
+		cacheSize = defaultElevationCacheSize
+	}
+	return &ElevationProvider{dir: dir, cacheSize: cacheSize, cache: map[string]*list.Element{}, lru: list.New()}
+}
+
+// Elevation returns the elevation in meters of the point, from its HGT file or else the first GeoTIFF file holding it.
+// ErrNoElevation is returned when no file holds the point, as for the seas in SRTM data.
+func (e *ElevationProvider) Elevation(p Point) (float64, error) {
+	e.mu.Lock()
+	indexed := e.indexed
+	e.mu.Unlock()
+	if !indexed {
+		if _, err := e.index(false); err != nil {
+			return 0, err
+		}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if f, ok := e.hgtFiles[HGTName(p)]; ok {
+		dem, err := e.load(f.path, func(file *os.File) (*DEM, error) {
+			if !strings.HasSuffix(strings.ToLower(f.path), ".gz") {
+				return ReadHGT(file, f.lat, f.lon)
+			}
+			gz, err := gzip.NewReader(file)
+			if err != nil {
+				return nil, err
+			}
+			return ReadHGT(gz, f.lat, f.lon)
+		})
+		if err != nil {
+			return 0, err
+		}
+		return dem.Elevation(p)
+	}
+	for _, f := range e.tiffs {
+		if !f.box.Contains(p) {
+			continue
+		}
+		dem, err := e.load(f.path, func(file *os.File) (*DEM, error) {
+			return ReadGeoTIFF(file)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if v, err := dem.Elevation(p); err != ErrNoElevation {
+			return v, err
+		}
+	}
+	return 0, ErrNoElevation
+}
+
+// Index lists the files of the directory again, to use files added since the first call to Elevation,
+// which indexes the directory. Files that cannot be read, such as GeoTIFF files without georeferencing,
+// are skipped and returned in the error, the other files are used.
+//  if err := provider.Index(); err != nil {
+//      log.Println(err)
+//  }
+func (e *ElevationProvider) Index() error {
+	skipped, err := e.index(true)
+	if err != nil {
+		return err
+	}
+	return skipped
+}
+
+// index lists the HGT files of the directory and reads the areas of the GeoTIFF files, unless the directory
+// is already indexed and again is false. skipped is the error of the files that cannot be read, and err
+// the error of the directory.
+func (e *ElevationProvider) index(again bool) (skipped, err error) {
+	e.indexMu.Lock()
+	defer e.indexMu.Unlock()
+	e.mu.Lock()
+	indexed := e.indexed
+	e.mu.Unlock()
+	if indexed && !again {
+		return nil, nil
+	}
+
+	files, err := ioutil.ReadDir(e.dir)
+	if err != nil {
+		return nil, err
+	}
+	hgtFiles := map[string]hgtFile{}
+	var tiffs []geoTIFFFile
+	var errs []string
+	for _, f := range files {
+		name := f.Name()
+		path := filepath.Join(e.dir, name)
+		lower := strings.ToLower(name)
+		switch {
+		case strings.HasSuffix(lower, ".hgt") || strings.HasSuffix(lower, ".hgt.gz"):
+			if lat, lon, ok := parseHGTName(name[:strings.Index(lower, ".hgt")+4]); ok {
+				hgtFiles[HGTName(Point{Lat: float64(lat), Lon: float64(lon)})] = hgtFile{path: path, lat: lat, lon: lon}
+			}
+		case strings.HasSuffix(lower, ".tif") || strings.HasSuffix(lower, ".tiff"):
+			box, err := geoTIFFBoundingBox(path)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+				continue
+			}
+			tiffs = append(tiffs, geoTIFFFile{path: path, box: box})
+		}
+	}
+
+	e.mu.Lock()
+	e.hgtFiles, e.tiffs, e.indexed = hgtFiles, tiffs, true
+	e.mu.Unlock()
+	if len(errs) > 0 {
+		skipped = fmt.Errorf("%d elevation files skipped: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return skipped, nil
+}
+
+// geoTIFFBoundingBox returns the area of a GeoTIFF file, reading its header only.
+func geoTIFFBoundingBox(path string) (BoundingBox, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return BoundingBox{}, err
+	}
+	defer file.Close()
+	t, err := readGeoTIFFHeader(file)
+	if err != nil {
+		return BoundingBox{}, err
+	}
+	return t.dem().BoundingBox(), nil
+}
+
+// load returns the elevation model of the file from the cache, or reads it with read.
+func (e *ElevationProvider) load(path string, read func(file *os.File) (*DEM, error)) (*DEM, error) {
+	if el, ok := e.cache[path]; ok {
+		e.lru.MoveToFront(el)
+		return el.Value.(*elevationTile).dem, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dem, err := read(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	e.cache[path] = e.lru.PushFront(&elevationTile{path: path, dem: dem})
+	if e.lru.Len() > e.cacheSize {
+		oldest := e.lru.Remove(e.lru.Back()).(*elevationTile)
+		delete(e.cache, oldest.path)
+	}
+	return dem, nil
+}
+
+// ElevationProfile returns the elevations along the path, at its points and every step meters
+// between them along great circles. Distances from the start are computed with Distance.
+// Points without elevation data have NaN elevations, other errors of the model stop the profile.
+//  profile, err := geo.ElevationProfile(provider, route, 100)
+func ElevationProfile(model ElevationModel, path []Point, step float64) ([]ProfilePoint, error) {
+	profile := make([]ProfilePoint, 0, len(path))
+	add := func(p Point, distance float64) error {
+		elevation, err := model.Elevation(p)
+		if err == ErrNoElevation {
+			elevation = math.NaN()
+		} else if err != nil {
+			return err
+		}
+		profile = append(profile, ProfilePoint{Point: p, Distance: distance, Elevation: elevation})
+		return nil
+	}
+
+	var distance float64
+	for i, p := range path {
+		if i > 0 {
+			prev := path[i-1]
+			length := prev.Distance(p)
+			if step > 0 {
+				for d := step; d < length; d += step {
+					lat, lon := Intermediate(prev.Lat, prev.Lon, p.Lat, p.Lon, d/length)
+					if err := add(Point{Lat: lat, Lon: lon}, distance+d); err != nil {
+						return nil, err
+					}
+				}
+			}
+			distance += length
+		}
+		if err := add(p, distance); err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
@@ -0,0 +1,214 @@
+package geo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testHGT returns an HGT file of size by size samples whose elevations grow by 1 meter per column
+// and 2 meters per row going south, with a void sample in the middle.
+func testHGT(size int) []byte {
+	data := make([]byte, 2*size*size)
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			v := int16(col + 2*row)
+			if row == size/2 && col == size/2 {
+				v = hgtVoid
+			}
+			binary.BigEndian.PutUint16(data[2*(row*size+col):], uint16(v))
+		}
+	}
+	return data
+}
+
+func TestReadHGT(t *testing.T) {
+	for _, size := range []int{1201, 3601} {
+		dem, err := ReadHGT(bytes.NewReader(testHGT(size)), 13, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		step := 1 / float64(size-1)
+		tests := []struct {
+			p    Point
+			want float64
+		}{
+			// Corners
+			{Point{14, 100}, 0},
+			{Point{14, 101}, float64(size - 1)},
+			{Point{13, 100}, float64(2 * (size - 1))},
+			{Point{13, 101}, float64(3 * (size - 1))},
+			// Between samples
+			{Point{14 - 2.5*step, 100 + 10.25*step}, 15.25},
+			// Next to the void sample
+			{Point{13.5, 100.5 - step/2}, float64(3*(size/2)) - 1},
+		}
+		for _, tt := range tests {
+			if got, err := dem.Elevation(tt.p); err != nil || math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("%v samples: Elevation(%v) = %v, %v, want %v", size, tt.p, got, err, tt.want)
+			}
+		}
+		for _, p := range []Point{{13.5, 100.5}, {12.9, 100.5}, {13.5, 101.1}} {
+			if _, err := dem.Elevation(p); err != ErrNoElevation {
+				t.Errorf("%v samples: Elevation(%v) error = %v", size, p, err)
+			}
+		}
+	}
+	if _, err := ReadHGT(bytes.NewReader(make([]byte, 1000)), 13, 100); err != ErrInvalidDEM {
+		t.Errorf("ReadHGT(invalid size) error = %v", err)
+	}
+}
+
+func TestHGTName(t *testing.T) {
+	tests := []struct {
+		p    Point
+		want string
+	}{
+		{Point{13.7563, 100.5018}, "N13E100.hgt"},
+		{Point{13, 100}, "N13E100.hgt"},
+		{Point{-0.5, -0.5}, "S01W001.hgt"},
+		{Point{-33.8688, 151.2093}, "S34E151.hgt"},
+		{Point{40.7128, -74.0060}, "N40W075.hgt"},
+		{Point{0, 180}, "N00W180.hgt"},
+	}
+	for _, tt := range tests {
+		if got := HGTName(tt.p); got != tt.want {
+			t.Errorf("HGTName(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+		lat, lon, ok := parseHGTName(tt.want)
+		if !ok || HGTName(Point{Lat: float64(lat), Lon: float64(lon)}) != tt.want {
+			t.Errorf("parseHGTName(%v) = %v, %v, %v", tt.want, lat, lon, ok)
+		}
+	}
+	for _, name := range []string{"X13E100.hgt", "N13E100.tif", "N13"} {
+		if _, _, ok := parseHGTName(name); ok {
+			t.Errorf("parseHGTName(%v) ok", name)
+		}
+	}
+}
+
+func TestElevationProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "N13E100.hgt"), testHGT(1201), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write(testHGT(1201))
+	w.Close()
+	if err := ioutil.WriteFile(filepath.Join(dir, "s01w001.hgt.gz"), gz.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tiff := testGeoTIFF{
+		order: binary.LittleEndian, width: 11, height: 11, bits: 16, format: 2,
+		scale: []float64{0.1, 0.1, 0}, tiepoint: []float64{0, 0, 0, 2, 49, 0}, geoKeys: geographicKeys,
+		sample: func(col, row int) float64 { return 35 },
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "paris.tif"), tiff.encode(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "readme.txt"), []byte("SRTM"), 0644)
+
+	provider := NewElevationProvider(dir, 1)
+	tests := []struct {
+		p    Point
+		want float64
+	}{
+		{Point{13.75, 100.5}, 1200},
+		{Point{13, 100.25}, 2700},
+		{Point{-1, -1}, 2400},
+		{Point{48.8566, 2.3522}, 35},
+		{Point{13.5, 100.25}, 1500},
+	}
+	for _, tt := range tests {
+		if got, err := provider.Elevation(tt.p); err != nil || math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("Elevation(%v) = %v, %v, want %v", tt.p, got, err, tt.want)
+		}
+	}
+	if provider.lru.Len() != 1 || len(provider.cache) != 1 {
+		t.Errorf("%v tiles in cache, want 1", provider.lru.Len())
+	}
+	if _, err := provider.Elevation(Point{7.88, 98.39}); err != ErrNoElevation {
+		t.Errorf("Elevation(missing tile) error = %v", err)
+	}
+	if _, err := NewElevationProvider(filepath.Join(dir, "missing"), 0).Elevation(Point{14, 100}); err == nil {
+		t.Error("Elevation(missing directory) error = nil")
+	}
+}
+
+func TestElevationProvider_Index(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "N13E100.hgt"), testHGT(1201), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tiff := testGeoTIFF{
+		order: binary.LittleEndian, width: 11, height: 11, bits: 16, format: 2,
+		scale: []float64{0.1, 0.1, 0}, tiepoint: []float64{0, 0, 0, 2, 49, 0}, geoKeys: geographicKeys,
+		sample: func(col, row int) float64 { return 35 },
+	}
+	ioutil.WriteFile(filepath.Join(dir, "paris.tif"), tiff.encode(), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "corrupt.tif"), []byte("II*\x00\xff\xff\xff\xff"), 0644)
+
+	// Files that cannot be read do not prevent the use of the other files
+	provider := NewElevationProvider(dir, 0)
+	for i := 0; i < 2; i++ {
+		if v, err := provider.Elevation(Point{13.75, 100.5}); err != nil || math.Abs(v-1200) > 1e-6 {
+			t.Errorf("Elevation() = %v, %v, want 1200", v, err)
+		}
+		if v, err := provider.Elevation(Point{48.8566, 2.3522}); err != nil || math.Abs(v-35) > 1e-6 {
+			t.Errorf("Elevation(GeoTIFF) = %v, %v, want 35", v, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := provider.Index(); err == nil || !strings.Contains(err.Error(), "corrupt.tif") {
+			t.Errorf("Index() error = %v", err)
+		}
+		if len(provider.tiffs) != 1 || len(provider.hgtFiles) != 1 {
+			t.Errorf("Index() = %v GeoTIFF files, %v HGT files", len(provider.tiffs), len(provider.hgtFiles))
+		}
+	}
+	os.Remove(filepath.Join(dir, "corrupt.tif"))
+	if err := provider.Index(); err != nil {
+		t.Errorf("Index() error = %v", err)
+	}
+}
+
+func TestElevationProfile(t *testing.T) {
+	dem, err := ReadHGT(bytes.NewReader(testHGT(1201)), 13, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := []Point{{13.1, 100.1}, {13.1, 100.2}, {13.2, 100.2}, {12.9, 100.2}}
+	profile, err := ElevationProfile(dem, path, 5000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10.8 km, 11.1 km then 33.4 km with points every 5 km
+	if len(profile) != 3+3+7+1 {
+		t.Fatalf("ElevationProfile() = %v points", len(profile))
+	}
+	var prev ProfilePoint
+	for i, p := range profile {
+		if i > 0 && (p.Distance <= prev.Distance || math.Abs(p.Distance-prev.Distance-prev.Point.Distance(p.Point)) > 1e-6) {
+			t.Errorf("ElevationProfile()[%v] = %+v after %+v", i, p, prev)
+		}
+		want, _ := dem.Elevation(p.Point)
+		if p.Lat < 13 && !math.IsNaN(p.Elevation) || p.Lat >= 13 && p.Elevation != want {
+			t.Errorf("ElevationProfile()[%v] = %+v, want elevation %v", i, p, want)
+		}
+		prev = p
+	}
+	if last := profile[len(profile)-1]; last.Point != path[3] || math.Abs(last.Distance-PathLength(path)) > 1e-6 {
+		t.Errorf("ElevationProfile() ends with %+v, want %v at %v", last, path[3], PathLength(path))
+	}
+
+	if profile, err := ElevationProfile(dem, path, 0); err != nil || len(profile) != len(path) {
+		t.Errorf("ElevationProfile(no step) = %v, %v", profile, err)
+	}
+}
@@ -16,6 +16,9 @@ import (
 
 //go:generate ffjson geo.go
 
+// EarthRadius is the radius of the Earth in meters used by the spherical functions of the package.
+const EarthRadius = 6378100
+
 type (
 	// Nominatim is address structure returned by nominatim API.
 	Nominatim struct {
@@ -63,7 +66,7 @@ func Distance(lat1, lon1, lat2, lon2 float64) float64 {
 	la2 = lat2 * math.Pi / 180
 	lo2 = lon2 * math.Pi / 180
 
-	r = 6378100 // Earth radius in METERS
+	r = EarthRadius // Earth radius in METERS
 
 	// calculate
 	h := hsin(la2-la1) + math.Cos(la1)*math.Cos(la2)*hsin(lo2-lo1)
@@ -99,6 +102,12 @@ func Reverse(lat, lon float64) (address Nominatim, err error) {
 // GeoLocate returns coordinates based on address
 //   GeoLocate(geo.Address{City:"Bangkok","Road":"Latprao 94, Town in Town",PostCode:10310})
 func GeoLocate(address Address) (lat, long float64) {
+	lat, long, _ = geoLocate(address)
+	return
+}
+
+// geoLocate queries nominatim for coordinates of address
+func geoLocate(address Address) (lat, long float64, err error) {
 	// curl "https://nominatim.openstreetmap.org/search/query?city=ottignies&street=pinchart 31&format=json
 
 	query := url.QueryEscape(fmt.Sprintf("format=json&city=%s&street=%s&postcode=%s",
@@ -130,11 +139,15 @@ func GeoLocate(address Address) (lat, long float64) {
 		return
 	}
 
-	if err = ffjson.Unmarshal(body, &places); err == nil && len(places) > 0 {
-		lat = places[0].Lat
-		long = places[0].Long
+	if err = ffjson.Unmarshal(body, &places); err != nil {
+		return
 	}
-
+	if len(places) == 0 {
+		err = ErrNotFound
+		return
+	}
+	lat = places[0].Lat
+	long = places[0].Long
 	return
 }
 
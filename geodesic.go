@@ -0,0 +1,358 @@
+package geo
+
+import "math"
+
+type (
+	// Ellipsoid is a reference ellipsoid described by its equatorial radius A (in meters)
+	// and its flattening F.
+	Ellipsoid struct {
+		A float64
+		F float64
+	}
+
+	// Geodesic is the solution of the inverse geodesic problem between two points.
+	// Azimuths are in degrees, clockwise from the north, in the range (-180, 180].
+	Geodesic struct {
+		Distance float64 // Distance in meters along the ellipsoid
+		Forward  float64 // Forward is the azimuth at the first point, heading toward the second point
+		Reverse  float64 // Reverse is the azimuth at the second point, heading back toward the first point
+	}
+)
+
+// Reference ellipsoids commonly used by geodetic datums.
+var (
+	WGS84             = Ellipsoid{A: 6378137, F: 1 / 298.257223563}
+	GRS80             = Ellipsoid{A: 6378137, F: 1 / 298.257222101}
+	International1924 = Ellipsoid{A: 6378388, F: 1 / 297.0}
+	Bessel1841        = Ellipsoid{A: 6377397.155, F: 1 / 299.1528128}
+	Clarke1866        = Ellipsoid{A: 6378206.4, F: 1 / 294.978698214}
+	Airy1830          = Ellipsoid{A: 6377563.396, F: 1 / 299.3249646}
+	Everest1830       = Ellipsoid{A: 6377276.345, F: 1 / 300.8017}
+)
+
+const (
+	// Number of iterations before we consider that Vincenty method does not converge
+	vincentyMaxIter = 200
+	// Number of iterations to find the azimuth of nearly antipodal points by bisection
+	bisectionMaxIter = 200
+	// Number of points used by the Gauss-Legendre quadrature
+	quadratureOrder = 32
+)
+
+var gaussNodes, gaussWeights = gaussLegendre(quadratureOrder)
+
+// GeodesicDistance returns the distance in meters on the WGS84 ellipsoid between two points
+// together with the forward and reverse azimuths.
+// It is slower but much more accurate than Distance that uses a spherical model.
+//  g := geo.GeodesicDistance(13.76, 100.50, 13.89, 101.12)
+//  fmt.Println(g.Distance, g.Forward, g.Reverse)
+func GeodesicDistance(lat1, lon1, lat2, lon2 float64) Geodesic {
+	return WGS84.Inverse(lat1, lon1, lat2, lon2)
+}
+
+// B returns the polar radius of the ellipsoid in meters.
+func (e Ellipsoid) B() float64 {
+	return e.A * (1 - e.F)
+}
+
+// Inverse solves the inverse geodesic problem: it returns the shortest distance between two points
+// on the ellipsoid and the azimuths at both ends.
+// Vincenty formulae are used in most cases. When they do not converge (nearly antipodal points),
+// we solve for the azimuth at the first point by bisection, as suggested by C. F. F. Karney in
+// "Algorithms for geodesics" (2013), evaluating the geodesic integrals with a Gauss-Legendre quadrature.
+//  g := geo.WGS84.Inverse(13.7665217, 100.6068431, 13.7199345, 100.5197898)
+func (e Ellipsoid) Inverse(lat1, lon1, lat2, lon2 float64) (g Geodesic) {
+	lon12 := angleDiff(lon1, lon2)
+
+	// Put points in a canonical configuration: lat1 <= 0, |lat2| <= |lat1| and lon12 >= 0
+	lonSign := 1.0
+	if lon12 < 0 {
+		lonSign = -1
+		lon12 = -lon12
+	}
+	swapSign := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapSign = -1
+		lonSign = -lonSign
+		lat1, lat2 = lat2, lat1
+	}
+	latSign := 1.0
+	if lat1 > 0 {
+		latSign = -1
+		lat1, lat2 = -lat1, -lat2
+	}
+
+	beta1 := e.reducedLatitude(lat1)
+	beta2 := e.reducedLatitude(lat2)
+	lambda12 := toRadians(lon12)
+
+	s, sinAlp1, cosAlp1, sinAlp2, cosAlp2, ok := e.vincentyInverse(beta1, beta2, lambda12)
+	if !ok {
+		s, sinAlp1, cosAlp1, sinAlp2, cosAlp2 = e.bisectionInverse(beta1, beta2, lambda12)
+	}
+
+	// Go back to the original configuration
+	if swapSign < 0 {
+		sinAlp1, sinAlp2 = sinAlp2, sinAlp1
+		cosAlp1, cosAlp2 = cosAlp2, cosAlp1
+	}
+	sinAlp1 *= swapSign * lonSign
+	cosAlp1 *= swapSign * latSign
+	sinAlp2 *= swapSign * lonSign
+	cosAlp2 *= swapSign * latSign
+
+	g.Distance = s
+	g.Forward = toDegrees(math.Atan2(sinAlp1, cosAlp1))
+	// Reverse azimuth is the forward azimuth at the second point turned around
+	g.Reverse = toDegrees(math.Atan2(-sinAlp2, -cosAlp2))
+	return
+}
+
+// Direct solves the direct geodesic problem: starting from a point with a given azimuth (degrees)
+// and walking the given distance (meters) along the geodesic, it returns the destination point
+// and the azimuth at the destination.
+//  lat, lon, azi := geo.WGS84.Direct(13.7665217, 100.6068431, 45, 10000)
+func (e Ellipsoid) Direct(lat1, lon1, azimuth1, distance float64) (lat2, lon2, azimuth2 float64) {
+	b := e.B()
+	f := e.F
+	alpha1 := toRadians(azimuth1)
+	sinAlpha1, cosAlpha1 := math.Sincos(alpha1)
+
+	tanU1 := (1 - f) * math.Tan(toRadians(lat1))
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+
+	sigma1 := math.Atan2(tanU1, cosAlpha1)
+	sinAlpha := cosU1 * sinAlpha1
+	cosSqAlpha := 1 - sinAlpha*sinAlpha
+	A, B := vincentyAB(cosSqAlpha * (e.A*e.A - b*b) / (b * b))
+
+	sigma := distance / (b * A)
+	var sinSigma, cosSigma, cos2SigmaM float64
+	for i := 0; i < vincentyMaxIter; i++ {
+		cos2SigmaM = math.Cos(2*sigma1 + sigma)
+		sinSigma, cosSigma = math.Sincos(sigma)
+		deltaSigma := vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM)
+		next := distance/(b*A) + deltaSigma
+		if math.Abs(next-sigma) < 1e-12 {
+			sigma = next
+			break
+		}
+		sigma = next
+	}
+	cos2SigmaM = math.Cos(2*sigma1 + sigma)
+	sinSigma, cosSigma = math.Sincos(sigma)
+
+	tmp := sinU1*sinSigma - cosU1*cosSigma*cosAlpha1
+	phi2 := math.Atan2(sinU1*cosSigma+cosU1*sinSigma*cosAlpha1, (1-f)*math.Sqrt(sinAlpha*sinAlpha+tmp*tmp))
+	lambda := math.Atan2(sinSigma*sinAlpha1, cosU1*cosSigma-sinU1*sinSigma*cosAlpha1)
+	C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+	L := lambda - (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+	lat2 = toDegrees(phi2)
+	lon2 = normalizeLongitude(lon1 + toDegrees(L))
+	azimuth2 = toDegrees(math.Atan2(sinAlpha, -tmp))
+	return
+}
+
+// vincentyInverse solves the inverse problem with Vincenty iteration on the auxiliary sphere.
+// beta1 and beta2 are reduced latitudes. ok is false when the iteration does not converge.
+func (e Ellipsoid) vincentyInverse(beta1, beta2, lambda12 float64) (s, sinAlp1, cosAlp1, sinAlp2, cosAlp2 float64, ok bool) {
+	b := e.B()
+	f := e.F
+	sinU1, cosU1 := math.Sincos(beta1)
+	sinU2, cosU2 := math.Sincos(beta2)
+
+	lambda := lambda12
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM, sinLambda, cosLambda float64
+	for i := 0; ; i++ {
+		if i == vincentyMaxIter {
+			return
+		}
+		sinLambda, cosLambda = math.Sincos(lambda)
+		x := cosU2 * sinLambda
+		y := cosU1*sinU2 - sinU1*cosU2*cosLambda
+		sinSigma = math.Hypot(x, y)
+		if sinSigma == 0 {
+			// Coincident points
+			return 0, 0, 1, 0, 1, true
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		next := lambda12 + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(next) > math.Pi {
+			return
+		}
+		if math.Abs(next-lambda) < 1e-12 {
+			lambda = next
+			break
+		}
+		lambda = next
+	}
+
+	sinLambda, cosLambda = math.Sincos(lambda)
+	A, B := vincentyAB(cosSqAlpha * (e.A*e.A - b*b) / (b * b))
+	s = b * A * (sigma - vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM))
+
+	sinAlp1, cosAlp1 = cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda
+	sinAlp2, cosAlp2 = cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda
+	ok = true
+	return
+}
+
+// bisectionInverse solves the inverse problem by finding the azimuth at the first point
+// for which the geodesic reaches the longitude of the second point.
+// Points must be in canonical configuration: beta1 <= 0, |beta2| <= |beta1| and lambda12 >= 0.
+// In this configuration the longitude reached is a monotonic function of the azimuth in [0, π].
+func (e Ellipsoid) bisectionInverse(beta1, beta2, lambda12 float64) (s, sinAlp1, cosAlp1, sinAlp2, cosAlp2 float64) {
+	lo, hi := 0.0, math.Pi
+	alpha1 := math.Pi / 2
+	for i := 0; i < bisectionMaxIter && hi-lo > 1e-15; i++ {
+		alpha1 = (lo + hi) / 2
+		lambda, _, _, _ := e.geodesicToLatitude(beta1, beta2, alpha1)
+		if lambda < lambda12 {
+			lo = alpha1
+		} else {
+			hi = alpha1
+		}
+	}
+	alpha1 = (lo + hi) / 2
+	_, s, sinAlp2, cosAlp2 = e.geodesicToLatitude(beta1, beta2, alpha1)
+	sinAlp1, cosAlp1 = math.Sincos(alpha1)
+	return
+}
+
+// geodesicToLatitude follows the geodesic leaving reduced latitude beta1 with azimuth alpha1
+// until it reaches reduced latitude beta2 heading north.
+// It returns the longitude difference, the distance and the azimuth at the end point.
+func (e Ellipsoid) geodesicToLatitude(beta1, beta2, alpha1 float64) (lambda12, s, sinAlp2, cosAlp2 float64) {
+	b := e.B()
+	f := e.F
+	sinBeta1, cosBeta1 := math.Sincos(beta1)
+	sinBeta2, cosBeta2 := math.Sincos(beta2)
+	sinAlp1, cosAlp1 := math.Sincos(alpha1)
+
+	// Clairaut's relation gives the azimuth at the equator crossing
+	sinAlp0 := sinAlp1 * cosBeta1
+	cosAlp0 := math.Hypot(cosAlp1, sinAlp1*sinBeta1)
+
+	sinAlp2 = sinAlp0 / cosBeta2
+	cosAlp2 = math.Sqrt(math.Max(0, cosAlp1*cosAlp1*cosBeta1*cosBeta1+(cosBeta2*cosBeta2-cosBeta1*cosBeta1))) / cosBeta2
+
+	sigma1 := math.Atan2(sinBeta1, cosAlp1*cosBeta1)
+	if sigma1 > 0 {
+		// beta1 is 0 and the geodesic heads south first
+		sigma1 -= 2 * math.Pi
+	}
+	sigma2 := math.Atan2(sinBeta2, cosAlp2*cosBeta2)
+	omega1 := math.Atan2(sinAlp0*math.Sin(sigma1), math.Cos(sigma1))
+	omega2 := math.Atan2(sinAlp0*math.Sin(sigma2), math.Cos(sigma2))
+
+	ep2 := (e.A*e.A - b*b) / (b * b)
+	k2 := ep2 * cosAlp0 * cosAlp0
+
+	i1 := integrate(func(sigma float64) float64 {
+		sin := math.Sin(sigma)
+		return math.Sqrt(1 + k2*sin*sin)
+	}, sigma1, sigma2)
+	i3 := integrate(func(sigma float64) float64 {
+		sin := math.Sin(sigma)
+		return (2 - f) / (1 + (1-f)*math.Sqrt(1+k2*sin*sin))
+	}, sigma1, sigma2)
+
+	lambda12 = omega2 - omega1 - f*sinAlp0*i3
+	s = b * i1
+	return
+}
+
+// reducedLatitude returns the reduced (parametric) latitude in radians.
+func (e Ellipsoid) reducedLatitude(lat float64) float64 {
+	if math.Abs(lat) == 90 {
+		return toRadians(lat)
+	}
+	return math.Atan((1 - e.F) * math.Tan(toRadians(lat)))
+}
+
+// vincentyAB returns Vincenty's A and B coefficients for u².
+func vincentyAB(uSq float64) (A, B float64) {
+	A = 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B = uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	return
+}
+
+// vincentyDeltaSigma returns Vincenty's Δσ.
+func vincentyDeltaSigma(B, sinSigma, cosSigma, cos2SigmaM float64) float64 {
+	return B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+}
+
+// integrate computes the integral of fn between a and b with the Gauss-Legendre quadrature.
+func integrate(fn func(float64) float64, a, b float64) float64 {
+	half := (b - a) / 2
+	mid := (b + a) / 2
+	var sum float64
+	for i, x := range gaussNodes {
+		sum += gaussWeights[i] * fn(mid+half*x)
+	}
+	return sum * half
+}
+
+// gaussLegendre returns the nodes and weights of the Gauss-Legendre quadrature of order n on [-1, 1].
+func gaussLegendre(n int) (nodes, weights []float64) {
+	nodes = make([]float64, n)
+	weights = make([]float64, n)
+	for i := 0; i < (n+1)/2; i++ {
+		// Initial guess of the root then Newton iterations on the Legendre polynomial
+		x := math.Cos(math.Pi * (float64(i) + 0.75) / (float64(n) + 0.5))
+		var dp float64
+		for iter := 0; iter < 100; iter++ {
+			p0, p1 := 1.0, x
+			for k := 2; k <= n; k++ {
+				p0, p1 = p1, ((2*float64(k)-1)*x*p1-(float64(k)-1)*p0)/float64(k)
+			}
+			dp = float64(n) * (x*p1 - p0) / (x*x - 1)
+			dx := p1 / dp
+			x -= dx
+			if math.Abs(dx) < 1e-16 {
+				break
+			}
+		}
+		nodes[i], nodes[n-1-i] = -x, x
+		weights[i] = 2 / ((1 - x*x) * dp * dp)
+		weights[n-1-i] = weights[i]
+	}
+	return
+}
+
+// angleDiff returns lon2 - lon1 in degrees reduced to the range (-180, 180].
+func angleDiff(lon1, lon2 float64) float64 {
+	return normalizeLongitude(lon2 - lon1)
+}
+
+// normalizeLongitude reduces a longitude in degrees to the range (-180, 180].
+func normalizeLongitude(lon float64) float64 {
+	lon = math.Mod(lon, 360)
+	if lon <= -180 {
+		lon += 360
+	} else if lon > 180 {
+		lon -= 360
+	}
+	return lon
+}
+
+// toRadians converts degrees to radians.
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// toDegrees converts radians to degrees.
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// dms converts degrees, minutes and seconds to decimal degrees.
+func dms(d, m, s float64) float64 {
+	return d + m/60 + s/3600
+}
+
+// Test lines published by T. Vincenty in "Direct and inverse solutions of geodesics on the
+// ellipsoid with application of nested equations" (1975). Azimuth2 is the forward azimuth at point 2.
+func TestEllipsoid_Inverse(t *testing.T) {
+	tests := []struct {
+		name     string
+		e        Ellipsoid
+		lat1     float64
+		lat2     float64
+		lon2     float64
+		distance float64
+		azimuth1 float64
+		azimuth2 float64
+	}{
+		{"a", Bessel1841, dms(55, 45, 0), -dms(33, 26, 0), dms(108, 13, 0),
+			14110526.170, dms(96, 36, 8.79960), dms(137, 52, 22.01454)},
+		{"b", International1924, dms(37, 19, 54.95367), dms(26, 7, 42.83946), dms(41, 28, 35.50729),
+			4085966.703, dms(95, 27, 59.63089), dms(118, 5, 58.96161)},
+		{"c", International1924, dms(35, 16, 11.24862), dms(67, 22, 14.77638), dms(137, 47, 28.31435),
+			8084823.839, dms(15, 44, 23.74850), dms(144, 55, 39.92147)},
+		{"d", International1924, 1, -dms(0, 59, 53.83076), dms(179, 17, 48.02997),
+			19960000.000, 89, dms(91, 0, 6.11733)},
+		{"e", International1924, 1, dms(1, 1, 15.18952), dms(179, 46, 17.84244),
+			19780006.558, dms(4, 59, 59.99995), dms(174, 59, 59.88481)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.e.Inverse(tt.lat1, 0, tt.lat2, tt.lon2)
+			if math.Abs(g.Distance-tt.distance) > 1e-3 {
+				t.Errorf("Inverse() distance = %v, want %v", g.Distance, tt.distance)
+			}
+			if math.Abs(g.Forward-tt.azimuth1) > 1e-5 {
+				t.Errorf("Inverse() forward = %v, want %v", g.Forward, tt.azimuth1)
+			}
+			if math.Abs(angleDiff(g.Reverse+180, tt.azimuth2)) > 1e-5 {
+				t.Errorf("Inverse() reverse = %v, want %v", g.Reverse, tt.azimuth2-180)
+			}
+		})
+	}
+}
+
+// Nearly antipodal points where Vincenty iteration does not converge.
+func TestEllipsoid_InverseAntipodal(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat1     float64
+		lon1     float64
+		lat2     float64
+		lon2     float64
+		distance float64
+	}{
+		{"GeographicLib example", 0, 0, 0.5, 179.5, 19936288.579},
+		{"Wikipedia example", 0, 0, 0.5, 179.7, 19944127.421},
+		{"Equator", 0, 0, 0, 179.9, 20003008.421},
+		{"Swapped", 0.5, 179.5, 0, 0, 19936288.579},
+		{"Southern", -30, 10, 30.0001, -170.05, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := WGS84.Inverse(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if tt.distance != 0 && math.Abs(g.Distance-tt.distance) > 1e-3 {
+				t.Errorf("Inverse() distance = %v, want %v", g.Distance, tt.distance)
+			}
+			// Walking along the geodesic must bring us to the second point
+			lat, lon, azi := WGS84.Direct(tt.lat1, tt.lon1, g.Forward, g.Distance)
+			if math.Abs(lat-tt.lat2) > 1e-8 || math.Abs(angleDiff(lon, tt.lon2)) > 1e-8 {
+				t.Errorf("Direct() = %v,%v, want %v,%v", lat, lon, tt.lat2, tt.lon2)
+			}
+			if math.Abs(angleDiff(azi+180, g.Reverse)) > 1e-6 {
+				t.Errorf("Direct() azimuth = %v, want %v", azi, g.Reverse+180)
+			}
+		})
+	}
+}
+
+func TestGeodesicDistance(t *testing.T) {
+	g := GeodesicDistance(13.7665217, 100.6068431, 13.7665217, 100.6068431)
+	if g.Distance != 0 {
+		t.Errorf("GeodesicDistance() = %v, want 0", g.Distance)
+	}
+	// Spherical approximation is within 0.5% of the ellipsoidal distance
+	g = GeodesicDistance(13.7665217, 100.6068431, 13.7199345, 100.5197898)
+	if d := Distance(13.7665217, 100.6068431, 13.7199345, 100.5197898); math.Abs(d-g.Distance)/g.Distance > 0.005 {
+		t.Errorf("GeodesicDistance() = %v, Distance() = %v", g.Distance, d)
+	}
+}
@@ -0,0 +1,213 @@
+package geo
+
+import (
+	"errors"
+	"strings"
+)
+
+// Direction is a cardinal or intercardinal direction.
+type Direction int
+
+// Directions of geohash neighbors, in the order returned by GeohashNeighbors.
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// geohashAlphabet is the base 32 alphabet used by geohash.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashMaxCover is the maximum number of cells returned by GeohashCover when it chooses the precision.
+const geohashMaxCover = 16
+
+// ErrInvalidGeohash is returned when a geohash contains characters outside of the geohash alphabet.
+var ErrInvalidGeohash = errors.New("Invalid geohash")
+
+// GeohashEncode returns the geohash of the point with the given number of characters.
+// Details https://en.wikipedia.org/wiki/Geohash
+//  geo.GeohashEncode(geo.Point{Lat: 13.7665217, Lon: 100.6068431}, 9)
+func GeohashEncode(p Point, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+	var b strings.Builder
+	even := true
+	for b.Len() < precision {
+		var c byte
+		for bit := 4; bit >= 0; bit-- {
+			if even {
+				mid := (minLon + maxLon) / 2
+				if p.Lon >= mid {
+					c |= 1 << uint(bit)
+					minLon = mid
+				} else {
+					maxLon = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if p.Lat >= mid {
+					c |= 1 << uint(bit)
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+		b.WriteByte(geohashAlphabet[c])
+	}
+	return b.String()
+}
+
+// GeohashDecode returns the point at the center of the geohash cell.
+func GeohashDecode(hash string) (p Point, err error) {
+	box, err := GeohashBox(hash)
+	if err != nil {
+		return
+	}
+	p = box.Center()
+	return
+}
+
+// GeohashBox returns the bounding box of the geohash cell.
+func GeohashBox(hash string) (box BoundingBox, err error) {
+	box = BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		c := strings.IndexByte(geohashAlphabet, toLowerASCII(hash[i]))
+		if c < 0 {
+			err = ErrInvalidGeohash
+			return
+		}
+		for bit := 4; bit >= 0; bit-- {
+			on := c&(1<<uint(bit)) != 0
+			if even {
+				mid := (box.MinLon + box.MaxLon) / 2
+				if on {
+					box.MinLon = mid
+				} else {
+					box.MaxLon = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if on {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return
+}
+
+// GeohashNeighbor returns the adjacent geohash of the same precision in the given direction
+// (North, NorthEast, ..., NorthWest). Neighbors wrap around the antimeridian.
+// It returns an empty string when going north of the north pole or south of the south pole.
+func GeohashNeighbor(hash string, direction Direction) (string, error) {
+	box, err := GeohashBox(hash)
+	if err != nil {
+		return "", err
+	}
+	height := box.MaxLat - box.MinLat
+	width := box.MaxLon - box.MinLon
+	center := box.Center()
+
+	switch direction {
+	case North, NorthEast, NorthWest:
+		center.Lat += height
+	case South, SouthEast, SouthWest:
+		center.Lat -= height
+	}
+	switch direction {
+	case East, NorthEast, SouthEast:
+		center.Lon += width
+	case West, NorthWest, SouthWest:
+		center.Lon -= width
+	}
+	if center.Lat > 90 || center.Lat < -90 {
+		return "", nil
+	}
+	center.Lon = normalizeLongitude(center.Lon)
+	if center.Lon == 180 {
+		center.Lon = -180
+	}
+	return GeohashEncode(center, len(hash)), nil
+}
+
+// GeohashNeighbors returns the 8 adjacent geohashes in the order
+// North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest.
+// Neighbors beyond the poles are empty strings.
+func GeohashNeighbors(hash string) (neighbors [8]string, err error) {
+	for direction := range neighbors {
+		if neighbors[direction], err = GeohashNeighbor(hash, Direction(direction)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// GeohashCover returns the geohashes of the given precision that cover the circle of radius meters
+// around the point, so we can query a key-value store for all places near the point.
+// Only cells closer than radius from the point (computed with Distance) are returned.
+// If precision is 0, we use the finest precision that returns at most 16 cells. For very large circles
+// it can be the empty geohash, prefix of all geohashes.
+//  hashes := geo.GeohashCover(geo.Point{Lat: 13.7665217, Lon: 100.6068431}, 5000, 0)
+func GeohashCover(center Point, radius float64, precision int) []string {
+	if precision > 0 {
+		hashes, _ := geohashCover(center, radius, precision, 0)
+		return hashes
+	}
+	hashes := []string{""}
+	for precision = 1; precision <= 12; precision++ {
+		cover, ok := geohashCover(center, radius, precision, geohashMaxCover)
+		if !ok {
+			break
+		}
+		hashes = cover
+	}
+	return hashes
+}
+
+// geohashCover returns the cells of the given precision intersecting the circle.
+// It starts from the cell of the center and walks the neighbors.
+// If max is not 0, we stop and return false when more than max cells are found.
+func geohashCover(center Point, radius float64, precision, max int) (hashes []string, ok bool) {
+	start := GeohashEncode(center, precision)
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		hashes = append(hashes, hash)
+		if max > 0 && len(hashes) > max {
+			return nil, false
+		}
+		neighbors, _ := GeohashNeighbors(hash)
+		for _, n := range neighbors {
+			if n == "" || visited[n] {
+				continue
+			}
+			visited[n] = true
+			box, _ := GeohashBox(n)
+			if box.DistanceTo(center) <= radius {
+				queue = append(queue, n)
+			}
+		}
+	}
+	return hashes, true
+}
+
+// toLowerASCII returns the lower case of an ASCII letter.
+func toLowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,106 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestGeohashEncode(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         Point
+		precision int
+		want      string
+	}{
+		{name: "Wikipedia", p: Point{42.6, -5.6}, precision: 5, want: "ezs42"},
+		{name: "Jutland", p: Point{57.64911, 10.40744}, precision: 11, want: "u4pruydqqvj"},
+		{name: "Origin", p: Point{0, 0}, precision: 4, want: "s000"},
+		{name: "South west corner", p: Point{-90, -180}, precision: 6, want: "000000"},
+		{name: "North east corner", p: Point{90, 180}, precision: 6, want: "zzzzzz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GeohashEncode(tt.p, tt.precision)
+			if got != tt.want {
+				t.Errorf("GeohashEncode() = %v, want %v", got, tt.want)
+			}
+			box, err := GeohashBox(got)
+			if err != nil {
+				t.Fatalf("GeohashBox() error = %v", err)
+			}
+			if !box.Contains(tt.p) {
+				t.Errorf("GeohashBox() = %v does not contain %v", box, tt.p)
+			}
+		})
+	}
+}
+
+func TestGeohashDecode(t *testing.T) {
+	p, err := GeohashDecode("U4PRUYDQQVJ")
+	if err != nil {
+		t.Fatalf("GeohashDecode() error = %v", err)
+	}
+	if math.Abs(p.Lat-57.64911) > 1e-5 || math.Abs(p.Lon-10.40744) > 1e-5 {
+		t.Errorf("GeohashDecode() = %v", p)
+	}
+	if _, err := GeohashDecode("u4pa"); err != ErrInvalidGeohash {
+		t.Errorf("GeohashDecode() error = %v, want %v", err, ErrInvalidGeohash)
+	}
+}
+
+func TestGeohashNeighbors(t *testing.T) {
+	tests := []struct {
+		hash string
+		want [8]string
+	}{
+		{hash: "ezs42", want: [8]string{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"}},
+		{hash: "xbp", want: [8]string{"xbr", "802", "800", "2pb", "rzz", "rzy", "xbn", "xbq"}},
+		{hash: "bpb", want: [8]string{"", "", "bpc", "bp9", "bp8", "zzx", "zzz", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hash, func(t *testing.T) {
+			got, err := GeohashNeighbors(tt.hash)
+			if err != nil {
+				t.Fatalf("GeohashNeighbors() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GeohashNeighbors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeohashCover(t *testing.T) {
+	center := Point{13.7665217, 100.6068431}
+	hashes := GeohashCover(center, 5000, 0)
+	if len(hashes) == 0 || len(hashes) > geohashMaxCover {
+		t.Fatalf("GeohashCover() = %v", hashes)
+	}
+	if len(hashes[0]) != 5 {
+		t.Errorf("GeohashCover() precision = %v, want 5", len(hashes[0]))
+	}
+
+	// Every point within the radius must be in one of the cells
+	sort.Strings(hashes)
+	for bearing := 0.0; bearing < 360; bearing += 10 {
+		for _, distance := range []float64{0, 2500, 4999} {
+			hash := GeohashEncode(center.Destination(bearing, distance), len(hashes[0]))
+			if i := sort.SearchStrings(hashes, hash); i == len(hashes) || hashes[i] != hash {
+				t.Errorf("GeohashCover() = %v does not contain %v", hashes, hash)
+			}
+		}
+	}
+
+	// Circle across the antimeridian
+	hashes = GeohashCover(Point{0, 179.99}, 3000, 5)
+	var east, west bool
+	for _, hash := range hashes {
+		p, _ := GeohashDecode(hash)
+		east = east || p.Lon > 0
+		west = west || p.Lon < 0
+	}
+	if !east || !west {
+		t.Errorf("GeohashCover() = %v must be on both sides of the antimeridian", hashes)
+	}
+}
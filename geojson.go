@@ -0,0 +1,346 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type (
+	// Feature is a GeoJSON feature: a geometry with properties.
+	// Geometry can be nil for features without location.
+	Feature struct {
+		ID         interface{}            // ID is optional, it is a string or a number
+		Geometry   Geometry               // Geometry is the location of the feature
+		Properties map[string]interface{} // Properties are the attributes of the feature
+	}
+
+	// FeatureCollection is a GeoJSON collection of features.
+	FeatureCollection struct {
+		Features []Feature
+	}
+
+	// GeoJSONGeometry wraps a geometry to encode or decode it as a GeoJSON geometry object
+	// with encoding/json. Point has its own JSON encoding, so we use GeoJSONGeometry{p} to get GeoJSON.
+	GeoJSONGeometry struct {
+		Geometry Geometry
+	}
+
+	// geoJSONObject is used to decode any GeoJSON object.
+	geoJSONObject struct {
+		Type        string            `json:"type"`
+		Coordinates json.RawMessage   `json:"coordinates"`
+		Geometries  []json.RawMessage `json:"geometries"`
+		Geometry    json.RawMessage   `json:"geometry"`
+		Properties  json.RawMessage   `json:"properties"`
+		ID          interface{}       `json:"id"`
+		Features    []json.RawMessage `json:"features"`
+	}
+)
+
+// Types of GeoJSON objects other than geometries.
+const (
+	TypeFeature           = "Feature"
+	TypeFeatureCollection = "FeatureCollection"
+)
+
+// ErrInvalidGeoJSON is returned when decoding a GeoJSON object that does not follow RFC 7946.
+var ErrInvalidGeoJSON = errors.New("Invalid GeoJSON")
+
+// MarshalGeoJSON returns the GeoJSON encoding of the geometry (RFC 7946).
+// Rings of polygons are closed if needed.
+//  data, err := geo.MarshalGeoJSON(geo.LineString{{13.76, 100.50}, {13.89, 101.12}})
+//  // {"type":"LineString","coordinates":[[100.5,13.76],[101.12,13.89]]}
+func MarshalGeoJSON(g Geometry) ([]byte, error) {
+	return json.Marshal(GeoJSONGeometry{g})
+}
+
+// UnmarshalGeoJSON decodes a GeoJSON geometry object.
+//  g, err := geo.UnmarshalGeoJSON([]byte(`{"type":"Point","coordinates":[100.5,13.76]}`))
+//  p := g.(geo.Point)
+func UnmarshalGeoJSON(data []byte) (Geometry, error) {
+	var g GeoJSONGeometry
+	err := json.Unmarshal(data, &g)
+	return g.Geometry, err
+}
+
+// NewFeature returns a feature with the geometry. Properties are taken from the JSON encoding
+// of properties, so fields keep their JSON names. properties can be nil.
+//  f, err := geo.NewFeature(place.Point(), place)
+func NewFeature(g Geometry, properties interface{}) (f Feature, err error) {
+	f.Geometry = g
+	if properties == nil {
+		return
+	}
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &f.Properties)
+	return
+}
+
+// Feature returns the place as a GeoJSON feature, place fields are the properties.
+func (p Place) Feature() (Feature, error) {
+	f, err := NewFeature(p.Point(), p)
+	f.ID = p.PlaceID
+	return f, err
+}
+
+// Feature returns the address found at point p by Reverse as a GeoJSON feature.
+//  address, err := geo.Reverse(13.7665269, 100.6068431)
+//  f, err := address.Feature(geo.Point{Lat: 13.7665269, Lon: 100.6068431})
+func (n Nominatim) Feature(p Point) (Feature, error) {
+	return NewFeature(p, n)
+}
+
+// Feature returns the Google place as a GeoJSON feature, place fields are the properties.
+func (g GooglePlace) Feature() (Feature, error) {
+	f, err := NewFeature(g.Point(), g)
+	f.ID = g.PlaceID
+	return f, err
+}
+
+// Feature returns the IP location as a GeoJSON feature, ipapi fields are the properties.
+func (ip IPAPI) Feature() (Feature, error) {
+	f, err := NewFeature(ip.Point(), ip)
+	f.ID = ip.IP
+	return f, err
+}
+
+// MarshalJSON encodes the feature as GeoJSON.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	obj := map[string]interface{}{
+		"type":       TypeFeature,
+		"geometry":   nil,
+		"properties": f.Properties,
+	}
+	if f.Geometry != nil {
+		obj["geometry"] = GeoJSONGeometry{f.Geometry}
+	}
+	if f.ID != nil && f.ID != "" {
+		obj["id"] = f.ID
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON decodes a GeoJSON feature.
+func (f *Feature) UnmarshalJSON(data []byte) (err error) {
+	var obj geoJSONObject
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return
+	}
+	if obj.Type != TypeFeature {
+		return ErrInvalidGeoJSON
+	}
+	*f = Feature{ID: obj.ID}
+	if len(obj.Geometry) > 0 && string(obj.Geometry) != "null" {
+		if f.Geometry, err = UnmarshalGeoJSON(obj.Geometry); err != nil {
+			return
+		}
+	}
+	if len(obj.Properties) > 0 {
+		err = json.Unmarshal(obj.Properties, &f.Properties)
+	}
+	return
+}
+
+// MarshalJSON encodes the collection as GeoJSON.
+func (c FeatureCollection) MarshalJSON() ([]byte, error) {
+	features := c.Features
+	if features == nil {
+		features = []Feature{}
+	}
+	return json.Marshal(map[string]interface{}{
+		"type":     TypeFeatureCollection,
+		"features": features,
+	})
+}
+
+// UnmarshalJSON decodes a GeoJSON feature collection.
+func (c *FeatureCollection) UnmarshalJSON(data []byte) (err error) {
+	var obj geoJSONObject
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return
+	}
+	if obj.Type != TypeFeatureCollection {
+		return ErrInvalidGeoJSON
+	}
+	c.Features = make([]Feature, len(obj.Features))
+	for i, raw := range obj.Features {
+		if err = c.Features[i].UnmarshalJSON(raw); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// MarshalJSON encodes the geometry as a GeoJSON geometry object.
+func (g GeoJSONGeometry) MarshalJSON() ([]byte, error) {
+	if g.Geometry == nil {
+		return []byte("null"), nil
+	}
+	obj := map[string]interface{}{"type": g.Geometry.GeometryType()}
+	switch geom := g.Geometry.(type) {
+	case Point:
+		obj["coordinates"] = geoJSONPosition(geom)
+	case MultiPoint:
+		obj["coordinates"] = geoJSONPositions(geom, false)
+	case LineString:
+		obj["coordinates"] = geoJSONPositions(geom, false)
+	case MultiLineString:
+		lines := make([][][]float64, len(geom))
+		for i, l := range geom {
+			lines[i] = geoJSONPositions(l, false)
+		}
+		obj["coordinates"] = lines
+	case Polygon:
+		obj["coordinates"] = geoJSONPolygon(geom)
+	case MultiPolygon:
+		polygons := make([][][][]float64, len(geom))
+		for i, p := range geom {
+			polygons[i] = geoJSONPolygon(p)
+		}
+		obj["coordinates"] = polygons
+	case GeometryCollection:
+		geometries := make([]GeoJSONGeometry, len(geom))
+		for i, child := range geom {
+			geometries[i] = GeoJSONGeometry{child}
+		}
+		obj["geometries"] = geometries
+	default:
+		return nil, ErrInvalidGeoJSON
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON decodes a GeoJSON geometry object.
+func (g *GeoJSONGeometry) UnmarshalJSON(data []byte) (err error) {
+	var obj geoJSONObject
+	if err = json.Unmarshal(data, &obj); err != nil {
+		return
+	}
+	if obj.Type == TypeGeometryCollection {
+		collection := make(GeometryCollection, len(obj.Geometries))
+		for i, raw := range obj.Geometries {
+			if collection[i], err = UnmarshalGeoJSON(raw); err != nil {
+				return
+			}
+		}
+		g.Geometry = collection
+		return
+	}
+
+	switch obj.Type {
+	case TypePoint:
+		var c []float64
+		if err = json.Unmarshal(obj.Coordinates, &c); err == nil {
+			g.Geometry, err = geoJSONToPoint(c)
+		}
+	case TypeMultiPoint, TypeLineString:
+		var c [][]float64
+		var points []Point
+		if err = json.Unmarshal(obj.Coordinates, &c); err == nil {
+			points, err = geoJSONToPoints(c)
+		}
+		if obj.Type == TypeMultiPoint {
+			g.Geometry = MultiPoint(points)
+		} else {
+			g.Geometry = LineString(points)
+		}
+	case TypeMultiLineString:
+		var c [][][]float64
+		if err = json.Unmarshal(obj.Coordinates, &c); err == nil {
+			lines := make(MultiLineString, len(c))
+			for i := range c {
+				if lines[i], err = geoJSONToPoints(c[i]); err != nil {
+					return
+				}
+			}
+			g.Geometry = lines
+		}
+	case TypePolygon:
+		var c [][][]float64
+		if err = json.Unmarshal(obj.Coordinates, &c); err == nil {
+			g.Geometry, err = geoJSONToPolygon(c)
+		}
+	case TypeMultiPolygon:
+		var c [][][][]float64
+		if err = json.Unmarshal(obj.Coordinates, &c); err == nil {
+			polygons := make(MultiPolygon, len(c))
+			for i := range c {
+				if polygons[i], err = geoJSONToPolygon(c[i]); err != nil {
+					return
+				}
+			}
+			g.Geometry = polygons
+		}
+	default:
+		err = ErrInvalidGeoJSON
+	}
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		err = ErrInvalidGeoJSON
+	}
+	return
+}
+
+// geoJSONPosition returns the GeoJSON position of a point: longitude first.
+func geoJSONPosition(p Point) []float64 {
+	return []float64{p.Lon, p.Lat}
+}
+
+// geoJSONPositions returns GeoJSON positions of points, closing the ring if asked.
+func geoJSONPositions(points []Point, closed bool) [][]float64 {
+	positions := make([][]float64, 0, len(points)+1)
+	for _, p := range points {
+		positions = append(positions, geoJSONPosition(p))
+	}
+	if closed && len(points) > 0 && points[0] != points[len(points)-1] {
+		positions = append(positions, geoJSONPosition(points[0]))
+	}
+	return positions
+}
+
+// geoJSONPolygon returns GeoJSON coordinates of a polygon.
+func geoJSONPolygon(p Polygon) [][][]float64 {
+	rings := make([][][]float64, len(p))
+	for i, r := range p {
+		rings[i] = geoJSONPositions(r, true)
+	}
+	return rings
+}
+
+// geoJSONToPoint returns the point of a GeoJSON position. Altitude is ignored.
+func geoJSONToPoint(c []float64) (Point, error) {
+	if len(c) < 2 {
+		return Point{}, ErrInvalidGeoJSON
+	}
+	return Point{Lat: c[1], Lon: c[0]}, nil
+}
+
+// geoJSONToPoints returns points of GeoJSON positions.
+func geoJSONToPoints(c [][]float64) (points []Point, err error) {
+	points = make([]Point, len(c))
+	for i := range c {
+		if points[i], err = geoJSONToPoint(c[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// geoJSONToPolygon returns the polygon of GeoJSON coordinates.
+// Each ring must have at least 4 positions as required by the RFC.
+func geoJSONToPolygon(c [][][]float64) (p Polygon, err error) {
+	p = make(Polygon, len(c))
+	for i := range c {
+		if len(c[i]) < 4 {
+			return nil, ErrInvalidGeoJSON
+		}
+		var points []Point
+		if points, err = geoJSONToPoints(c[i]); err != nil {
+			return
+		}
+		p[i] = Ring(points)
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalGeoJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry Geometry
+		want     string
+	}{
+		{
+			name:     "Point",
+			geometry: Point{13.76, 100.5},
+			want:     `{"coordinates":[100.5,13.76],"type":"Point"}`,
+		},
+		{
+			name:     "MultiPoint",
+			geometry: MultiPoint{{13.76, 100.5}, {13.89, 101.12}},
+			want:     `{"coordinates":[[100.5,13.76],[101.12,13.89]],"type":"MultiPoint"}`,
+		},
+		{
+			name:     "LineString",
+			geometry: LineString{{13.76, 100.5}, {13.89, 101.12}},
+			want:     `{"coordinates":[[100.5,13.76],[101.12,13.89]],"type":"LineString"}`,
+		},
+		{
+			name:     "MultiLineString",
+			geometry: MultiLineString{{{13.76, 100.5}, {13.89, 101.12}}, {{1, 2}, {3, 4}}},
+			want:     `{"coordinates":[[[100.5,13.76],[101.12,13.89]],[[2,1],[4,3]]],"type":"MultiLineString"}`,
+		},
+		{
+			name:     "Polygon",
+			geometry: Polygon{Ring{{0, 0}, {0, 1}, {1, 1}}},
+			want:     `{"coordinates":[[[0,0],[1,0],[1,1],[0,0]]],"type":"Polygon"}`,
+		},
+		{
+			name:     "MultiPolygon",
+			geometry: MultiPolygon{{Ring{{0, 0}, {0, 1}, {1, 1}, {0, 0}}}, {square(0, 0, 4, 4), square(1, 1, 2, 2)}},
+			want: `{"coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[2,1],[2,2],[1,2],[1,1]]]],` +
+				`"type":"MultiPolygon"}`,
+		},
+		{
+			name:     "GeometryCollection",
+			geometry: GeometryCollection{Point{1, 2}, LineString{{1, 2}, {3, 4}}},
+			want:     `{"geometries":[{"coordinates":[2,1],"type":"Point"},{"coordinates":[[2,1],[4,3]],"type":"LineString"}],"type":"GeometryCollection"}`,
+		},
+		{
+			name:     "Empty GeometryCollection",
+			geometry: GeometryCollection{},
+			want:     `{"geometries":[],"type":"GeometryCollection"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalGeoJSON(tt.geometry)
+			if err != nil {
+				t.Fatalf("MarshalGeoJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalGeoJSON() = %s, want %s", got, tt.want)
+			}
+			back, err := UnmarshalGeoJSON(got)
+			if err != nil {
+				t.Fatalf("UnmarshalGeoJSON() error = %v", err)
+			}
+			again, _ := MarshalGeoJSON(back)
+			if string(again) != tt.want {
+				t.Errorf("UnmarshalGeoJSON() = %#v", back)
+			}
+		})
+	}
+}
+
+func TestUnmarshalGeoJSON_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`{"type":"Point","coordinates":[100.5]}`,
+		`{"type":"Point","coordinates":"100.5,13"}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+		`{"type":"Circle","coordinates":[0,0]}`,
+		`[]`,
+	} {
+		if _, err := UnmarshalGeoJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalGeoJSON(%s) error = nil", data)
+		}
+	}
+}
+
+func TestFeatureCollection(t *testing.T) {
+	// Example from RFC 7946
+	data := `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [102.0, 0.5]},
+			"properties": {"prop0": "value0"}
+		}, {
+			"type": "Feature",
+			"id": 12,
+			"geometry": {"type": "LineString", "coordinates": [[102.0, 0.0], [103.0, 1.0], [104.0, 0.0], [105.0, 1.0]]},
+			"properties": {"prop0": "value0", "prop1": 0.0}
+		}, {
+			"type": "Feature",
+			"geometry": null,
+			"properties": null
+		}]
+	}`
+	var c FeatureCollection
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(c.Features) != 3 {
+		t.Fatalf("Unmarshal() = %v features, want 3", len(c.Features))
+	}
+	if p, ok := c.Features[0].Geometry.(Point); !ok || p != (Point{0.5, 102}) {
+		t.Errorf("Unmarshal() geometry = %v", c.Features[0].Geometry)
+	}
+	if c.Features[0].Properties["prop0"] != "value0" || c.Features[1].ID != 12.0 {
+		t.Errorf("Unmarshal() properties = %v, id = %v", c.Features[0].Properties, c.Features[1].ID)
+	}
+	if c.Features[2].Geometry != nil || c.Features[2].Properties != nil {
+		t.Errorf("Unmarshal() = %v", c.Features[2])
+	}
+
+	out, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var back FeatureCollection
+	if err := json.Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(c, back) {
+		t.Errorf("Marshal() = %s", out)
+	}
+}
+
+func TestPlace_Feature(t *testing.T) {
+	place := Place{Lat: 13.7665217, Long: 100.6068431, PlaceID: "42", DisplayName: "Frontware", Importance: 0.5}
+	f, err := place.Feature()
+	if err != nil {
+		t.Fatalf("Feature() error = %v", err)
+	}
+	if f.ID != "42" || f.Geometry != place.Point() || f.Properties["display_name"] != "Frontware" || f.Properties["importance"] != 0.5 {
+		t.Errorf("Feature() = %v", f)
+	}
+	out, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var back Feature
+	if err := json.Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(f, back) {
+		t.Errorf("Marshal() = %s", out)
+	}
+
+	n := Nominatim{DisplayName: "Frontware", Address: &Address{City: "Bangkok"}}
+	if f, err = n.Feature(place.Point()); err != nil {
+		t.Fatalf("Feature() error = %v", err)
+	}
+	if address, ok := f.Properties["address"].(map[string]interface{}); !ok || address["city"] != "Bangkok" {
+		t.Errorf("Feature() = %v", f)
+	}
+}
@@ -0,0 +1,59 @@
+package geo
+
+type (
+	// Geometry is implemented by all geometry types of the package:
+	// Point, MultiPoint, LineString, MultiLineString, Polygon, MultiPolygon and GeometryCollection.
+	Geometry interface {
+		// GeometryType returns the name of the geometry type as used by GeoJSON and WKT.
+		GeometryType() string
+	}
+
+	// LineString is a line joining points, such as a route.
+	LineString []Point
+
+	// MultiPoint is a set of points.
+	MultiPoint []Point
+
+	// MultiLineString is a set of lines.
+	MultiLineString []LineString
+
+	// GeometryCollection is a set of geometries of any type.
+	GeometryCollection []Geometry
+)
+
+// Names of geometry types.
+const (
+	TypePoint              = "Point"
+	TypeMultiPoint         = "MultiPoint"
+	TypeLineString         = "LineString"
+	TypeMultiLineString    = "MultiLineString"
+	TypePolygon            = "Polygon"
+	TypeMultiPolygon       = "MultiPolygon"
+	TypeGeometryCollection = "GeometryCollection"
+)
+
+// GeometryType returns "Point".
+func (p Point) GeometryType() string { return TypePoint }
+
+// GeometryType returns "MultiPoint".
+func (m MultiPoint) GeometryType() string { return TypeMultiPoint }
+
+// GeometryType returns "LineString".
+func (l LineString) GeometryType() string { return TypeLineString }
+
+// GeometryType returns "MultiLineString".
+func (m MultiLineString) GeometryType() string { return TypeMultiLineString }
+
+// GeometryType returns "Polygon".
+func (p Polygon) GeometryType() string { return TypePolygon }
+
+// GeometryType returns "MultiPolygon".
+func (m MultiPolygon) GeometryType() string { return TypeMultiPolygon }
+
+// GeometryType returns "GeometryCollection".
+func (c GeometryCollection) GeometryType() string { return TypeGeometryCollection }
+
+// Length returns the length of the line in meters, computed with Distance.
+func (l LineString) Length() float64 {
+	return PathLength(l)
+}
@@ -0,0 +1,361 @@
+package geo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TIFF tags read from GeoTIFF elevation models
+const (
+	tiffImageWidth      = 256
+	tiffImageLength     = 257
+	tiffBitsPerSample   = 258
+	tiffCompression     = 259
+	tiffStripOffsets    = 273
+	tiffSamplesPerPixel = 277
+	tiffRowsPerStrip    = 278
+	tiffStripByteCounts = 279
+	tiffPredictor       = 317
+	tiffTileWidth       = 322
+	tiffTileLength      = 323
+	tiffTileOffsets     = 324
+	tiffTileByteCounts  = 325
+	tiffSampleFormat    = 339
+	tiffPixelScale      = 33550
+	tiffTiepoint        = 33922
+	tiffGeoKeyDirectory = 34735
+	tiffGDALNoData      = 42113
+)
+
+// GeoTIFF keys
+const (
+	geoKeyModelType       = 1024
+	geoKeyRasterType      = 1025
+	geoKeyGeographicType  = 2048
+	geoKeyProjectedCSType = 3072
+
+	geoModelProjected     = 1
+	geoModelGeographic    = 2
+	geoRasterPixelIsPoint = 2
+)
+
+// tiffTypeSizes are the sizes in bytes of the values of TIFF field types: bytes, ASCII, shorts, longs,
+// signed bytes, shorts and longs, floats, doubles and long8.
+var tiffTypeSizes = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 6: 1, 8: 2, 9: 4, 11: 4, 12: 8, 16: 8}
+
+// ErrUnsupportedGeoTIFF is returned for GeoTIFF files using features not supported by ReadGeoTIFF.
+var ErrUnsupportedGeoTIFF = errors.New("Unsupported GeoTIFF")
+
+// geoTIFF holds the header of a GeoTIFF file.
+type geoTIFF struct {
+	r     io.ReaderAt
+	order binary.ByteOrder
+
+	width, height       int
+	bits, format        int
+	compression         int
+	predictor           int
+	chunkWidth          int
+	chunkHeight         int
+	offsets, byteCounts []float64
+
+	x, y, xStep, yStep float64
+	crs                CRS
+	noData             float64
+}
+
+// ReadGeoTIFF reads a GeoTIFF elevation model of a single band in a geographic coordinate reference system,
+// or a projected one registered with RegisterEPSG. Samples are 8 to 64 bit integers or floats, in strips or tiles,
+// uncompressed or compressed with Deflate. Samples equal to the GDAL no data value are NaN.
+//  file, _ := os.Open("dem.tif")
+//  dem, err := geo.ReadGeoTIFF(file)
+func ReadGeoTIFF(r io.ReaderAt) (*DEM, error) {
+	t, err := readGeoTIFFHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	dem := t.dem()
+	dem.Samples = make([]float32, t.width*t.height)
+
+	across := (t.width + t.chunkWidth - 1) / t.chunkWidth
+	size := t.bits / 8
+	for i, offset := range t.offsets {
+		data := make([]byte, int(t.byteCounts[i]))
+		if _, err := r.ReadAt(data, int64(offset)); err != nil {
+			return nil, err
+		}
+		if t.compression != 1 {
+			z, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			if data, err = ioutil.ReadAll(z); err != nil {
+				return nil, err
+			}
+		}
+
+		// Chunks are strips of the width of the image or tiles, last strips may be shorter
+		x0, y0 := i%across*t.chunkWidth, i/across*t.chunkHeight
+		rows := len(data) / (t.chunkWidth * size)
+		if rows > t.chunkHeight {
+			rows = t.chunkHeight
+		}
+		for row := 0; row < rows && y0+row < t.height; row++ {
+			line := data[row*t.chunkWidth*size : (row+1)*t.chunkWidth*size]
+			if t.predictor == 2 {
+				t.undoPredictor(line)
+			}
+			for col := 0; col < t.chunkWidth && x0+col < t.width; col++ {
+				v := t.sample(line[col*size:])
+				if v == t.noData || math.IsNaN(v) {
+					v = math.NaN()
+				}
+				dem.Samples[(y0+row)*t.width+x0+col] = float32(v)
+			}
+		}
+	}
+	return dem, nil
+}
+
+// dem returns the elevation model described by the header, without samples.
+func (t *geoTIFF) dem() *DEM {
+	return &DEM{Width: t.width, Height: t.height, X: t.x, Y: t.y, XStep: t.xStep, YStep: t.yStep, CRS: t.crs}
+}
+
+// readGeoTIFFHeader reads the first image file directory and the georeferencing of a GeoTIFF file.
+func readGeoTIFFHeader(r io.ReaderAt) (*geoTIFF, error) {
+	header := make([]byte, 8)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, ErrInvalidDEM
+	}
+	t := &geoTIFF{r: r, compression: 1, predictor: 1, format: 1, noData: math.NaN()}
+	switch string(header[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return nil, ErrInvalidDEM
+	}
+	if magic := t.order.Uint16(header[2:]); magic == 43 {
+		// BigTIFF
+		return nil, ErrUnsupportedGeoTIFF
+	} else if magic != 42 {
+		return nil, ErrInvalidDEM
+	}
+
+	tags, ascii, err := t.readTags(int64(t.order.Uint32(header[4:])))
+	if err != nil {
+		return nil, err
+	}
+	number := func(tag int) int {
+		if v := tags[tag]; len(v) > 0 {
+			return int(v[0])
+		}
+		return 0
+	}
+	t.width, t.height = number(tiffImageWidth), number(tiffImageLength)
+	t.bits = number(tiffBitsPerSample)
+	if v := number(tiffSampleFormat); v != 0 {
+		t.format = v
+	}
+	if v := number(tiffCompression); v != 0 {
+		t.compression = v
+	}
+	if v := number(tiffPredictor); v != 0 {
+		t.predictor = v
+	}
+	// Deflate is compression 8, or 32946 for old files
+	if number(tiffSamplesPerPixel) > 1 || (t.compression != 1 && t.compression != 8 && t.compression != 32946) ||
+		(t.predictor != 1 && t.predictor != 2) || (t.predictor == 2 && t.format == 3) ||
+		(t.bits != 8 && t.bits != 16 && t.bits != 32 && t.bits != 64) || t.format < 1 || t.format > 3 || (t.format == 3 && t.bits < 32) {
+		return nil, ErrUnsupportedGeoTIFF
+	}
+	if t.width <= 0 || t.height <= 0 {
+		return nil, ErrInvalidDEM
+	}
+
+	if _, ok := tags[tiffTileOffsets]; ok {
+		t.chunkWidth, t.chunkHeight = number(tiffTileWidth), number(tiffTileLength)
+		t.offsets, t.byteCounts = tags[tiffTileOffsets], tags[tiffTileByteCounts]
+	} else {
+		t.chunkWidth, t.chunkHeight = t.width, number(tiffRowsPerStrip)
+		if t.chunkHeight <= 0 || t.chunkHeight > t.height {
+			t.chunkHeight = t.height
+		}
+		t.offsets, t.byteCounts = tags[tiffStripOffsets], tags[tiffStripByteCounts]
+	}
+	if t.chunkWidth <= 0 || t.chunkHeight <= 0 {
+		return nil, ErrInvalidDEM
+	}
+	chunks := ((t.width + t.chunkWidth - 1) / t.chunkWidth) * ((t.height + t.chunkHeight - 1) / t.chunkHeight)
+	if len(t.offsets) != chunks || len(t.byteCounts) != chunks {
+		return nil, ErrInvalidDEM
+	}
+	for _, n := range t.byteCounts {
+		if n > 1<<30 {
+			return nil, ErrInvalidDEM
+		}
+	}
+
+	if v, err := strconv.ParseFloat(strings.TrimSpace(ascii[tiffGDALNoData]), 64); err == nil {
+		t.noData = v
+	}
+	if err := t.georeference(tags); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// georeference reads the position of the image and its coordinate reference system.
+func (t *geoTIFF) georeference(tags map[int][]float64) error {
+	scale, tiepoint := tags[tiffPixelScale], tags[tiffTiepoint]
+	if len(scale) < 2 || len(tiepoint) < 6 || scale[0] <= 0 || scale[1] <= 0 {
+		return ErrUnsupportedGeoTIFF
+	}
+
+	keys := map[int]int{}
+	if dir := tags[tiffGeoKeyDirectory]; len(dir) >= 4 {
+		for i := 4; i+3 < len(dir) && i < 4+4*int(dir[3]); i += 4 {
+			// Keys stored in other tags are not needed
+			if dir[i+1] == 0 {
+				keys[int(dir[i])] = int(dir[i+3])
+			}
+		}
+	}
+	switch keys[geoKeyModelType] {
+	case geoModelGeographic, 0:
+		if code := keys[geoKeyGeographicType]; code != 0 && code != 4326 && code != 32767 {
+			crs, err := EPSG(code)
+			if err != nil {
+				return ErrUnsupportedGeoTIFF
+			}
+			t.crs = crs
+		}
+	case geoModelProjected:
+		crs, err := EPSG(keys[geoKeyProjectedCSType])
+		if err != nil {
+			return ErrUnsupportedGeoTIFF
+		}
+		t.crs = crs
+	default:
+		return ErrUnsupportedGeoTIFF
+	}
+
+	// The tie point is the corner of the pixel, unless pixels are points
+	t.xStep, t.yStep = scale[0], scale[1]
+	t.x = tiepoint[3] - tiepoint[0]*t.xStep
+	t.y = tiepoint[4] + tiepoint[1]*t.yStep
+	if keys[geoKeyRasterType] != geoRasterPixelIsPoint {
+		t.x += t.xStep / 2
+		t.y -= t.yStep / 2
+	}
+	return nil
+}
+
+// readTags reads the tags of the image file directory at offset, numbers as floats and ASCII values as strings.
+func (t *geoTIFF) readTags(offset int64) (tags map[int][]float64, ascii map[int]string, err error) {
+	b := make([]byte, 2)
+	if _, err := t.r.ReadAt(b, offset); err != nil {
+		return nil, nil, ErrInvalidDEM
+	}
+	entries := make([]byte, 12*int(t.order.Uint16(b)))
+	if _, err := t.r.ReadAt(entries, offset+2); err != nil {
+		return nil, nil, ErrInvalidDEM
+	}
+
+	tags, ascii = map[int][]float64{}, map[int]string{}
+	for e := entries; len(e) >= 12; e = e[12:] {
+		tag, kind, count := int(t.order.Uint16(e)), t.order.Uint16(e[2:]), int(t.order.Uint32(e[4:]))
+		size := tiffTypeSizes[kind]
+		if size == 0 || count < 0 || count > 1<<28/size {
+			continue
+		}
+		data := e[8:12]
+		if count*size > 4 {
+			data = make([]byte, count*size)
+			if _, err := t.r.ReadAt(data, int64(t.order.Uint32(e[8:]))); err != nil {
+				return nil, nil, ErrInvalidDEM
+			}
+		}
+		if kind == 2 {
+			ascii[tag] = strings.TrimRight(string(data[:count]), "\x00")
+			continue
+		}
+		values := make([]float64, count)
+		for i := range values {
+			v := data[i*size:]
+			switch kind {
+			case 1:
+				values[i] = float64(v[0])
+			case 6:
+				values[i] = float64(int8(v[0]))
+			case 3:
+				values[i] = float64(t.order.Uint16(v))
+			case 8:
+				values[i] = float64(int16(t.order.Uint16(v)))
+			case 4:
+				values[i] = float64(t.order.Uint32(v))
+			case 9:
+				values[i] = float64(int32(t.order.Uint32(v)))
+			case 11:
+				values[i] = float64(math.Float32frombits(t.order.Uint32(v)))
+			case 12:
+				values[i] = math.Float64frombits(t.order.Uint64(v))
+			case 16:
+				values[i] = float64(t.order.Uint64(v))
+			}
+		}
+		tags[tag] = values
+	}
+	return tags, ascii, nil
+}
+
+// sample returns the value of the sample at the start of b.
+func (t *geoTIFF) sample(b []byte) float64 {
+	switch {
+	case t.bits == 8 && t.format == 2:
+		return float64(int8(b[0]))
+	case t.bits == 8:
+		return float64(b[0])
+	case t.bits == 16 && t.format == 2:
+		return float64(int16(t.order.Uint16(b)))
+	case t.bits == 16:
+		return float64(t.order.Uint16(b))
+	case t.bits == 32 && t.format == 3:
+		return float64(math.Float32frombits(t.order.Uint32(b)))
+	case t.bits == 32 && t.format == 2:
+		return float64(int32(t.order.Uint32(b)))
+	case t.bits == 32:
+		return float64(t.order.Uint32(b))
+	case t.format == 3:
+		return math.Float64frombits(t.order.Uint64(b))
+	case t.format == 2:
+		return float64(int64(t.order.Uint64(b)))
+	}
+	return float64(t.order.Uint64(b))
+}
+
+// undoPredictor restores the integer samples of a row stored as differences with the previous sample.
+func (t *geoTIFF) undoPredictor(line []byte) {
+	size := t.bits / 8
+	for i := size; i+size <= len(line); i += size {
+		switch size {
+		case 1:
+			line[i] += line[i-1]
+		case 2:
+			t.order.PutUint16(line[i:], t.order.Uint16(line[i:])+t.order.Uint16(line[i-2:]))
+		case 4:
+			t.order.PutUint32(line[i:], t.order.Uint32(line[i:])+t.order.Uint32(line[i-4:]))
+		case 8:
+			t.order.PutUint64(line[i:], t.order.Uint64(line[i:])+t.order.Uint64(line[i-8:]))
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package geo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// testGeoTIFF describes a GeoTIFF file written by encode.
+type testGeoTIFF struct {
+	order                 binary.ByteOrder
+	width, height         int
+	bits, format          int
+	tileWidth, tileHeight int // strips of 2 rows when 0
+	deflate, predictor    bool
+	scale, tiepoint       []float64
+	geoKeys               []uint16
+	noData                string
+	sample                func(col, row int) float64
+}
+
+// encode returns a TIFF file with the samples in strips or tiles, then the image file directory.
+func (g testGeoTIFF) encode() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(map[bool]string{true: "II", false: "MM"}[g.order == binary.LittleEndian])
+	binary.Write(&buf, g.order, uint16(42))
+	binary.Write(&buf, g.order, uint32(0)) // IFD offset, set at the end
+
+	chunkWidth, chunkHeight := g.tileWidth, g.tileHeight
+	if chunkWidth == 0 {
+		chunkWidth, chunkHeight = g.width, 2
+	}
+	var offsets, counts []uint32
+	for y0 := 0; y0 < g.height; y0 += chunkHeight {
+		for x0 := 0; x0 < g.width; x0 += chunkWidth {
+			var chunk bytes.Buffer
+			for row := y0; row < y0+chunkHeight && (g.tileWidth > 0 || row < g.height); row++ {
+				prev := uint64(0)
+				for col := x0; col < x0+chunkWidth; col++ {
+					v := 0.0
+					if col < g.width && row < g.height {
+						v = g.sample(col, row)
+					}
+					var u uint64
+					switch g.format {
+					case 3:
+						if g.bits == 32 {
+							u = uint64(math.Float32bits(float32(v)))
+						} else {
+							u = math.Float64bits(v)
+						}
+					default:
+						u = uint64(int64(v))
+					}
+					w := u
+					if g.predictor {
+						w = u - prev
+						prev = u
+					}
+					switch g.bits {
+					case 8:
+						chunk.WriteByte(byte(w))
+					case 16:
+						binary.Write(&chunk, g.order, uint16(w))
+					case 32:
+						binary.Write(&chunk, g.order, uint32(w))
+					default:
+						binary.Write(&chunk, g.order, w)
+					}
+				}
+			}
+			data := chunk.Bytes()
+			if g.deflate {
+				var z bytes.Buffer
+				zw := zlib.NewWriter(&z)
+				zw.Write(data)
+				zw.Close()
+				data = z.Bytes()
+			}
+			offsets = append(offsets, uint32(buf.Len()))
+			counts = append(counts, uint32(len(data)))
+			buf.Write(data)
+		}
+	}
+
+	type entry struct {
+		tag, kind uint16
+		values    interface{}
+		count     int
+	}
+	compression, predictor := uint16(1), uint16(1)
+	if g.deflate {
+		compression = 8
+	}
+	if g.predictor {
+		predictor = 2
+	}
+	entries := []entry{
+		{tiffImageWidth, 4, []uint32{uint32(g.width)}, 1},
+		{tiffImageLength, 4, []uint32{uint32(g.height)}, 1},
+		{tiffBitsPerSample, 3, []uint16{uint16(g.bits)}, 1},
+		{tiffCompression, 3, []uint16{compression}, 1},
+		{tiffSamplesPerPixel, 3, []uint16{1}, 1},
+		{tiffPredictor, 3, []uint16{predictor}, 1},
+		{tiffSampleFormat, 3, []uint16{uint16(g.format)}, 1},
+		{tiffPixelScale, 12, g.scale, len(g.scale)},
+		{tiffTiepoint, 12, g.tiepoint, len(g.tiepoint)},
+	}
+	if g.tileWidth > 0 {
+		entries = append(entries, entry{tiffTileWidth, 3, []uint16{uint16(g.tileWidth)}, 1}, entry{tiffTileLength, 3, []uint16{uint16(g.tileHeight)}, 1},
+			entry{tiffTileOffsets, 4, offsets, len(offsets)}, entry{tiffTileByteCounts, 4, counts, len(counts)})
+	} else {
+		entries = append(entries, entry{tiffRowsPerStrip, 3, []uint16{2}, 1},
+			entry{tiffStripOffsets, 4, offsets, len(offsets)}, entry{tiffStripByteCounts, 4, counts, len(counts)})
+	}
+	if g.geoKeys != nil {
+		entries = append(entries, entry{tiffGeoKeyDirectory, 3, g.geoKeys, len(g.geoKeys)})
+	}
+	if g.noData != "" {
+		entries = append(entries, entry{tiffGDALNoData, 2, []byte(g.noData + "\x00"), len(g.noData) + 1})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+
+	if buf.Len()%2 == 1 {
+		buf.WriteByte(0)
+	}
+	ifd := buf.Len()
+	g.order.PutUint32(buf.Bytes()[4:], uint32(ifd))
+	extra := ifd + 2 + 12*len(entries) + 4
+	var values bytes.Buffer
+	binary.Write(&buf, g.order, uint16(len(entries)))
+	for _, e := range entries {
+		var v bytes.Buffer
+		binary.Write(&v, g.order, e.values)
+		binary.Write(&buf, g.order, e.tag)
+		binary.Write(&buf, g.order, e.kind)
+		binary.Write(&buf, g.order, uint32(e.count))
+		if v.Len() <= 4 {
+			buf.Write(append(v.Bytes(), make([]byte, 4-v.Len())...))
+			continue
+		}
+		binary.Write(&buf, g.order, uint32(extra+values.Len()))
+		values.Write(v.Bytes())
+	}
+	binary.Write(&buf, g.order, uint32(0))
+	buf.Write(values.Bytes())
+	return buf.Bytes()
+}
+
+// geographicKeys are the GeoTIFF keys of WGS84 longitudes and latitudes, with pixels as areas.
+var geographicKeys = []uint16{1, 1, 0, 3, geoKeyModelType, 0, 1, geoModelGeographic, geoKeyRasterType, 0, 1, 1, geoKeyGeographicType, 0, 1, 4326}
+
+func TestReadGeoTIFF(t *testing.T) {
+	// Elevations grow by 1 meter per column and 10 meters per row, 0.01 degree pixels from 100E 14N
+	base := testGeoTIFF{
+		order: binary.LittleEndian, width: 7, height: 5, bits: 16, format: 2,
+		scale: []float64{0.01, 0.01, 0}, tiepoint: []float64{0, 0, 0, 100, 14, 0}, geoKeys: geographicKeys,
+		sample: func(col, row int) float64 { return float64(col + 10*row) },
+	}
+	variants := map[string]func(g *testGeoTIFF){
+		"strips":      func(g *testGeoTIFF) {},
+		"big endian":  func(g *testGeoTIFF) { g.order = binary.BigEndian },
+		"tiles":       func(g *testGeoTIFF) { g.tileWidth, g.tileHeight = 16, 16 },
+		"small tiles": func(g *testGeoTIFF) { g.tileWidth, g.tileHeight = 16, 2 },
+		"deflate":     func(g *testGeoTIFF) { g.deflate, g.predictor = true, true },
+		"uint8":       func(g *testGeoTIFF) { g.bits, g.format = 8, 1 },
+		"int32":       func(g *testGeoTIFF) { g.bits, g.deflate = 32, true },
+		"float32":     func(g *testGeoTIFF) { g.bits, g.format = 32, 3 },
+		"float64":     func(g *testGeoTIFF) { g.bits, g.format, g.order = 64, 3, binary.BigEndian },
+		"no keys":     func(g *testGeoTIFF) { g.geoKeys = nil },
+	}
+	for name, variant := range variants {
+		g := base
+		variant(&g)
+		dem, err := ReadGeoTIFF(bytes.NewReader(g.encode()))
+		if err != nil {
+			t.Errorf("%v: ReadGeoTIFF() error = %v", name, err)
+			continue
+		}
+		// Samples are at the centers of pixels
+		if dem.Width != 7 || dem.Height != 5 || math.Abs(dem.X-100.005) > 1e-12 || math.Abs(dem.Y-13.995) > 1e-12 || dem.CRS != nil {
+			t.Errorf("%v: ReadGeoTIFF() = %+v", name, dem)
+			continue
+		}
+		for i, v := range dem.Samples {
+			if v != float32(i%7+10*(i/7)) {
+				t.Errorf("%v: sample %v = %v", name, i, v)
+				break
+			}
+		}
+		if v, err := dem.Elevation(Point{13.98, 100.02}); err != nil || math.Abs(v-16.5) > 1e-6 {
+			t.Errorf("%v: Elevation() = %v, %v, want 16.5", name, v, err)
+		}
+	}
+}
+
+func TestReadGeoTIFF_file(t *testing.T) {
+	// Grid of testdata/geotiff/int16_4326.asc, 0.25 degree pixels from 100E 14N
+	f, err := os.Open(filepath.Join("testdata", "geotiff", "int16_4326.tif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dem, err := ReadGeoTIFF(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dem.Width != 4 || dem.Height != 3 || dem.X != 100.125 || dem.Y != 13.875 || dem.XStep != 0.25 || dem.YStep != 0.25 || dem.CRS != nil {
+		t.Fatalf("ReadGeoTIFF() = %+v", dem)
+	}
+	want := []float64{120, 125, 131, math.NaN(), 118, -5, 0, 140, 1000, 999, 998, 997}
+	for i, v := range dem.Samples {
+		if float64(v) != want[i] && !(math.IsNaN(float64(v)) && math.IsNaN(want[i])) {
+			t.Errorf("sample %v = %v, want %v", i, v, want[i])
+		}
+	}
+	if v, err := dem.Elevation(Point{13.625, 100.25}); err != nil || math.Abs(v-56.5) > 1e-9 {
+		t.Errorf("Elevation() = %v, %v, want 56.5", v, err)
+	}
+	if _, err := dem.Elevation(Point{13.875, 100.875}); err != ErrNoElevation {
+		t.Errorf("Elevation(no data) error = %v", err)
+	}
+}
+
+func TestReadGeoTIFF_georeference(t *testing.T) {
+	g := testGeoTIFF{
+		order: binary.LittleEndian, width: 4, height: 4, bits: 32, format: 3, noData: "-9999",
+		scale: []float64{30, 30, 0}, tiepoint: []float64{0, 0, 0, 660000, 1522000, 0},
+		// UTM zone 47N, pixels as points
+		geoKeys: []uint16{1, 1, 0, 3, geoKeyModelType, 0, 1, geoModelProjected, geoKeyRasterType, 0, 1, geoRasterPixelIsPoint, geoKeyProjectedCSType, 0, 1, 32647},
+		sample: func(col, row int) float64 {
+			if col >= 2 && row >= 2 {
+				return -9999
+			}
+			return 2
+		},
+	}
+	dem, err := ReadGeoTIFF(bytes.NewReader(g.encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dem.X != 660000 || dem.Y != 1522000 || dem.CRS == nil || !math.IsNaN(float64(dem.Samples[15])) {
+		t.Fatalf("ReadGeoTIFF() = %+v", dem)
+	}
+	p := dem.CRS.ToWGS84(660045, 1521955)
+	if v, err := dem.Elevation(p); err != nil || math.Abs(v-2) > 1e-9 {
+		t.Errorf("Elevation(%v) = %v, %v", p, v, err)
+	}
+	if _, err := dem.Elevation(dem.CRS.ToWGS84(660075, 1521925)); err != ErrNoElevation {
+		t.Errorf("Elevation(no data) error = %v", err)
+	}
+	box := dem.BoundingBox()
+	if !box.Contains(p) || box.Contains(dem.CRS.ToWGS84(659900, 1522000)) {
+		t.Errorf("BoundingBox() = %v", box)
+	}
+
+	// Coordinate reference systems missing from the registry
+	g.geoKeys[len(g.geoKeys)-1] = 2000
+	if _, err := ReadGeoTIFF(bytes.NewReader(g.encode())); err != ErrUnsupportedGeoTIFF {
+		t.Errorf("ReadGeoTIFF(unknown EPSG) error = %v", err)
+	}
+	g.geoKeys = geographicKeys
+	g.bits, g.format, g.predictor = 32, 3, true
+	if _, err := ReadGeoTIFF(bytes.NewReader(g.encode())); err != ErrUnsupportedGeoTIFF {
+		t.Errorf("ReadGeoTIFF(float predictor) error = %v", err)
+	}
+	for _, data := range [][]byte{nil, []byte("II*\x00\xff\xff\xff\xff"), []byte("PK\x03\x04\x00\x00\x00\x00")} {
+		if _, err := ReadGeoTIFF(bytes.NewReader(data)); err != ErrInvalidDEM {
+			t.Errorf("ReadGeoTIFF(%q) error = %v", data, err)
+		}
+	}
+}
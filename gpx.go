@@ -0,0 +1,697 @@
+package geo
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GPXNamespace is the XML namespace of GPX 1.1 documents.
+const GPXNamespace = "http://www.topografix.com/GPX/1/1"
+
+// gpxCreator is the creator of GPX documents written without creator.
+const gpxCreator = "github.com/frontware/geo"
+
+type (
+	// GPX is a GPX 1.1 document with waypoints, routes and tracks.
+	// Details https://www.topografix.com/GPX/1/1/
+	GPX struct {
+		Version   string        `xml:"version,attr"`
+		Creator   string        `xml:"creator,attr"`
+		Metadata  GPXMetadata   `xml:"metadata"`
+		Waypoints []GPXWaypoint `xml:"wpt"`
+		Routes    []GPXRoute    `xml:"rte"`
+		Tracks    []GPXTrack    `xml:"trk"`
+		// Extensions holds elements of other namespaces, such as Garmin extensions.
+		Extensions GPXExtensions `xml:"extensions"`
+		// Namespaces maps the prefixes of the namespaces used by extensions to their URIs,
+		// such as gpxtpx to http://www.garmin.com/xmlschemas/TrackPointExtension/v1.
+		Namespaces map[string]string `xml:"-"`
+	}
+
+	// GPXMetadata describes a GPX document.
+	GPXMetadata struct {
+		Name       string        `xml:"name"`
+		Desc       string        `xml:"desc"`
+		Author     GPXPerson     `xml:"author"`
+		Copyright  GPXCopyright  `xml:"copyright"`
+		Links      []GPXLink     `xml:"link"`
+		Time       GPXTime       `xml:"time"`
+		Keywords   string        `xml:"keywords"`
+		Bounds     *GPXBounds    `xml:"bounds"`
+		Extensions GPXExtensions `xml:"extensions"`
+	}
+
+	// GPXWaypoint is a waypoint, a point of a route or a point of a track.
+	// Ele is nil and Time is the zero time when unknown.
+	GPXWaypoint struct {
+		Lat float64 `xml:"lat,attr"`
+		Lon float64 `xml:"lon,attr"`
+		// Ele is the elevation in meters.
+		Ele  *float64 `xml:"ele"`
+		Time GPXTime  `xml:"time"`
+		// MagVar is the magnetic variation in degrees and GeoidHeight the height of the geoid above the ellipsoid in meters.
+		MagVar      float64   `xml:"magvar"`
+		GeoidHeight float64   `xml:"geoidheight"`
+		Name        string    `xml:"name"`
+		Cmt         string    `xml:"cmt"`
+		Desc        string    `xml:"desc"`
+		Src         string    `xml:"src"`
+		Links       []GPXLink `xml:"link"`
+		Sym         string    `xml:"sym"`
+		Type        string    `xml:"type"`
+		// Fix is none, 2d, 3d, dgps or pps.
+		Fix           string        `xml:"fix"`
+		Sat           int           `xml:"sat"`
+		HDOP          float64       `xml:"hdop"`
+		VDOP          float64       `xml:"vdop"`
+		PDOP          float64       `xml:"pdop"`
+		AgeOfDGPSData float64       `xml:"ageofdgpsdata"`
+		DGPSID        int           `xml:"dgpsid"`
+		Extensions    GPXExtensions `xml:"extensions"`
+	}
+
+	// GPXRoute is a route, an ordered list of points to follow.
+	GPXRoute struct {
+		Name       string        `xml:"name"`
+		Cmt        string        `xml:"cmt"`
+		Desc       string        `xml:"desc"`
+		Src        string        `xml:"src"`
+		Links      []GPXLink     `xml:"link"`
+		Number     int           `xml:"number"`
+		Type       string        `xml:"type"`
+		Extensions GPXExtensions `xml:"extensions"`
+		Points     []GPXWaypoint `xml:"rtept"`
+	}
+
+	// GPXTrack is a track, the recorded path of a trip in segments.
+	// A new segment starts when the GPS receiver lost its fix or was turned off.
+	GPXTrack struct {
+		Name       string        `xml:"name"`
+		Cmt        string        `xml:"cmt"`
+		Desc       string        `xml:"desc"`
+		Src        string        `xml:"src"`
+		Links      []GPXLink     `xml:"link"`
+		Number     int           `xml:"number"`
+		Type       string        `xml:"type"`
+		Extensions GPXExtensions `xml:"extensions"`
+		Segments   []GPXSegment  `xml:"trkseg"`
+	}
+
+	// GPXSegment is a segment of a track, points recorded continuously.
+	GPXSegment struct {
+		Points     []GPXWaypoint `xml:"trkpt"`
+		Extensions GPXExtensions `xml:"extensions"`
+	}
+
+	// GPXLink is a link to a web page or a file.
+	GPXLink struct {
+		Href string `xml:"href,attr"`
+		Text string `xml:"text"`
+		Type string `xml:"type"`
+	}
+
+	// GPXPerson is a person or an organization.
+	GPXPerson struct {
+		Name  string    `xml:"name"`
+		Email *GPXEmail `xml:"email"`
+		Link  *GPXLink  `xml:"link"`
+	}
+
+	// GPXEmail is an email address split in an ID and a domain, to make harvesting harder.
+	GPXEmail struct {
+		ID     string `xml:"id,attr"`
+		Domain string `xml:"domain,attr"`
+	}
+
+	// GPXCopyright is the copyright holder of a document and its license.
+	GPXCopyright struct {
+		Author  string `xml:"author,attr"`
+		Year    int    `xml:"year"`
+		License string `xml:"license"`
+	}
+
+	// GPXBounds is the area covered by a document.
+	GPXBounds struct {
+		MinLat float64 `xml:"minlat,attr"`
+		MinLon float64 `xml:"minlon,attr"`
+		MaxLat float64 `xml:"maxlat,attr"`
+		MaxLon float64 `xml:"maxlon,attr"`
+	}
+
+	// GPXExtensions holds the XML of the extensions of an element, as it is in the document.
+	GPXExtensions struct {
+		XML string `xml:",innerxml"`
+	}
+
+	// GPXTime is the time of a GPX element. Times should be in UTC with a Z suffix,
+	// times of other zones and times without zone, taken as UTC, are read too.
+	GPXTime struct {
+		time.Time
+	}
+)
+
+// ErrInvalidGPX is returned when reading XML documents that are not GPX documents.
+var ErrInvalidGPX = errors.New("Invalid GPX")
+
+// UnmarshalText reads a time such as 2021-03-20T05:25:27Z, 2021-03-20T12:25:27.5+07:00 or 2021-03-20T05:25:27.
+func (t *GPXTime) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	// Fractional seconds are read even when the layout has none
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		tm, err = time.ParseInLocation("2006-01-02T15:04:05", s, time.UTC)
+	}
+	if err != nil {
+		return err
+	}
+	t.Time = tm
+	return nil
+}
+
+// ReadGPX reads a GPX 1.1 document. Large documents can be read point by point with a GPXDecoder.
+//  file, _ := os.Open("ride.gpx")
+//  doc, err := geo.ReadGPX(file)
+func ReadGPX(r io.Reader) (*GPX, error) {
+	d := xml.NewDecoder(r)
+	start, err := gpxRoot(d)
+	if err != nil {
+		return nil, err
+	}
+	g := &GPX{}
+	if err := d.DecodeElement(g, &start); err != nil {
+		return nil, err
+	}
+	g.Namespaces = gpxNamespaces(start)
+	return g, nil
+}
+
+// GPXDecoder reads the points of a GPX document one by one: waypoints, points of routes and points of tracks.
+// It is useful for large tracks that we do not want to load in memory at once.
+type GPXDecoder struct {
+	d       *xml.Decoder
+	doc     GPX
+	started bool
+	depth   int
+	route   *GPXRoute
+	track   *GPXTrack
+	segment int
+}
+
+// GPXPoint is a point read by a GPXDecoder.
+type GPXPoint struct {
+	GPXWaypoint
+	// Route is the route of route points, and Track the track of track points, without their points.
+	// Points of the same route or track share the same pointer.
+	Route *GPXRoute
+	Track *GPXTrack
+	// Segment is the index of the segment of track points in their track.
+	Segment int
+}
+
+// NewGPXDecoder returns a decoder reading the points of a GPX document from r.
+//  d := geo.NewGPXDecoder(file)
+//  for p, err := d.Next(); err == nil; p, err = d.Next() {
+//      fmt.Println(p.Time, p.Point())
+//  }
+func NewGPXDecoder(r io.Reader) *GPXDecoder {
+	return &GPXDecoder{d: xml.NewDecoder(r)}
+}
+
+// Document returns the document read so far without its points: version, creator, namespaces,
+// metadata and extensions. Metadata is read with the first point, extensions at the end of the document.
+func (d *GPXDecoder) Document() *GPX {
+	return &d.doc
+}
+
+// Next returns the next point of the document.
+// It returns io.EOF when there are no more points, and ErrInvalidGPX or XML errors for malformed documents.
+func (d *GPXDecoder) Next() (p GPXPoint, err error) {
+	if !d.started {
+		start, err := gpxRoot(d.d)
+		if err != nil {
+			return p, err
+		}
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "version":
+				d.doc.Version = attr.Value
+			case "creator":
+				d.doc.Creator = attr.Value
+			}
+		}
+		d.doc.Namespaces = gpxNamespaces(start)
+		d.started, d.depth = true, 1
+	}
+
+	for {
+		t, err := d.d.Token()
+		if err == io.EOF {
+			if d.depth > 0 {
+				return p, io.ErrUnexpectedEOF
+			}
+			return p, io.EOF
+		} else if err != nil {
+			return p, err
+		}
+
+		switch t := t.(type) {
+		case xml.EndElement:
+			d.depth--
+			switch {
+			case d.depth == 1:
+				d.route, d.track = nil, nil
+			case d.depth == 0:
+				return p, io.EOF
+			}
+		case xml.StartElement:
+			name := t.Name.Local
+			var v interface{}
+			switch {
+			case d.depth == 1 && name == "wpt":
+				err = d.d.DecodeElement(&p.GPXWaypoint, &t)
+				return p, err
+			case d.depth == 1 && name == "rte":
+				d.route, d.depth = &GPXRoute{}, 2
+				continue
+			case d.depth == 1 && name == "trk":
+				d.track, d.segment, d.depth = &GPXTrack{}, -1, 2
+				continue
+			case d.depth == 1 && name == "metadata":
+				v = &d.doc.Metadata
+			case d.depth == 1 && name == "extensions":
+				v = &d.doc.Extensions
+			case d.depth == 2 && d.route != nil && name == "rtept":
+				err = d.d.DecodeElement(&p.GPXWaypoint, &t)
+				p.Route = d.route
+				return p, err
+			case d.depth == 2 && d.route != nil:
+				v = d.route.fields()[name]
+			case d.depth == 2 && d.track != nil && name == "trkseg":
+				d.segment++
+				d.depth = 3
+				continue
+			case d.depth == 2 && d.track != nil:
+				v = d.track.fields()[name]
+			case d.depth == 3 && name == "trkpt":
+				err = d.d.DecodeElement(&p.GPXWaypoint, &t)
+				p.Track, p.Segment = d.track, d.segment
+				return p, err
+			}
+			// Elements are read or skipped up to their end
+			if v != nil {
+				err = d.d.DecodeElement(v, &t)
+			} else {
+				err = d.d.Skip()
+			}
+			if err != nil {
+				return p, err
+			}
+		}
+	}
+}
+
+// fields returns pointers to the fields of the route read from elements of the same name.
+func (r *GPXRoute) fields() map[string]interface{} {
+	return map[string]interface{}{"name": &r.Name, "cmt": &r.Cmt, "desc": &r.Desc, "src": &r.Src,
+		"link": &r.Links, "number": &r.Number, "type": &r.Type, "extensions": &r.Extensions}
+}
+
+// fields returns pointers to the fields of the track read from elements of the same name.
+func (t *GPXTrack) fields() map[string]interface{} {
+	return map[string]interface{}{"name": &t.Name, "cmt": &t.Cmt, "desc": &t.Desc, "src": &t.Src,
+		"link": &t.Links, "number": &t.Number, "type": &t.Type, "extensions": &t.Extensions}
+}
+
+// gpxRoot returns the root gpx element of a document.
+func gpxRoot(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return xml.StartElement{}, ErrInvalidGPX
+		} else if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			if start.Name.Local != "gpx" {
+				return start, ErrInvalidGPX
+			}
+			return start, nil
+		}
+	}
+}
+
+// gpxNamespaces returns the prefixes and URIs of namespaces declared by the root element, other than GPX and XML schema.
+func gpxNamespaces(root xml.StartElement) map[string]string {
+	namespaces := map[string]string{}
+	for _, attr := range root.Attr {
+		if attr.Name.Space == "xmlns" && attr.Value != GPXNamespace && attr.Name.Local != "xsi" {
+			namespaces[attr.Name.Local] = attr.Value
+		}
+	}
+	return namespaces
+}
+
+// WriteGPX writes a GPX 1.1 document. Nil elevations and zero times are left out.
+//  err := geo.WriteGPX(file, &geo.GPX{Routes: []geo.GPXRoute{{Name: "Delivery", Points: geo.GPXPoints(route)}}})
+func WriteGPX(w io.Writer, g *GPX) error {
+	e := &xmlWriter{w: bufio.NewWriter(w)}
+	creator := g.Creator
+	if creator == "" {
+		creator = gpxCreator
+	}
+	e.raw(xml.Header)
+	attrs := []string{"version", "1.1", "creator", creator, "xmlns", GPXNamespace}
+	prefixes := make([]string, 0, len(g.Namespaces))
+	for prefix := range g.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		attrs = append(attrs, "xmlns:"+prefix, g.Namespaces[prefix])
+	}
+	e.start("gpx", attrs...)
+
+	if m := g.Metadata; !m.empty() {
+		e.start("metadata")
+		e.text("name", m.Name)
+		e.text("desc", m.Desc)
+		if a := m.Author; a.Name != "" || a.Email != nil || a.Link != nil {
+			e.start("author")
+			e.text("name", a.Name)
+			if a.Email != nil {
+				e.start("email", "id", a.Email.ID, "domain", a.Email.Domain)
+				e.end("email")
+			}
+			if a.Link != nil {
+				e.link(*a.Link)
+			}
+			e.end("author")
+		}
+		if c := m.Copyright; c.Author != "" {
+			e.start("copyright", "author", c.Author)
+			if c.Year != 0 {
+				e.text("year", strconv.Itoa(c.Year))
+			}
+			e.text("license", c.License)
+			e.end("copyright")
+		}
+		for _, l := range m.Links {
+			e.link(l)
+		}
+		e.time(m.Time.Time)
+		e.text("keywords", m.Keywords)
+		if b := m.Bounds; b != nil {
+			e.start("bounds", "minlat", formatFloat(b.MinLat), "minlon", formatFloat(b.MinLon), "maxlat", formatFloat(b.MaxLat), "maxlon", formatFloat(b.MaxLon))
+			e.end("bounds")
+		}
+		e.extensions(m.Extensions)
+		e.end("metadata")
+	}
+	for _, p := range g.Waypoints {
+		e.waypoint("wpt", p)
+	}
+	for _, r := range g.Routes {
+		e.start("rte")
+		e.description(r.Name, r.Cmt, r.Desc, r.Src, r.Links, r.Number, r.Type)
+		e.extensions(r.Extensions)
+		for _, p := range r.Points {
+			e.waypoint("rtept", p)
+		}
+		e.end("rte")
+	}
+	for _, t := range g.Tracks {
+		e.start("trk")
+		e.description(t.Name, t.Cmt, t.Desc, t.Src, t.Links, t.Number, t.Type)
+		e.extensions(t.Extensions)
+		for _, s := range t.Segments {
+			e.start("trkseg")
+			for _, p := range s.Points {
+				e.waypoint("trkpt", p)
+			}
+			e.extensions(s.Extensions)
+			e.end("trkseg")
+		}
+		e.end("trk")
+	}
+	e.extensions(g.Extensions)
+	e.end("gpx")
+	e.raw("\n")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// empty returns true if the metadata has no field set.
+func (m GPXMetadata) empty() bool {
+	return m.Name == "" && m.Desc == "" && m.Author.Name == "" && m.Author.Email == nil && m.Author.Link == nil &&
+		m.Copyright.Author == "" && len(m.Links) == 0 && m.Time.IsZero() && m.Keywords == "" && m.Bounds == nil && m.Extensions.XML == ""
+}
+
+// xmlWriter writes indented XML elements, keeping the first error.
+// GPX elements are written in the order of the GPX schema, KML elements in the order of the KML schema.
+type xmlWriter struct {
+	w     *bufio.Writer
+	depth int
+	// open is true when the last start tag is not closed yet, so empty elements are written as <name/>
+	open bool
+	err  error
+}
+
+// raw writes s as it is.
+func (e *xmlWriter) raw(s string) {
+	if e.open {
+		e.open = false
+		e.raw(">")
+	}
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s)
+	}
+}
+
+// escaped writes s with XML special characters escaped.
+func (e *xmlWriter) escaped(s string) {
+	if e.err == nil {
+		e.err = xml.EscapeText(e.w, []byte(s))
+	}
+}
+
+// start writes an indented start tag with attributes given as names and values.
+func (e *xmlWriter) start(name string, attrs ...string) {
+	e.indent()
+	e.raw("<" + name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		e.raw(" " + attrs[i] + `="`)
+		e.escaped(attrs[i+1])
+		e.raw(`"`)
+	}
+	e.open = true
+	e.depth++
+}
+
+// end writes an indented end tag, or closes the start tag of an empty element.
+func (e *xmlWriter) end(name string) {
+	e.depth--
+	if e.open {
+		e.open = false
+		e.raw("/>")
+		return
+	}
+	e.newLine()
+	e.raw("</" + name + ">")
+}
+
+// indent starts a new line for a child element, the root element stays on the line of the XML header.
+func (e *xmlWriter) indent() {
+	if e.depth > 0 {
+		e.newLine()
+	}
+}
+
+// newLine starts a new line indented by the depth of the element.
+func (e *xmlWriter) newLine() {
+	e.raw("\n")
+	for i := 0; i < e.depth; i++ {
+		e.raw("  ")
+	}
+}
+
+// text writes an element holding text, unless the text is empty.
+func (e *xmlWriter) text(name, value string) {
+	if value == "" {
+		return
+	}
+	e.indent()
+	e.raw("<" + name + ">")
+	e.escaped(value)
+	e.raw("</" + name + ">")
+}
+
+// number writes an element holding a number, unless it is 0.
+func (e *xmlWriter) number(name string, v float64) {
+	if v != 0 {
+		e.text(name, formatFloat(v))
+	}
+}
+
+// time writes a time element in UTC, unless the time is zero.
+func (e *xmlWriter) time(t time.Time) {
+	if !t.IsZero() {
+		e.text("time", t.UTC().Format(time.RFC3339Nano))
+	}
+}
+
+// link writes a link element.
+func (e *xmlWriter) link(l GPXLink) {
+	e.start("link", "href", l.Href)
+	e.text("text", l.Text)
+	e.text("type", l.Type)
+	e.end("link")
+}
+
+// extensions writes the extensions element, unless there are no extensions.
+func (e *xmlWriter) extensions(x GPXExtensions) {
+	if x.XML != "" {
+		e.indent()
+		e.raw("<extensions>" + x.XML + "</extensions>")
+	}
+}
+
+// description writes the elements shared by routes and tracks.
+func (e *xmlWriter) description(name, cmt, desc, src string, links []GPXLink, number int, typ string) {
+	e.text("name", name)
+	e.text("cmt", cmt)
+	e.text("desc", desc)
+	e.text("src", src)
+	for _, l := range links {
+		e.link(l)
+	}
+	if number != 0 {
+		e.text("number", strconv.Itoa(number))
+	}
+	e.text("type", typ)
+}
+
+// waypoint writes a wpt, rtept or trkpt element.
+func (e *xmlWriter) waypoint(name string, p GPXWaypoint) {
+	e.start(name, "lat", formatFloat(p.Lat), "lon", formatFloat(p.Lon))
+	if p.Ele != nil {
+		e.text("ele", formatFloat(*p.Ele))
+	}
+	e.time(p.Time.Time)
+	e.number("magvar", p.MagVar)
+	e.number("geoidheight", p.GeoidHeight)
+	e.text("name", p.Name)
+	e.text("cmt", p.Cmt)
+	e.text("desc", p.Desc)
+	e.text("src", p.Src)
+	for _, l := range p.Links {
+		e.link(l)
+	}
+	e.text("sym", p.Sym)
+	e.text("type", p.Type)
+	e.text("fix", p.Fix)
+	if p.Sat != 0 {
+		e.text("sat", strconv.Itoa(p.Sat))
+	}
+	e.number("hdop", p.HDOP)
+	e.number("vdop", p.VDOP)
+	e.number("pdop", p.PDOP)
+	e.number("ageofdgpsdata", p.AgeOfDGPSData)
+	if p.DGPSID != 0 {
+		e.text("dgpsid", strconv.Itoa(p.DGPSID))
+	}
+	e.extensions(p.Extensions)
+	e.end(name)
+}
+
+// Point returns the location of the waypoint.
+func (p GPXWaypoint) Point() Point {
+	return Point{Lat: p.Lat, Lon: p.Lon}
+}
+
+// GPXPoints returns waypoints at the points, to build routes and tracks.
+func GPXPoints(points []Point) []GPXWaypoint {
+	waypoints := make([]GPXWaypoint, len(points))
+	for i, p := range points {
+		waypoints[i] = GPXWaypoint{Lat: p.Lat, Lon: p.Lon}
+	}
+	return waypoints
+}
+
+// gpxLine returns the line joining waypoints.
+func gpxLine(waypoints []GPXWaypoint) LineString {
+	line := make(LineString, len(waypoints))
+	for i, p := range waypoints {
+		line[i] = p.Point()
+	}
+	return line
+}
+
+// LineString returns the line joining the points of the route.
+func (r GPXRoute) LineString() LineString {
+	return gpxLine(r.Points)
+}
+
+// LineString returns the line joining the points of the segment.
+func (s GPXSegment) LineString() LineString {
+	return gpxLine(s.Points)
+}
+
+// MultiLineString returns the lines of the segments of the track.
+func (t GPXTrack) MultiLineString() MultiLineString {
+	lines := make(MultiLineString, len(t.Segments))
+	for i, s := range t.Segments {
+		lines[i] = s.LineString()
+	}
+	return lines
+}
+
+// Geometry returns the waypoints, routes and tracks of the document as a geometry collection
+// of points, line strings and multi line strings, to convert GPX documents to GeoJSON for instance.
+func (g *GPX) Geometry() GeometryCollection {
+	c := make(GeometryCollection, 0, len(g.Waypoints)+len(g.Routes)+len(g.Tracks))
+	for _, p := range g.Waypoints {
+		c = append(c, p.Point())
+	}
+	for _, r := range g.Routes {
+		c = append(c, r.LineString())
+	}
+	for _, t := range g.Tracks {
+		c = append(c, t.MultiLineString())
+	}
+	return c
+}
+
+// BoundingBox returns the box around the points of the document.
+// The box is empty, with MinLat greater than MaxLat, when the document has no point.
+func (g *GPX) BoundingBox() BoundingBox {
+	b := BoundingBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	add := func(points []GPXWaypoint) {
+		for _, p := range points {
+			b.MinLat, b.MaxLat = math.Min(b.MinLat, p.Lat), math.Max(b.MaxLat, p.Lat)
+			b.MinLon, b.MaxLon = math.Min(b.MinLon, p.Lon), math.Max(b.MaxLon, p.Lon)
+		}
+	}
+	add(g.Waypoints)
+	for _, r := range g.Routes {
+		add(r.Points)
+	}
+	for _, t := range g.Tracks {
+		for _, s := range t.Segments {
+			add(s.Points)
+		}
+	}
+	return b
+}
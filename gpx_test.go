@@ -0,0 +1,261 @@
+package geo
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="Garmin Connect" xmlns="http://www.topografix.com/GPX/1/1"
+  xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+  xsi:schemaLocation="http://www.topografix.com/GPX/1/1 http://www.topografix.com/GPX/1/1/gpx.xsd"
+  xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <metadata>
+    <name>Morning ride</name>
+    <author><name>Frontware</name><email id="info" domain="frontware.com"/></author>
+    <link href="https://www.frontware.com"><text>Frontware</text></link>
+    <time>2021-03-20T23:10:05Z</time>
+    <bounds minlat="13.7" minlon="100.5" maxlat="13.8" maxlon="100.6"/>
+  </metadata>
+  <wpt lat="13.7563" lon="100.5018">
+    <ele>4</ele>
+    <name>Bangkok &amp; Thonburi</name>
+    <sym>Flag, Blue</sym>
+  </wpt>
+  <rte>
+    <name>Delivery</name>
+    <number>2</number>
+    <rtept lat="13.7665217" lon="100.6068431"><name>Office</name></rtept>
+    <rtept lat="13.7199345" lon="100.5197898"/>
+  </rte>
+  <trk>
+    <name>Ride</name>
+    <type>cycling</type>
+    <trkseg>
+      <trkpt lat="13.7665217" lon="100.6068431">
+        <ele>2.5</ele>
+        <time>2021-03-20T23:10:05Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>121</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="13.7666" lon="100.6069"><ele>2.7</ele><time>2021-03-20T23:10:10.5Z</time><hdop>1.2</hdop><sat>9</sat></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="13.7199345" lon="100.5197898"><time>2021-03-21T06:10:15+07:00</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+func TestReadGPX(t *testing.T) {
+	g, err := ReadGPX(strings.NewReader(testGPX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Version != "1.1" || g.Creator != "Garmin Connect" || g.Namespaces["gpxtpx"] != "http://www.garmin.com/xmlschemas/TrackPointExtension/v1" || len(g.Namespaces) != 1 {
+		t.Errorf("ReadGPX() = %v, %v, %v", g.Version, g.Creator, g.Namespaces)
+	}
+	m := g.Metadata
+	if m.Name != "Morning ride" || m.Author.Name != "Frontware" || m.Author.Email == nil || m.Author.Email.Domain != "frontware.com" ||
+		len(m.Links) != 1 || m.Links[0].Text != "Frontware" || !m.Time.Equal(time.Date(2021, 3, 20, 23, 10, 5, 0, time.UTC)) ||
+		m.Bounds == nil || m.Bounds.MaxLon != 100.6 {
+		t.Errorf("ReadGPX() metadata = %+v", m)
+	}
+	if len(g.Waypoints) != 1 || g.Waypoints[0].Name != "Bangkok & Thonburi" || g.Waypoints[0].Ele == nil || *g.Waypoints[0].Ele != 4 || g.Waypoints[0].Point() != (Point{13.7563, 100.5018}) {
+		t.Errorf("ReadGPX() waypoints = %+v", g.Waypoints)
+	}
+	if len(g.Routes) != 1 || g.Routes[0].Name != "Delivery" || g.Routes[0].Number != 2 || len(g.Routes[0].Points) != 2 || g.Routes[0].Points[0].Name != "Office" {
+		t.Errorf("ReadGPX() routes = %+v", g.Routes)
+	}
+	if len(g.Tracks) != 1 || len(g.Tracks[0].Segments) != 2 || g.Tracks[0].Type != "cycling" {
+		t.Fatalf("ReadGPX() tracks = %+v", g.Tracks)
+	}
+	p := g.Tracks[0].Segments[0].Points[1]
+	if p.Ele == nil || *p.Ele != 2.7 || p.HDOP != 1.2 || p.Sat != 9 || !p.Time.Equal(time.Date(2021, 3, 20, 23, 10, 10, 5e8, time.UTC)) {
+		t.Errorf("ReadGPX() track point = %+v", p)
+	}
+	if x := g.Tracks[0].Segments[0].Points[0].Extensions.XML; x != "<gpxtpx:TrackPointExtension><gpxtpx:hr>121</gpxtpx:hr></gpxtpx:TrackPointExtension>" {
+		t.Errorf("ReadGPX() extensions = %v", x)
+	}
+	if tm := g.Tracks[0].Segments[1].Points[0].Time; !tm.Equal(time.Date(2021, 3, 20, 23, 10, 15, 0, time.UTC)) {
+		t.Errorf("ReadGPX() time = %v", tm)
+	}
+	if e := g.Routes[0].Points[1].Ele; e != nil {
+		t.Errorf("ReadGPX() elevation = %v, want nil", *e)
+	}
+
+	// Conversions
+	if line := g.Routes[0].LineString(); !reflect.DeepEqual(line, LineString{{13.7665217, 100.6068431}, {13.7199345, 100.5197898}}) {
+		t.Errorf("LineString() = %v", line)
+	}
+	if lines := g.Tracks[0].MultiLineString(); len(lines) != 2 || len(lines[0]) != 2 || lines[1][0] != (Point{13.7199345, 100.5197898}) {
+		t.Errorf("MultiLineString() = %v", lines)
+	}
+	if c := g.Geometry(); len(c) != 3 || c[0].GeometryType() != TypePoint || c[1].GeometryType() != TypeLineString || c[2].GeometryType() != TypeMultiLineString {
+		t.Errorf("Geometry() = %v", c)
+	}
+	if b := g.BoundingBox(); b != (BoundingBox{13.7199345, 100.5018, 13.7666, 100.6069}) {
+		t.Errorf("BoundingBox() = %v", b)
+	}
+
+	for _, doc := range []string{"", "<kml></kml>", "<gpx><wpt lat=\"x\"/></gpx>", "<gpx><trk>"} {
+		if _, err := ReadGPX(strings.NewReader(doc)); err == nil {
+			t.Errorf("ReadGPX(%q) error = nil", doc)
+		}
+	}
+}
+
+func TestWriteGPX(t *testing.T) {
+	g, err := ReadGPX(strings.NewReader(testGPX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := WriteGPX(&b, g); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, s := range []string{
+		`<gpx version="1.1" creator="Garmin Connect" xmlns="http://www.topografix.com/GPX/1/1" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">`,
+		"<name>Bangkok &amp; Thonburi</name>",
+		`<email id="info" domain="frontware.com"/>`,
+		"<time>2021-03-20T23:10:15Z</time>",
+		"<time>2021-03-20T23:10:10.5Z</time>",
+		`<trkpt lat="13.7199345" lon="100.5197898">`,
+		"<extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>121</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("WriteGPX() output misses %v:\n%v", s, out)
+		}
+	}
+
+	// Times are written in UTC
+	g.Tracks[0].Segments[1].Points[0].Time = GPXTime{g.Tracks[0].Segments[1].Points[0].Time.UTC()}
+	read, err := ReadGPX(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, g) {
+		t.Errorf("ReadGPX(WriteGPX()) = %+v, want %+v", read, g)
+	}
+
+	b.Reset()
+	route := []Point{{13.7665217, 100.6068431}, {13.7199345, 100.5197898}}
+	if err := WriteGPX(&b, &GPX{Routes: []GPXRoute{{Name: "Delivery", Points: GPXPoints(route)}}}); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="github.com/frontware/geo" xmlns="http://www.topografix.com/GPX/1/1">
+  <rte>
+    <name>Delivery</name>
+    <rtept lat="13.7665217" lon="100.6068431"/>
+    <rtept lat="13.7199345" lon="100.5197898"/>
+  </rte>
+</gpx>
+`
+	if b.String() != want {
+		t.Errorf("WriteGPX() = %v, want %v", b.String(), want)
+	}
+
+	// Elevations of 0 are written, at sea level
+	b.Reset()
+	sea := 0.0
+	if err := WriteGPX(&b, &GPX{Waypoints: []GPXWaypoint{{Lat: 13.5, Lon: 100.6, Ele: &sea}}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "<ele>0</ele>") {
+		t.Errorf("WriteGPX(elevation 0) = %v", b.String())
+	}
+}
+
+func TestGPXTime(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{"2021-03-20T05:25:27Z", time.Date(2021, 3, 20, 5, 25, 27, 0, time.UTC)},
+		{"2021-03-20T05:25:27.25Z", time.Date(2021, 3, 20, 5, 25, 27, 25e7, time.UTC)},
+		{"2021-03-20T12:25:27+07:00", time.Date(2021, 3, 20, 5, 25, 27, 0, time.UTC)},
+		{"2021-03-20T05:25:27", time.Date(2021, 3, 20, 5, 25, 27, 0, time.UTC)},
+		{" 2021-03-20T05:25:27.5 ", time.Date(2021, 3, 20, 5, 25, 27, 5e8, time.UTC)},
+		{"", time.Time{}},
+	}
+	for _, tt := range tests {
+		doc := `<gpx><metadata><time>` + tt.text + `</time></metadata><wpt lat="1" lon="2"><time>` + tt.text + `</time></wpt></gpx>`
+		g, err := ReadGPX(strings.NewReader(doc))
+		if err != nil {
+			t.Errorf("ReadGPX(%q) error = %v", tt.text, err)
+			continue
+		}
+		if !g.Metadata.Time.Equal(tt.want) || !g.Waypoints[0].Time.Equal(tt.want) {
+			t.Errorf("ReadGPX(%q) times = %v, %v, want %v", tt.text, g.Metadata.Time, g.Waypoints[0].Time, tt.want)
+		}
+	}
+	if _, err := ReadGPX(strings.NewReader(`<gpx><wpt lat="1" lon="2"><time>yesterday</time></wpt></gpx>`)); err == nil {
+		t.Error("ReadGPX(invalid time) error = nil")
+	}
+}
+
+func TestGPXDecoder(t *testing.T) {
+	g, _ := ReadGPX(strings.NewReader(testGPX))
+	d := NewGPXDecoder(strings.NewReader(testGPX))
+	var points []GPXPoint
+	for p, err := d.Next(); err != io.EOF; p, err = d.Next() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		points = append(points, p)
+	}
+	if len(points) != 6 {
+		t.Fatalf("Next() returned %v points", len(points))
+	}
+	if p := points[0]; !reflect.DeepEqual(p.GPXWaypoint, g.Waypoints[0]) || p.Route != nil || p.Track != nil {
+		t.Errorf("Next() = %+v", p)
+	}
+	for i, p := range points[1:3] {
+		if !reflect.DeepEqual(p.GPXWaypoint, g.Routes[0].Points[i]) || p.Route == nil || p.Route.Name != "Delivery" || p.Route.Number != 2 || p.Track != nil {
+			t.Errorf("Next() = %+v", p)
+		}
+	}
+	for i, p := range points[3:] {
+		segment := map[bool]int{true: 1}[i == 2]
+		if !reflect.DeepEqual(p.GPXWaypoint, g.Tracks[0].Segments[segment].Points[i%2]) || p.Track == nil || p.Track.Name != "Ride" ||
+			p.Track != points[3].Track || p.Segment != segment || p.Route != nil {
+			t.Errorf("Next() = %+v", p)
+		}
+	}
+	if doc := d.Document(); doc.Creator != "Garmin Connect" || doc.Metadata.Name != "Morning ride" || doc.Namespaces["gpxtpx"] == "" || doc.Tracks != nil {
+		t.Errorf("Document() = %+v", doc)
+	}
+
+	for _, doc := range []string{"<kml></kml>", "<gpx><trk><trkseg><trkpt lat=\"1\" lon=\"2\"/>"} {
+		d := NewGPXDecoder(strings.NewReader(doc))
+		var err error
+		for err == nil {
+			_, err = d.Next()
+		}
+		if err == io.EOF {
+			t.Errorf("Next(%q) error = io.EOF", doc)
+		}
+	}
+}
+
+func BenchmarkGPXDecoder(b *testing.B) {
+	var doc bytes.Buffer
+	segment := GPXSegment{}
+	start := time.Date(2021, 3, 20, 23, 10, 5, 0, time.UTC)
+	ele := 2.0
+	for i := 0; i < 10000; i++ {
+		segment.Points = append(segment.Points, GPXWaypoint{Lat: 13.7 + math.Sin(float64(i))/100, Lon: 100.5 + float64(i)/1e5, Ele: &ele, Time: GPXTime{start.Add(time.Duration(i) * time.Second)}})
+	}
+	WriteGPX(&doc, &GPX{Tracks: []GPXTrack{{Segments: []GPXSegment{segment}}}})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d := NewGPXDecoder(bytes.NewReader(doc.Bytes()))
+		for _, err := d.Next(); err == nil; _, err = d.Next() {
+		}
+	}
+}
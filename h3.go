@@ -0,0 +1,1083 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// H3Cell is the index of a cell of the H3 hexagonal hierarchical grid from Uber.
+// Indexes are the same as the reference implementation, so cells computed here
+// match the ones of the H3 libraries in Python, Java or SQL.
+// Details https://h3geo.org/docs/core-library/h3Indexing
+type H3Cell uint64
+
+// H3 resolutions
+const (
+	H3MinResolution = 0
+	H3MaxResolution = 15
+)
+
+var (
+	// ErrInvalidH3Resolution is returned for a resolution out of 0-15.
+	ErrInvalidH3Resolution = errors.New("Invalid H3 resolution")
+	// ErrInvalidH3Cell is returned when parsing an invalid H3 index.
+	ErrInvalidH3Cell = errors.New("Invalid H3 cell")
+)
+
+// Bit layout of an H3 index
+const (
+	h3ModeOffset     = 59
+	h3BaseCellOffset = 45
+	h3ResOffset      = 52
+	h3ReservedOffset = 56
+	h3DigitBits      = 3
+	h3CellMode       = 1
+	// h3Init is an index with all digits set to 7 (unused)
+	h3Init = H3Cell(35184372088831)
+)
+
+// Digits of an H3 index, each is a direction from the parent cell center
+const (
+	h3Center = iota
+	h3K
+	h3J
+	h3JK
+	h3I
+	h3IK
+	h3IJ
+	h3InvalidDigit
+)
+
+// Overage of face coordinates
+const (
+	h3NoOverage = iota
+	h3FaceEdge
+	h3NewFace
+)
+
+const (
+	h3NumBaseCells       = 122
+	h3InvalidBaseCell    = 127
+	h3MaxFaceCoord       = 2
+	h3Epsilon            = 1e-16
+	h3Sqrt7              = 2.6457513110645905905016157536392604257102
+	h3Sqrt3Half          = 0.8660254037844386467637231707529361834714
+	h3Ap7RotRads         = 0.333473172251832115336090755351601070065900389
+	h3Res0GnomonicUnit   = 0.38196601125010500003
+	h3AlmostEqualEpsilon = 1.1920928955078125e-07 // FLT_EPSILON of C
+)
+
+// h3Directions are the directions to the neighbors of a cell, in ring order.
+var h3Directions = [6]int{h3J, h3JK, h3K, h3IK, h3I, h3IJ}
+
+// H3FromPoint returns the cell holding the point at the resolution, from 0 (cells of about 1100 km)
+// to 15 (cells of about 0.5 m).
+//  cell, err := geo.H3FromPoint(geo.Point{Lat: 13.7563, Lon: 100.5018}, 9)
+//  fmt.Println(cell) // 8964a4b138fffff
+func H3FromPoint(p Point, res int) (H3Cell, error) {
+	if res < H3MinResolution || res > H3MaxResolution {
+		return 0, ErrInvalidH3Resolution
+	}
+	if math.IsNaN(p.Lat) || math.IsInf(p.Lat, 0) {
+		return 0, ErrInvalidLatitude
+	}
+	if math.IsNaN(p.Lon) || math.IsInf(p.Lon, 0) {
+		return 0, ErrInvalidLongitude
+	}
+	return h3GeoToFaceIJK(p.Lat*(math.Pi/180), p.Lon*(math.Pi/180), res).toCell(res), nil
+}
+
+// H3FromString parses the hexadecimal representation of a cell.
+//  cell, err := geo.H3FromString("8964a4b138fffff")
+func H3FromString(s string) (H3Cell, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || !H3Cell(v).Valid() {
+		return 0, ErrInvalidH3Cell
+	}
+	return H3Cell(v), nil
+}
+
+// String returns the hexadecimal representation of the cell, as used by H3 libraries.
+func (c H3Cell) String() string {
+	return strconv.FormatUint(uint64(c), 16)
+}
+
+// Resolution returns the resolution of the cell.
+func (c H3Cell) Resolution() int {
+	return int(c>>h3ResOffset) & 15
+}
+
+// BaseCell returns the number of the resolution 0 cell holding the cell, from 0 to 121.
+func (c H3Cell) BaseCell() int {
+	return int(c>>h3BaseCellOffset) & 127
+}
+
+// Valid returns true if the index is a valid cell.
+func (c H3Cell) Valid() bool {
+	if c>>63 != 0 || int(c>>h3ModeOffset)&15 != h3CellMode || int(c>>h3ReservedOffset)&7 != 0 {
+		return false
+	}
+	baseCell := c.BaseCell()
+	if baseCell >= h3NumBaseCells {
+		return false
+	}
+	res := c.Resolution()
+	leading := false
+	for r := 1; r <= res; r++ {
+		d := c.digit(r)
+		if !leading && d != h3Center {
+			leading = true
+			// Pentagons have no child in the K direction
+			if h3BaseCells[baseCell].pentagon && d == h3K {
+				return false
+			}
+		}
+		if d == h3InvalidDigit {
+			return false
+		}
+	}
+	for r := res + 1; r <= H3MaxResolution; r++ {
+		if c.digit(r) != h3InvalidDigit {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPentagon returns true if the cell is one of the 12 pentagons of each resolution.
+func (c H3Cell) IsPentagon() bool {
+	return h3BaseCells[c.BaseCell()].pentagon && c.leadingDigit() == h3Center
+}
+
+// Point returns the center of the cell.
+func (c H3Cell) Point() Point {
+	lat, lng := c.faceIJK().toGeo(c.Resolution())
+	return Point{Lat: lat * (180 / math.Pi), Lon: lng * (180 / math.Pi)}
+}
+
+// Boundary returns the vertices of the cell, counterclockwise. Hexagons have 6 vertices and pentagons 5,
+// plus a vertex where an edge crosses an edge of the icosahedron.
+func (c H3Cell) Boundary() Ring {
+	res := c.Resolution()
+	if c.IsPentagon() {
+		return c.faceIJK().pentagonBoundary(res)
+	}
+	return c.faceIJK().hexagonBoundary(res)
+}
+
+// Parent returns the cell at the coarser resolution holding the cell.
+// It returns 0 if res is not between 0 and the resolution of the cell.
+//  district := cell.Parent(6)
+func (c H3Cell) Parent(res int) H3Cell {
+	r := c.Resolution()
+	if res < H3MinResolution || res > r {
+		return 0
+	}
+	p := c.setResolution(res)
+	for i := res + 1; i <= r; i++ {
+		p = p.setDigit(i, h3InvalidDigit)
+	}
+	return p
+}
+
+// Children returns the cells at the finer resolution held by the cell: 7 per resolution for hexagons.
+// It returns nil if res is not between the resolution of the cell and 15.
+func (c H3Cell) Children(res int) []H3Cell {
+	r := c.Resolution()
+	if res < r || res > H3MaxResolution {
+		return nil
+	}
+	cells := []H3Cell{c}
+	for ; r < res; r++ {
+		next := make([]H3Cell, 0, 7*len(cells))
+		for _, cell := range cells {
+			pentagon := cell.IsPentagon()
+			child := cell.setResolution(r + 1)
+			for d := h3Center; d < h3InvalidDigit; d++ {
+				// The K child of a pentagon is deleted
+				if pentagon && d == h3K {
+					continue
+				}
+				next = append(next, child.setDigit(r+1, d))
+			}
+		}
+		cells = next
+	}
+	return cells
+}
+
+// KRing returns the cells at a grid distance up to k from the cell, the cell first then ring by ring.
+//  neighbors := cell.KRing(1)
+func (c H3Cell) KRing(k int) []H3Cell {
+	cells := []H3Cell{c}
+	seen := map[H3Cell]bool{c: true}
+	ring := cells
+	for i := 0; i < k; i++ {
+		var next []H3Cell
+		for _, cell := range ring {
+			for _, dir := range h3Directions {
+				rotations := 0
+				n, ok := cell.neighbor(dir, &rotations)
+				if ok && !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		cells = append(cells, next...)
+		ring = next
+	}
+	return cells
+}
+
+// H3PolygonFill returns the cells at the resolution whose center is inside the polygon.
+// Holes are excluded. Polygons around a pole are not supported.
+//  cells, err := geo.H3PolygonFill(deliveryArea, 9)
+func H3PolygonFill(p Polygon, res int) ([]H3Cell, error) {
+	if res < H3MinResolution || res > H3MaxResolution {
+		return nil, ErrInvalidH3Resolution
+	}
+	// A cell inside the polygon is either next to a cell crossed by the boundary, or only
+	// next to cells inside the polygon. So we start from the cells along the boundary
+	// and flood fill to their neighbors.
+	spacing := h3EdgeLengths[res] / 3
+	seeds := map[H3Cell]bool{}
+	for _, r := range p {
+		for i := range r {
+			a, b := r[i], r[(i+1)%len(r)]
+			dLat, dLon := b.Lat-a.Lat, angleDiff(a.Lon, b.Lon)
+			steps := int(math.Ceil((math.Abs(dLat)+math.Abs(dLon))*EarthRadius*math.Pi/180/spacing)) + 1
+			for s := 0; s < steps; s++ {
+				f := float64(s) / float64(steps)
+				cell, err := H3FromPoint(Point{Lat: a.Lat + f*dLat, Lon: normalizeLongitude(a.Lon + f*dLon)}, res)
+				if err != nil {
+					return nil, err
+				}
+				seeds[cell] = true
+			}
+		}
+	}
+
+	var cells []H3Cell
+	seen := map[H3Cell]bool{}
+	var queue []H3Cell
+	for seed := range seeds {
+		for _, cell := range seed.KRing(2) {
+			if !seen[cell] {
+				seen[cell] = true
+				queue = append(queue, cell)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		cell := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if !p.Contains(cell.Point()) {
+			continue
+		}
+		cells = append(cells, cell)
+		for _, n := range cell.KRing(1)[1:] {
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return cells, nil
+}
+
+// digit returns the digit of the index at the resolution.
+func (c H3Cell) digit(res int) int {
+	return int(c>>((H3MaxResolution-res)*h3DigitBits)) & 7
+}
+
+// setDigit returns the index with the digit at the resolution changed.
+func (c H3Cell) setDigit(res, d int) H3Cell {
+	shift := uint((H3MaxResolution - res) * h3DigitBits)
+	return c&^(7<<shift) | H3Cell(d)<<shift
+}
+
+// setResolution returns the index with the resolution changed.
+func (c H3Cell) setResolution(res int) H3Cell {
+	return c&^(15<<h3ResOffset) | H3Cell(res)<<h3ResOffset
+}
+
+// setBaseCell returns the index with the base cell changed.
+func (c H3Cell) setBaseCell(baseCell int) H3Cell {
+	return c&^(127<<h3BaseCellOffset) | H3Cell(baseCell)<<h3BaseCellOffset
+}
+
+// leadingDigit returns the first digit which is not center, or center.
+func (c H3Cell) leadingDigit() int {
+	for r := 1; r <= c.Resolution(); r++ {
+		if d := c.digit(r); d != h3Center {
+			return d
+		}
+	}
+	return h3Center
+}
+
+// rotate60ccw rotates all digits of the index 60° counterclockwise.
+func (c H3Cell) rotate60ccw() H3Cell {
+	for r := 1; r <= c.Resolution(); r++ {
+		c = c.setDigit(r, h3RotateDigit60ccw(c.digit(r)))
+	}
+	return c
+}
+
+// rotate60cw rotates all digits of the index 60° clockwise.
+func (c H3Cell) rotate60cw() H3Cell {
+	for r := 1; r <= c.Resolution(); r++ {
+		c = c.setDigit(r, h3RotateDigit60cw(c.digit(r)))
+	}
+	return c
+}
+
+// rotatePentagon60ccw rotates the digits of a pentagon index 60° counterclockwise,
+// skipping the deleted K sub-sequence.
+func (c H3Cell) rotatePentagon60ccw() H3Cell {
+	leading := false
+	for r := 1; r <= c.Resolution(); r++ {
+		c = c.setDigit(r, h3RotateDigit60ccw(c.digit(r)))
+		if !leading && c.digit(r) != h3Center {
+			leading = true
+			if c.leadingDigit() == h3K {
+				c = c.rotate60ccw()
+			}
+		}
+	}
+	return c
+}
+
+// faceIJK returns the face and coordinates of the cell center.
+func (c H3Cell) faceIJK() (f h3FaceIJK) {
+	baseCell := c.BaseCell()
+	pentagon := h3BaseCells[baseCell].pentagon
+	// Adjust for the pentagon missing sequence: all of sub-sequence 5 needs
+	// to be adjusted, and some of sub-sequence 4 below
+	if pentagon && c.leadingDigit() == h3IK {
+		c = c.rotate60cw()
+	}
+
+	f = h3BaseCells[baseCell].home
+	res := c.Resolution()
+	possibleOverage := pentagon || (res != 0 && f.coord != h3IJK{})
+	for r := 1; r <= res; r++ {
+		if r%2 == 1 {
+			f.coord.downAp7()
+		} else {
+			f.coord.downAp7r()
+		}
+		f.coord.neighbor(c.digit(r))
+	}
+	if !possibleOverage {
+		return
+	}
+
+	// The cell can be on an adjacent face
+	orig := f.coord
+	adjRes := res
+	if res%2 == 1 {
+		// Class III, we use the next finer class II grid
+		f.coord.downAp7r()
+		adjRes++
+	}
+	pentLeading4 := pentagon && c.leadingDigit() == h3I
+	if f.adjustOverageClassII(adjRes, pentLeading4, false) != h3NoOverage {
+		// Pentagons can have secondary overages
+		if pentagon {
+			for f.adjustOverageClassII(adjRes, false, false) != h3NoOverage {
+			}
+		}
+		if adjRes != res {
+			f.coord.upAp7r()
+		}
+	} else if adjRes != res {
+		f.coord = orig
+	}
+	return
+}
+
+// neighbor returns the neighbor of the cell in the direction. Rotations is the number of
+// 60° ccw rotations to apply to the direction, it is updated when crossing base cells.
+// It returns false when moving to the deleted direction of a pentagon.
+func (c H3Cell) neighbor(dir int, rotations *int) (H3Cell, bool) {
+	current := c
+	*rotations %= 6
+	for i := 0; i < *rotations; i++ {
+		dir = h3RotateDigit60ccw(dir)
+	}
+
+	newRotations := 0
+	oldBaseCell := c.BaseCell()
+	oldLeadingDigit := c.leadingDigit()
+
+	// Adjust the digits and, if needed, the base cell
+	for r := c.Resolution() - 1; ; r-- {
+		if r == -1 {
+			current = current.setBaseCell(h3BaseCellNeighbors[oldBaseCell][dir])
+			newRotations = h3BaseCellNeighborRotations[oldBaseCell][dir]
+			if current.BaseCell() == h3InvalidBaseCell {
+				// Adjust for the deleted K vertex at the base cell level.
+				// This edge actually borders a different neighbor.
+				current = current.setBaseCell(h3BaseCellNeighbors[oldBaseCell][h3IK])
+				newRotations = h3BaseCellNeighborRotations[oldBaseCell][h3IK]
+				current = current.rotate60ccw()
+				*rotations++
+			}
+			break
+		}
+		oldDigit := current.digit(r + 1)
+		var nextDir int
+		if (r+1)%2 == 1 {
+			current = current.setDigit(r+1, h3NewDigitII[oldDigit][dir])
+			nextDir = h3NewAdjustmentII[oldDigit][dir]
+		} else {
+			current = current.setDigit(r+1, h3NewDigitIII[oldDigit][dir])
+			nextDir = h3NewAdjustmentIII[oldDigit][dir]
+		}
+		if nextDir == h3Center {
+			break
+		}
+		dir = nextDir
+	}
+
+	newBaseCell := current.BaseCell()
+	if h3BaseCells[newBaseCell].pentagon {
+		alreadyAdjusted := false
+		// Force rotation out of the missing K sub-sequence
+		if current.leadingDigit() == h3K {
+			if oldBaseCell != newBaseCell {
+				// We moved into the deleted K sub-sequence of a pentagon base cell
+				if h3BaseCells[newBaseCell].isCwOffset(h3BaseCells[oldBaseCell].home.face) {
+					current = current.rotate60cw()
+				} else {
+					current = current.rotate60ccw()
+				}
+				alreadyAdjusted = true
+			} else {
+				// We moved into the deleted K sub-sequence from the same pentagon base cell
+				switch oldLeadingDigit {
+				case h3Center:
+					return 0, false
+				case h3JK:
+					current = current.rotate60ccw()
+					*rotations++
+				case h3IK:
+					current = current.rotate60cw()
+					*rotations += 5
+				default:
+					return 0, false
+				}
+			}
+		}
+		for i := 0; i < newRotations; i++ {
+			current = current.rotatePentagon60ccw()
+		}
+		// Account for the different orientation of the base cells
+		if oldBaseCell != newBaseCell {
+			if newBaseCell == 4 || newBaseCell == 117 {
+				// Polar pentagons have all neighbors oriented towards them
+				if oldBaseCell != 118 && oldBaseCell != 8 && current.leadingDigit() != h3JK {
+					*rotations++
+				}
+			} else if current.leadingDigit() == h3IK && !alreadyAdjusted {
+				*rotations++
+			}
+		}
+	} else {
+		for i := 0; i < newRotations; i++ {
+			current = current.rotate60ccw()
+		}
+	}
+	*rotations = (*rotations + newRotations) % 6
+	return current, true
+}
+
+// isCwOffset returns true if the face is a cw offset face of the pentagon base cell.
+func (b h3BaseCellData) isCwOffset(face int) bool {
+	return b.cwOffsetPent[0] == face || b.cwOffsetPent[1] == face
+}
+
+// toCell returns the index of the cell at the coordinates.
+func (f h3FaceIJK) toCell(res int) H3Cell {
+	h := h3Init.setResolution(res)
+	h = h&^(15<<h3ModeOffset) | h3CellMode<<h3ModeOffset
+	if res == 0 {
+		if f.coord.i > h3MaxFaceCoord || f.coord.j > h3MaxFaceCoord || f.coord.k > h3MaxFaceCoord {
+			return 0
+		}
+		return h.setBaseCell(h3FaceIJKBaseCells[f.face][f.coord.i][f.coord.j][f.coord.k].baseCell)
+	}
+
+	// Build the index from the finest resolution up
+	ijk := &f.coord
+	for r := res - 1; r >= 0; r-- {
+		last := *ijk
+		var center h3IJK
+		if (r+1)%2 == 1 {
+			ijk.upAp7()
+			center = *ijk
+			center.downAp7()
+		} else {
+			ijk.upAp7r()
+			center = *ijk
+			center.downAp7r()
+		}
+		diff := last.sub(center)
+		diff.normalize()
+		h = h.setDigit(r+1, diff.digit())
+	}
+
+	// f is now the base cell in the coordinate system of the face
+	if f.coord.i > h3MaxFaceCoord || f.coord.j > h3MaxFaceCoord || f.coord.k > h3MaxFaceCoord {
+		return 0
+	}
+	rotation := h3FaceIJKBaseCells[f.face][f.coord.i][f.coord.j][f.coord.k]
+	h = h.setBaseCell(rotation.baseCell)
+
+	// Rotate to the orientation of the base cell
+	if h3BaseCells[rotation.baseCell].pentagon {
+		// Force rotation out of the missing K sub-sequence
+		if h.leadingDigit() == h3K {
+			if h3BaseCells[rotation.baseCell].isCwOffset(f.face) {
+				h = h.rotate60cw()
+			} else {
+				h = h.rotate60ccw()
+			}
+		}
+		for i := 0; i < rotation.ccwRot60; i++ {
+			h = h.rotatePentagon60ccw()
+		}
+	} else {
+		for i := 0; i < rotation.ccwRot60; i++ {
+			h = h.rotate60ccw()
+		}
+	}
+	return h
+}
+
+// h3GeoToFaceIJK returns the face and coordinates of the cell holding the point, in radians.
+func h3GeoToFaceIJK(lat, lng float64, res int) h3FaceIJK {
+	face, x, y := h3GeoToHex2d(lat, lng, res)
+	return h3FaceIJK{face: face, coord: h3Hex2dToIJK(x, y)}
+}
+
+// h3GeoToHex2d returns the closest face of the point and its 2D coordinates on the face.
+func h3GeoToHex2d(lat, lng float64, res int) (face int, x, y float64) {
+	v := vector{math.Cos(lng) * math.Cos(lat), math.Sin(lng) * math.Cos(lat), math.Sin(lat)}
+	sqd := 5.0
+	for f, center := range h3FaceCenterVectors {
+		d := v.add(center.scale(-1))
+		if s := d.X*d.X + d.Y*d.Y + d.Z*d.Z; s < sqd {
+			face, sqd = f, s
+		}
+	}
+
+	r := math.Acos(1 - sqd/2)
+	if r < h3Epsilon {
+		return face, 0, 0
+	}
+	// Counterclockwise angle from the class II i axis
+	center := h3FaceCenters[face]
+	theta := h3PositiveAngle(h3FaceAxesAzimuths[face][0] - h3PositiveAngle(h3Azimuth(center[0], center[1], lat, lng)))
+	if res%2 == 1 {
+		theta = h3PositiveAngle(theta - h3Ap7RotRads)
+	}
+	// Gnomonic scaling
+	r = math.Tan(r) / h3Res0GnomonicUnit
+	for i := 0; i < res; i++ {
+		r *= h3Sqrt7
+	}
+	return face, r * math.Cos(theta), r * math.Sin(theta)
+}
+
+// h3Hex2dToGeo returns the point in radians at the 2D coordinates of the face.
+// Substrate grids are 3 times finer, and already adjusted for class III.
+func h3Hex2dToGeo(x, y float64, face, res int, substrate bool) (lat, lng float64) {
+	r := math.Hypot(x, y)
+	if r < h3Epsilon {
+		return h3FaceCenters[face][0], h3FaceCenters[face][1]
+	}
+	theta := math.Atan2(y, x)
+	for i := 0; i < res; i++ {
+		r /= h3Sqrt7
+	}
+	if substrate {
+		r /= 3
+		if res%2 == 1 {
+			r /= h3Sqrt7
+		}
+	}
+	r = math.Atan(r * h3Res0GnomonicUnit)
+	if !substrate && res%2 == 1 {
+		theta = h3PositiveAngle(theta + h3Ap7RotRads)
+	}
+	theta = h3PositiveAngle(h3FaceAxesAzimuths[face][0] - theta)
+	return h3AzimuthDistance(h3FaceCenters[face][0], h3FaceCenters[face][1], theta, r)
+}
+
+// toGeo returns the center of the cell in radians.
+func (f h3FaceIJK) toGeo(res int) (lat, lng float64) {
+	x, y := f.coord.toHex2d()
+	return h3Hex2dToGeo(x, y, f.face, res, false)
+}
+
+// vertices returns the vertices of the cell on a substrate grid, and the class II resolution of the grid.
+func (f h3FaceIJK) vertices(res, n int) ([]h3FaceIJK, int) {
+	// Vertices of an origin centered cell, counterclockwise from the i axis,
+	// in class II and class III resolutions
+	vertsCII := [6]h3IJK{{2, 1, 0}, {1, 2, 0}, {0, 2, 1}, {0, 1, 2}, {1, 0, 2}, {2, 0, 1}}
+	vertsCIII := [6]h3IJK{{5, 4, 0}, {1, 5, 0}, {0, 5, 4}, {0, 1, 5}, {4, 0, 5}, {5, 0, 1}}
+	verts := vertsCII
+	// Move the center to an aperture 33r substrate grid
+	f.coord.downAp3()
+	f.coord.downAp3r()
+	if res%2 == 1 {
+		verts = vertsCIII
+		f.coord.downAp7r()
+		res++
+	}
+	vertices := make([]h3FaceIJK, n)
+	for v := range vertices {
+		vertices[v] = h3FaceIJK{face: f.face, coord: f.coord.add(verts[v])}
+		vertices[v].coord.normalize()
+	}
+	return vertices, res
+}
+
+// hexagonBoundary returns the vertices of a hexagon.
+func (f h3FaceIJK) hexagonBoundary(res int) (boundary Ring) {
+	verts, adjRes := f.vertices(res, 6)
+	lastFace, lastOverage := -1, h3NoOverage
+	// One more iteration for a vertex where the last edge crosses a face edge
+	for vert := 0; vert < 7; vert++ {
+		v := vert % 6
+		fijk := verts[v]
+		overage := fijk.adjustOverageClassII(adjRes, false, true)
+
+		// Edges crossing an icosahedron edge get a vertex at the intersection,
+		// class II cells have their vertices on face edges
+		if res%2 == 1 && vert > 0 && fijk.face != lastFace && lastOverage != h3FaceEdge {
+			x0, y0 := verts[(v+5)%6].coord.toHex2d()
+			x1, y1 := verts[v].coord.toHex2d()
+			face2 := lastFace
+			if lastFace == f.face {
+				face2 = fijk.face
+			}
+			x, y := h3FaceEdgeIntersection(x0, y0, x1, y1, h3AdjacentFaceDirections[f.face][face2], adjRes)
+			// No vertex is needed when the intersection is at a vertex
+			atVertex := (math.Abs(x0-x) < h3AlmostEqualEpsilon && math.Abs(y0-y) < h3AlmostEqualEpsilon) ||
+				(math.Abs(x1-x) < h3AlmostEqualEpsilon && math.Abs(y1-y) < h3AlmostEqualEpsilon)
+			if !atVertex {
+				boundary = append(boundary, h3Vertex(x, y, f.face, adjRes))
+			}
+		}
+		if vert < 6 {
+			x, y := fijk.coord.toHex2d()
+			boundary = append(boundary, h3Vertex(x, y, fijk.face, adjRes))
+		}
+		lastFace, lastOverage = fijk.face, overage
+	}
+	return
+}
+
+// pentagonBoundary returns the vertices of a pentagon.
+func (f h3FaceIJK) pentagonBoundary(res int) (boundary Ring) {
+	verts, adjRes := f.vertices(res, 5)
+	var last h3FaceIJK
+	for vert := 0; vert < 6; vert++ {
+		fijk := verts[vert%5]
+		for fijk.adjustOverageClassII(adjRes, false, true) == h3NewFace {
+		}
+
+		// All class III pentagon edges cross icosahedron edges
+		if res%2 == 1 && vert > 0 {
+			tmp := fijk
+			x0, y0 := last.coord.toHex2d()
+			orient := h3FaceNeighbors[tmp.face][h3AdjacentFaceDirections[tmp.face][last.face]]
+			tmp.face = orient.face
+			for i := 0; i < orient.ccwRot60; i++ {
+				tmp.coord.rotate60ccw()
+			}
+			translate := orient.translate
+			translate.scale(h3UnitScales[adjRes] * 3)
+			tmp.coord = tmp.coord.add(translate)
+			tmp.coord.normalize()
+			x1, y1 := tmp.coord.toHex2d()
+			x, y := h3FaceEdgeIntersection(x0, y0, x1, y1, h3AdjacentFaceDirections[tmp.face][fijk.face], adjRes)
+			boundary = append(boundary, h3Vertex(x, y, tmp.face, adjRes))
+		}
+		if vert < 5 {
+			x, y := fijk.coord.toHex2d()
+			boundary = append(boundary, h3Vertex(x, y, fijk.face, adjRes))
+		}
+		last = fijk
+	}
+	return
+}
+
+// h3FaceEdgeIntersection returns the intersection of a segment with the edge of the face in the quadrant.
+func h3FaceEdgeIntersection(x0, y0, x1, y1 float64, quadrant, res int) (x, y float64) {
+	maxDim := float64(h3MaxDimensions[res])
+	v0 := [2]float64{3 * maxDim, 0}
+	v1 := [2]float64{-1.5 * maxDim, 3 * h3Sqrt3Half * maxDim}
+	v2 := [2]float64{-1.5 * maxDim, -3 * h3Sqrt3Half * maxDim}
+	var e0, e1 [2]float64
+	switch quadrant {
+	case h3QuadrantIJ:
+		e0, e1 = v0, v1
+	case h3QuadrantJK:
+		e0, e1 = v1, v2
+	default:
+		e0, e1 = v2, v0
+	}
+	s1x, s1y := x1-x0, y1-y0
+	s2x, s2y := e1[0]-e0[0], e1[1]-e0[1]
+	t := (s2x*(y0-e0[1]) - s2y*(x0-e0[0])) / (-s2x*s1y + s1x*s2y)
+	return x0 + t*s1x, y0 + t*s1y
+}
+
+// h3Vertex returns the point at the coordinates of a substrate grid.
+func h3Vertex(x, y float64, face, res int) Point {
+	lat, lng := h3Hex2dToGeo(x, y, face, res, true)
+	return Point{Lat: lat * (180 / math.Pi), Lon: lng * (180 / math.Pi)}
+}
+
+// adjustOverageClassII moves coordinates past the edge of the face to the adjacent face.
+// pentLeading4 is true for a pentagon base cell with a leading 4 digit.
+func (f *h3FaceIJK) adjustOverageClassII(res int, pentLeading4, substrate bool) int {
+	overage := h3NoOverage
+	ijk := &f.coord
+	maxDim := h3MaxDimensions[res]
+	if substrate {
+		maxDim *= 3
+	}
+	sum := ijk.i + ijk.j + ijk.k
+	if substrate && sum == maxDim {
+		return h3FaceEdge
+	}
+	if sum > maxDim {
+		overage = h3NewFace
+		var orient h3FaceOrientation
+		switch {
+		case ijk.k > 0 && ijk.j > 0:
+			orient = h3FaceNeighbors[f.face][h3QuadrantJK]
+		case ijk.k > 0:
+			orient = h3FaceNeighbors[f.face][h3QuadrantKI]
+			// Adjust for the pentagon missing sequence
+			if pentLeading4 {
+				// Rotate around the center of the pentagon
+				origin := h3IJK{maxDim, 0, 0}
+				tmp := ijk.sub(origin)
+				tmp.rotate60cw()
+				*ijk = tmp.add(origin)
+			}
+		default:
+			orient = h3FaceNeighbors[f.face][h3QuadrantIJ]
+		}
+		f.face = orient.face
+		for i := 0; i < orient.ccwRot60; i++ {
+			ijk.rotate60ccw()
+		}
+		translate := orient.translate
+		unitScale := h3UnitScales[res]
+		if substrate {
+			unitScale *= 3
+		}
+		translate.scale(unitScale)
+		*ijk = ijk.add(translate)
+		ijk.normalize()
+		// Overage points on pentagon boundaries can end up on edges
+		if substrate && ijk.i+ijk.j+ijk.k == maxDim {
+			overage = h3FaceEdge
+		}
+	}
+	return overage
+}
+
+// h3Hex2dToIJK returns the coordinates of the hexagon holding the 2D point.
+func h3Hex2dToIJK(x, y float64) (h h3IJK) {
+	a1, a2 := math.Abs(x), math.Abs(y)
+	// Reverse conversion
+	x2 := a2 / h3Sqrt3Half
+	x1 := a1 + x2/2
+	m1, m2 := int(x1), int(x2)
+	r1, r2 := x1-float64(m1), x2-float64(m2)
+
+	if r1 < 0.5 {
+		if r1 < 1.0/3 {
+			h.i = m1
+			h.j = m2
+			if r2 >= (1+r1)/2 {
+				h.j++
+			}
+		} else {
+			h.j = m2
+			if r2 >= 1-r1 {
+				h.j++
+			}
+			h.i = m1
+			if 1-r1 <= r2 && r2 < 2*r1 {
+				h.i++
+			}
+		}
+	} else {
+		if r1 < 2.0/3 {
+			h.j = m2
+			if r2 >= 1-r1 {
+				h.j++
+			}
+			h.i = m1 + 1
+			if 2*r1-1 < r2 && r2 < 1-r1 {
+				h.i--
+			}
+		} else {
+			h.i = m1 + 1
+			h.j = m2
+			if r2 >= r1/2 {
+				h.j++
+			}
+		}
+	}
+
+	// Fold across the axes if necessary
+	if x < 0 {
+		if h.j%2 == 0 {
+			diff := h.i - h.j/2
+			h.i -= 2 * diff
+		} else {
+			diff := h.i - (h.j+1)/2
+			h.i -= 2*diff + 1
+		}
+	}
+	if y < 0 {
+		h.i -= (2*h.j + 1) / 2
+		h.j = -h.j
+	}
+	h.normalize()
+	return
+}
+
+// toHex2d returns the 2D coordinates of the hexagon center.
+func (c h3IJK) toHex2d() (x, y float64) {
+	i, j := c.i-c.k, c.j-c.k
+	return float64(i) - 0.5*float64(j), float64(j) * h3Sqrt3Half
+}
+
+func (c h3IJK) add(o h3IJK) h3IJK {
+	return h3IJK{c.i + o.i, c.j + o.j, c.k + o.k}
+}
+
+func (c h3IJK) sub(o h3IJK) h3IJK {
+	return h3IJK{c.i - o.i, c.j - o.j, c.k - o.k}
+}
+
+func (c *h3IJK) scale(f int) {
+	c.i *= f
+	c.j *= f
+	c.k *= f
+}
+
+// normalize makes all coordinates positive with at least one zero.
+func (c *h3IJK) normalize() {
+	if c.i < 0 {
+		c.j -= c.i
+		c.k -= c.i
+		c.i = 0
+	}
+	if c.j < 0 {
+		c.i -= c.j
+		c.k -= c.j
+		c.j = 0
+	}
+	if c.k < 0 {
+		c.i -= c.k
+		c.j -= c.k
+		c.k = 0
+	}
+	min := c.i
+	if c.j < min {
+		min = c.j
+	}
+	if c.k < min {
+		min = c.k
+	}
+	if min > 0 {
+		c.i -= min
+		c.j -= min
+		c.k -= min
+	}
+}
+
+// digit returns the digit of a unit vector, or h3InvalidDigit.
+func (c h3IJK) digit() int {
+	c.normalize()
+	for d, unit := range [7]h3IJK{{0, 0, 0}, {0, 0, 1}, {0, 1, 0}, {0, 1, 1}, {1, 0, 0}, {1, 0, 1}, {1, 1, 0}} {
+		if c == unit {
+			return d
+		}
+	}
+	return h3InvalidDigit
+}
+
+// transform replaces the coordinates by i*iVec + j*jVec + k*kVec.
+func (c *h3IJK) transform(iVec, jVec, kVec h3IJK) {
+	iVec.scale(c.i)
+	jVec.scale(c.j)
+	kVec.scale(c.k)
+	*c = iVec.add(jVec).add(kVec)
+	c.normalize()
+}
+
+// upAp7 moves to the parent coordinates of a class III resolution, counterclockwise aperture 7.
+func (c *h3IJK) upAp7() {
+	i, j := c.i-c.k, c.j-c.k
+	*c = h3IJK{int(math.Round(float64(3*i-j) / 7)), int(math.Round(float64(i+2*j) / 7)), 0}
+	c.normalize()
+}
+
+// upAp7r moves to the parent coordinates of a class II resolution, clockwise aperture 7.
+func (c *h3IJK) upAp7r() {
+	i, j := c.i-c.k, c.j-c.k
+	*c = h3IJK{int(math.Round(float64(2*i+j) / 7)), int(math.Round(float64(3*j-i) / 7)), 0}
+	c.normalize()
+}
+
+// downAp7 moves to the center child coordinates, counterclockwise aperture 7.
+func (c *h3IJK) downAp7() {
+	c.transform(h3IJK{3, 0, 1}, h3IJK{1, 3, 0}, h3IJK{0, 1, 3})
+}
+
+// downAp7r moves to the center child coordinates, clockwise aperture 7.
+func (c *h3IJK) downAp7r() {
+	c.transform(h3IJK{3, 1, 0}, h3IJK{0, 3, 1}, h3IJK{1, 0, 3})
+}
+
+// downAp3 moves to the center child coordinates, counterclockwise aperture 3.
+func (c *h3IJK) downAp3() {
+	c.transform(h3IJK{2, 0, 1}, h3IJK{1, 2, 0}, h3IJK{0, 1, 2})
+}
+
+// downAp3r moves to the center child coordinates, clockwise aperture 3.
+func (c *h3IJK) downAp3r() {
+	c.transform(h3IJK{2, 1, 0}, h3IJK{0, 2, 1}, h3IJK{1, 0, 2})
+}
+
+// rotate60ccw rotates the coordinates 60° counterclockwise.
+func (c *h3IJK) rotate60ccw() {
+	c.transform(h3IJK{1, 1, 0}, h3IJK{0, 1, 1}, h3IJK{1, 0, 1})
+}
+
+// rotate60cw rotates the coordinates 60° clockwise.
+func (c *h3IJK) rotate60cw() {
+	c.transform(h3IJK{1, 0, 1}, h3IJK{1, 1, 0}, h3IJK{0, 1, 1})
+}
+
+// neighbor moves to the neighbor coordinates in the direction.
+func (c *h3IJK) neighbor(d int) {
+	if d > h3Center && d < h3InvalidDigit {
+		*c = c.add(h3IJK{d >> 2 & 1, d >> 1 & 1, d & 1})
+		c.normalize()
+	}
+}
+
+// h3RotateDigit60ccw rotates a direction 60° counterclockwise.
+func h3RotateDigit60ccw(d int) int {
+	switch d {
+	case h3K:
+		return h3IK
+	case h3IK:
+		return h3I
+	case h3I:
+		return h3IJ
+	case h3IJ:
+		return h3J
+	case h3J:
+		return h3JK
+	case h3JK:
+		return h3K
+	}
+	return d
+}
+
+// h3RotateDigit60cw rotates a direction 60° clockwise.
+func h3RotateDigit60cw(d int) int {
+	switch d {
+	case h3K:
+		return h3JK
+	case h3JK:
+		return h3J
+	case h3J:
+		return h3IJ
+	case h3IJ:
+		return h3I
+	case h3I:
+		return h3IK
+	case h3IK:
+		return h3K
+	}
+	return d
+}
+
+// h3PositiveAngle returns the angle in radians in [0, 2π).
+func h3PositiveAngle(rads float64) float64 {
+	tmp := rads
+	if rads < 0 {
+		tmp = rads + 2*math.Pi
+	}
+	if rads >= 2*math.Pi {
+		tmp -= 2 * math.Pi
+	}
+	return tmp
+}
+
+// h3Azimuth returns the azimuth in radians from point 1 to point 2, in radians.
+func h3Azimuth(lat1, lng1, lat2, lng2 float64) float64 {
+	return math.Atan2(math.Cos(lat2)*math.Sin(lng2-lng1),
+		math.Cos(lat1)*math.Sin(lat2)-math.Sin(lat1)*math.Cos(lat2)*math.Cos(lng2-lng1))
+}
+
+// h3AzimuthDistance returns the point at the azimuth and angular distance from the point, in radians.
+func h3AzimuthDistance(lat1, lng1, az, distance float64) (lat2, lng2 float64) {
+	if distance < h3Epsilon {
+		return lat1, lng1
+	}
+	az = h3PositiveAngle(az)
+	if az < h3Epsilon || math.Abs(az-math.Pi) < h3Epsilon {
+		// Due north or south
+		if az < h3Epsilon {
+			lat2 = lat1 + distance
+		} else {
+			lat2 = lat1 - distance
+		}
+		if math.Abs(lat2-math.Pi/2) < h3Epsilon {
+			return math.Pi / 2, 0
+		} else if math.Abs(lat2+math.Pi/2) < h3Epsilon {
+			return -math.Pi / 2, 0
+		}
+		return lat2, h3ConstrainLng(lng1)
+	}
+	sinLat := math.Max(-1, math.Min(1, math.Sin(lat1)*math.Cos(distance)+math.Cos(lat1)*math.Sin(distance)*math.Cos(az)))
+	lat2 = math.Asin(sinLat)
+	if math.Abs(lat2-math.Pi/2) < h3Epsilon {
+		return math.Pi / 2, 0
+	} else if math.Abs(lat2+math.Pi/2) < h3Epsilon {
+		return -math.Pi / 2, 0
+	}
+	sinLng := math.Max(-1, math.Min(1, math.Sin(az)*math.Sin(distance)/math.Cos(lat2)))
+	cosLng := math.Max(-1, math.Min(1, (math.Cos(distance)-math.Sin(lat1)*math.Sin(lat2))/math.Cos(lat1)/math.Cos(lat2)))
+	return lat2, h3ConstrainLng(lng1 + math.Atan2(sinLng, cosLng))
+}
+
+// h3ConstrainLng returns the longitude in radians in [-π, π].
+func h3ConstrainLng(lng float64) float64 {
+	for lng > math.Pi {
+		lng -= 2 * math.Pi
+	}
+	for lng < -math.Pi {
+		lng += 2 * math.Pi
+	}
+	return lng
+}
@@ -0,0 +1,232 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// Expected values come from the reference H3 library v4.1.
+
+func TestH3FromPoint(t *testing.T) {
+	tests := []struct {
+		p    Point
+		res  int
+		want string
+	}{
+		{Point{13.7563, 100.5018}, 9, "8964a4b138fffff"},
+		{Point{13.7563, 100.5018}, 0, "8065fffffffffff"},
+		{Point{13.7563, 100.5018}, 15, "8f64a4b138c08cd"},
+		{Point{37.775938728915946, -122.41795063018799}, 7, "872830828ffffff"},
+		{Point{48.8566, 2.3522}, 5, "851fb467fffffff"},
+		{Point{-33.8688, 151.2093}, 12, "8cbe0e35cbad1ff"},
+		{Point{-90, 0}, 4, "84f2939ffffffff"},
+		{Point{90, 0}, 3, "830326fffffffff"},
+		{Point{0, 180}, 8, "887eb57221fffff"},
+		{Point{0, -180}, 8, "887eb57221fffff"},
+		{Point{64.7, 10.536}, 1, "81083ffffffffff"},
+		{Point{-41.3, 174.8}, 11, "8bbb2942d383fff"},
+		{Point{58, 11}, 2, "821f27fffffffff"},
+		{Point{35.6762, 139.6503}, 10, "8a2f5a363ba7fff"},
+	}
+	for _, tt := range tests {
+		got, err := H3FromPoint(tt.p, tt.res)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("H3FromPoint(%v, %v) = %v, %v, want %v", tt.p, tt.res, got, err, tt.want)
+		}
+		if got.Resolution() != tt.res || !got.Valid() {
+			t.Errorf("H3FromPoint(%v, %v) resolution = %v, valid = %v", tt.p, tt.res, got.Resolution(), got.Valid())
+		}
+		// The center of the cell is in the same cell
+		if c, _ := H3FromPoint(got.Point(), tt.res); c != got {
+			t.Errorf("H3FromPoint(%v.Point()) = %v", got, c)
+		}
+	}
+	if _, err := H3FromPoint(Point{}, 16); err != ErrInvalidH3Resolution {
+		t.Errorf("H3FromPoint(16) error = %v", err)
+	}
+	if _, err := H3FromPoint(Point{math.NaN(), 0}, 5); err != ErrInvalidLatitude {
+		t.Errorf("H3FromPoint(NaN) error = %v", err)
+	}
+}
+
+func TestH3FromString(t *testing.T) {
+	for _, s := range []string{"", "xyz", "0", "8964a4b138fffff0", "8f64a4b138c08cf", "8c1c00fffffffff"} {
+		if _, err := H3FromString(s); err != ErrInvalidH3Cell {
+			t.Errorf("H3FromString(%q) error = %v", s, err)
+		}
+	}
+	c, err := H3FromString("8964a4b138fffff")
+	if err != nil || c.BaseCell() != 50 || c.Resolution() != 9 {
+		t.Errorf("H3FromString() = %v, %v", c, err)
+	}
+}
+
+func TestH3Cell_Boundary(t *testing.T) {
+	tests := []struct {
+		cell     string
+		pentagon bool
+		want     Ring
+	}{
+		{"8964a4801a3ffff", false, Ring{
+			{13.759248509, 100.787177122},
+			{13.761166163, 100.786685349},
+			{13.761719770, 100.784733520},
+			{13.760355712, 100.783273477},
+			{13.758438053, 100.783765274},
+			{13.757884457, 100.785717090},
+		}},
+		{"821c07fffffffff", true, Ring{
+			{51.311333257, -143.064496135},
+			{50.710755838, -145.167886625},
+			{49.268689394, -144.888335035},
+			{48.970551942, -142.715064822},
+			{50.214791889, -141.556666302},
+		}},
+		// Class III pentagon, with vertices where edges cross icosahedron edges
+		{"831c00fffffffff", true, Ring{
+			{50.533827142, -143.601655315},
+			{50.306661630, -144.031945813},
+			{50.159869212, -144.155631118},
+			{49.829344191, -143.949729091},
+			{49.708377213, -143.772015622},
+			{49.729485516, -143.222289763},
+			{49.799744366, -142.988158353},
+			{50.143581848, -142.844448070},
+			{50.309188952, -142.874989673},
+			{50.502579451, -143.344037334},
+		}},
+	}
+	for _, tt := range tests {
+		c, _ := H3FromString(tt.cell)
+		if c.IsPentagon() != tt.pentagon {
+			t.Errorf("%v.IsPentagon() = %v", c, c.IsPentagon())
+		}
+		got := c.Boundary()
+		if len(got) != len(tt.want) {
+			t.Errorf("%v.Boundary() has %v vertices, want %v", c, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i].Lat-tt.want[i].Lat) > 1e-9 || math.Abs(got[i].Lon-tt.want[i].Lon) > 1e-9 {
+				t.Errorf("%v.Boundary()[%v] = %v, want %v", c, i, got[i], tt.want[i])
+			}
+		}
+		if !(Polygon{got}).Contains(c.Point()) {
+			t.Errorf("%v.Boundary() does not contain the center", c)
+		}
+	}
+}
+
+func TestH3Cell_ParentChildren(t *testing.T) {
+	c, _ := H3FromPoint(Point{13.7563, 100.5018}, 9)
+	for res := 0; res <= 9; res++ {
+		p := c.Parent(res)
+		want, _ := H3FromPoint(Point{13.7563, 100.5018}, res)
+		if res >= 7 && p != want {
+			// Cells are not exactly nested, but the point is far from the edges here
+			t.Errorf("Parent(%v) = %v, want %v", res, p, want)
+		}
+		if !p.Valid() || p.Resolution() != res {
+			t.Errorf("Parent(%v) = %v is invalid", res, p)
+		}
+		found := false
+		for _, child := range p.Children(9) {
+			found = found || child == c
+		}
+		if !found {
+			t.Errorf("Parent(%v).Children(9) does not hold the cell", res)
+		}
+	}
+	if c.Parent(10) != 0 || c.Children(8) != nil {
+		t.Errorf("Parent(10) and Children(8) must be empty")
+	}
+	if n := len(c.Children(11)); n != 49 {
+		t.Errorf("Children(11) returned %v cells", n)
+	}
+	pentagon, _ := H3FromString("821c07fffffffff")
+	if n := len(pentagon.Children(4)); n != 1+5*(49-1)/6 {
+		t.Errorf("pentagon Children(4) returned %v cells", n)
+	}
+	for _, child := range pentagon.Children(4) {
+		if !child.Valid() || child.Parent(2) != pentagon {
+			t.Errorf("pentagon child %v is invalid", child)
+		}
+	}
+}
+
+func TestH3Cell_KRing(t *testing.T) {
+	tests := []struct {
+		cell string
+		want []string
+	}{
+		{"8964a4801a3ffff", []string{"8964a4801a3ffff", "8964a4801a7ffff", "8964a4801abffff", "8964a4801afffff", "8964a4801b3ffff", "8964a4801b7ffff", "8964a4801bbffff"}},
+		{"821c07fffffffff", []string{"821c07fffffffff", "821c17fffffffff", "821c1ffffffffff", "821c27fffffffff", "821c2ffffffffff", "821c37fffffffff"}},
+	}
+	for _, tt := range tests {
+		c, _ := H3FromString(tt.cell)
+		ring := c.KRing(1)
+		if ring[0] != c {
+			t.Errorf("%v.KRing(1) must start with the cell", c)
+		}
+		var got []string
+		for _, n := range ring {
+			got = append(got, n.String())
+		}
+		sort.Strings(got)
+		if len(got) != len(tt.want) {
+			t.Errorf("%v.KRing(1) = %v, want %v", c, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%v.KRing(1) = %v, want %v", c, got, tt.want)
+				break
+			}
+		}
+	}
+	c, _ := H3FromString("8964a4801a3ffff")
+	if n := len(c.KRing(3)); n != 37 {
+		t.Errorf("KRing(3) returned %v cells", n)
+	}
+}
+
+func TestH3PolygonFill(t *testing.T) {
+	area := Polygon{{{13.76, 100.49}, {13.76, 100.52}, {13.74, 100.52}, {13.74, 100.49}}}
+	cells, err := H3PolygonFill(area, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range cells {
+		got = append(got, c.String())
+	}
+	sort.Strings(got)
+	want := []string{"8864a4b101fffff", "8864a4b103fffff", "8864a4b107fffff", "8864a4b115fffff", "8864a4b139fffff", "8864a4b13bfffff"}
+	if len(got) != len(want) {
+		t.Fatalf("H3PolygonFill() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("H3PolygonFill() = %v, want %v", got, want)
+			break
+		}
+	}
+
+	if cells, _ := H3PolygonFill(area, 9); len(cells) != 58 {
+		t.Errorf("H3PolygonFill(9) returned %v cells, want 58", len(cells))
+	}
+	area = append(area, Ring{{13.755, 100.50}, {13.755, 100.51}, {13.745, 100.51}, {13.745, 100.50}})
+	if cells, _ := H3PolygonFill(area, 9); len(cells) != 49 {
+		t.Errorf("H3PolygonFill(9) with a hole returned %v cells, want 49", len(cells))
+	}
+	if _, err := H3PolygonFill(area, -1); err != ErrInvalidH3Resolution {
+		t.Errorf("H3PolygonFill(-1) error = %v", err)
+	}
+}
+
+func BenchmarkH3FromPoint(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		H3FromPoint(Point{13.7563, 100.5018}, 9)
+	}
+}
@@ -0,0 +1,315 @@
+package geo
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+type (
+	// SpatialIndex is an in-memory index of points for nearest neighbors, radius and bounding box queries.
+	// It is a KD-tree of the positions of the points on the unit sphere, so it works across the
+	// antimeridian and around the poles. It is not safe for concurrent writes.
+	SpatialIndex struct {
+		root    *kdNode
+		size    int
+		deleted int
+	}
+
+	// IndexItem is a point stored in the index with its payload, such as a store ID.
+	IndexItem struct {
+		Point Point
+		Data  interface{}
+	}
+
+	// Neighbor is an item found by a query with its distance in meters to the query point.
+	Neighbor struct {
+		IndexItem
+		Distance float64
+	}
+
+	// kdNode is a node of the KD-tree. Deleted nodes stay in the tree until it is rebuilt.
+	kdNode struct {
+		item        IndexItem
+		v           vector
+		axis        int
+		left, right *kdNode
+		deleted     bool
+	}
+
+	// neighborHeap keeps the k nearest nodes found, the farthest first.
+	neighborHeap []kdCandidate
+
+	kdCandidate struct {
+		node  *kdNode
+		chord float64
+	}
+)
+
+// NewSpatialIndex returns an index holding the items. Loading all items at once builds a balanced tree,
+// which is faster to query than inserting items one by one.
+//  index := geo.NewSpatialIndex([]geo.IndexItem{{Point: store.Point, Data: store.ID}, ...})
+func NewSpatialIndex(items []IndexItem) *SpatialIndex {
+	nodes := make([]*kdNode, len(items))
+	for i, item := range items {
+		nodes[i] = &kdNode{item: item, v: pointToVector(item.Point)}
+	}
+	return &SpatialIndex{root: buildKDTree(nodes, 0), size: len(items)}
+}
+
+// Len returns the number of items in the index.
+func (idx *SpatialIndex) Len() int {
+	return idx.size
+}
+
+// Insert adds a point with its payload to the index.
+func (idx *SpatialIndex) Insert(p Point, data interface{}) {
+	n := &kdNode{item: IndexItem{Point: p, Data: data}, v: pointToVector(p)}
+	idx.size++
+	if idx.root == nil {
+		idx.root = n
+		return
+	}
+	parent := idx.root
+	for {
+		child := &parent.right
+		if n.v.coord(parent.axis) < parent.v.coord(parent.axis) {
+			child = &parent.left
+		}
+		if *child == nil {
+			n.axis = (parent.axis + 1) % 3
+			*child = n
+			return
+		}
+		parent = *child
+	}
+}
+
+// Delete removes an item with the same point and payload from the index.
+// Payloads are compared with ==, so only comparable payloads can be deleted: use DeleteFunc for payloads
+// such as slices, maps or structs holding them. It returns false if there is no such item.
+func (idx *SpatialIndex) Delete(p Point, data interface{}) bool {
+	return idx.DeleteFunc(p, func(d interface{}) bool { return equalPayloads(d, data) })
+}
+
+// equalPayloads returns a == b, or false when == panics on uncomparable values,
+// such as structs with an interface field holding a slice.
+func equalPayloads(a, b interface{}) (equal bool) {
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+	return a == b
+}
+
+// DeleteFunc removes an item at the point whose payload matches. It returns false if there is no such item.
+//  index.DeleteFunc(p, func(data interface{}) bool { return bytes.Equal(data.([]byte), key) })
+func (idx *SpatialIndex) DeleteFunc(p Point, match func(data interface{}) bool) bool {
+	v := pointToVector(p)
+	var found *kdNode
+	idx.root.walk(func(n *kdNode) bool {
+		if !n.deleted && n.item.Point == p && match(n.item.Data) {
+			found = n
+			return false
+		}
+		return true
+	}, func(n *kdNode) (left, right bool) {
+		c := v.coord(n.axis) - n.v.coord(n.axis)
+		return c <= 0, c >= 0
+	})
+	if found == nil {
+		return false
+	}
+	found.deleted = true
+	idx.size--
+	idx.deleted++
+	// Rebuild the tree when it holds more deleted nodes than items
+	if idx.deleted > idx.size {
+		nodes := make([]*kdNode, 0, idx.size)
+		idx.root.walk(func(n *kdNode) bool {
+			if !n.deleted {
+				nodes = append(nodes, &kdNode{item: n.item, v: n.v})
+			}
+			return true
+		}, nil)
+		idx.root = buildKDTree(nodes, 0)
+		idx.deleted = 0
+	}
+	return true
+}
+
+// Nearest returns the k items nearest to the point, the nearest first.
+//  stores := index.Nearest(user, 10)
+func (idx *SpatialIndex) Nearest(p Point, k int) []Neighbor {
+	if k <= 0 {
+		return nil
+	}
+	v := pointToVector(p)
+	h := make(neighborHeap, 0, k)
+	var search func(n *kdNode)
+	search = func(n *kdNode) {
+		if n == nil {
+			return
+		}
+		if !n.deleted {
+			d := v.chord(n.v)
+			if len(h) < k {
+				heap.Push(&h, kdCandidate{n, d})
+			} else if d < h[0].chord {
+				h[0] = kdCandidate{n, d}
+				heap.Fix(&h, 0)
+			}
+		}
+		diff := v.coord(n.axis) - n.v.coord(n.axis)
+		near, far := n.left, n.right
+		if diff >= 0 {
+			near, far = n.right, n.left
+		}
+		search(near)
+		if len(h) < k || math.Abs(diff) < h[0].chord {
+			search(far)
+		}
+	}
+	search(idx.root)
+
+	neighbors := make([]Neighbor, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		c := heap.Pop(&h).(kdCandidate)
+		neighbors[i] = Neighbor{IndexItem: c.node.item, Distance: p.Distance(c.node.item.Point)}
+	}
+	return neighbors
+}
+
+// Radius returns the items closer than radius meters to the point, the nearest first.
+//  stores := index.Radius(user, 5000)
+func (idx *SpatialIndex) Radius(p Point, radius float64) []Neighbor {
+	v := pointToVector(p)
+	// Length of the chord of the sphere matching the radius
+	chord := 2 * math.Sin(math.Min(radius/EarthRadius, math.Pi)/2)
+	var neighbors []Neighbor
+	idx.root.walk(func(n *kdNode) bool {
+		if !n.deleted && v.chord(n.v) <= chord {
+			if d := p.Distance(n.item.Point); d <= radius {
+				neighbors = append(neighbors, Neighbor{IndexItem: n.item, Distance: d})
+			}
+		}
+		return true
+	}, func(n *kdNode) (left, right bool) {
+		diff := v.coord(n.axis) - n.v.coord(n.axis)
+		return diff <= chord, diff >= -chord
+	})
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].Distance < neighbors[j].Distance })
+	return neighbors
+}
+
+// InBoundingBox returns the items inside the box.
+func (idx *SpatialIndex) InBoundingBox(b BoundingBox) (items []IndexItem) {
+	center := b.Center()
+	antipode := Point{Lat: -center.Lat, Lon: normalizeLongitude(center.Lon + 180)}
+	if b.Contains(antipode) || b.MaxLon-b.MinLon > 180 {
+		// Huge box, we check all items
+		idx.root.walk(func(n *kdNode) bool {
+			if !n.deleted && b.Contains(n.item.Point) {
+				items = append(items, n.item)
+			}
+			return true
+		}, nil)
+		return
+	}
+	// The farthest points of a box at most 180° wide from its center are its corners:
+	// distances grow with the difference of longitude along parallels, and have no maximum inside meridian edges.
+	var radius float64
+	for _, corner := range []Point{{b.MinLat, b.MinLon}, {b.MinLat, b.MaxLon}, {b.MaxLat, b.MinLon}, {b.MaxLat, b.MaxLon}} {
+		radius = math.Max(radius, center.Distance(corner))
+	}
+	for _, n := range idx.Radius(center, radius*(1+1e-9)) {
+		if b.Contains(n.Point) {
+			items = append(items, n.IndexItem)
+		}
+	}
+	return
+}
+
+// Items returns all items of the index.
+func (idx *SpatialIndex) Items() (items []IndexItem) {
+	items = make([]IndexItem, 0, idx.size)
+	idx.root.walk(func(n *kdNode) bool {
+		if !n.deleted {
+			items = append(items, n.item)
+		}
+		return true
+	}, nil)
+	return
+}
+
+// buildKDTree returns a balanced tree of the nodes, splitting on the median.
+func buildKDTree(nodes []*kdNode, axis int) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].v.coord(axis) < nodes[j].v.coord(axis) })
+	mid := len(nodes) / 2
+	// Equal values must go right, as in Insert
+	for mid > 0 && nodes[mid-1].v.coord(axis) == nodes[mid].v.coord(axis) {
+		mid--
+	}
+	n := nodes[mid]
+	n.axis = axis
+	n.left = buildKDTree(nodes[:mid], (axis+1)%3)
+	n.right = buildKDTree(nodes[mid+1:], (axis+1)%3)
+	return n
+}
+
+// walk visits the nodes of the tree until visit returns false.
+// If children is not nil, it tells which children of a node must be visited.
+func (n *kdNode) walk(visit func(*kdNode) bool, children func(*kdNode) (left, right bool)) bool {
+	if n == nil {
+		return true
+	}
+	if !visit(n) {
+		return false
+	}
+	left, right := true, true
+	if children != nil {
+		left, right = children(n)
+	}
+	if left && !n.left.walk(visit, children) {
+		return false
+	}
+	if right && !n.right.walk(visit, children) {
+		return false
+	}
+	return true
+}
+
+// coord returns the coordinate of the vector on the axis.
+func (v vector) coord(axis int) float64 {
+	switch axis {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	}
+	return v.Z
+}
+
+// chord returns the length of the straight line between two vectors.
+func (v vector) chord(w vector) float64 {
+	return v.add(w.scale(-1)).norm()
+}
+
+func (h neighborHeap) Len() int { return len(h) }
+
+func (h neighborHeap) Less(i, j int) bool { return h[i].chord > h[j].chord }
+
+func (h neighborHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(kdCandidate)) }
+
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
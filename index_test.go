@@ -0,0 +1,231 @@
+package geo
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// randomItems returns n items spread on the whole Earth, with their index as payload.
+func randomItems(n int) []IndexItem {
+	r := rand.New(rand.NewSource(42))
+	items := make([]IndexItem, n)
+	for i := range items {
+		lat := toDegrees(math.Asin(2*r.Float64() - 1))
+		items[i] = IndexItem{Point: Point{lat, r.Float64()*360 - 180}, Data: i}
+	}
+	return items
+}
+
+// bruteForce returns the items closer than radius to the point, the nearest first.
+func bruteForce(items []IndexItem, p Point, radius float64) (neighbors []Neighbor) {
+	for _, item := range items {
+		if d := p.Distance(item.Point); d <= radius {
+			neighbors = append(neighbors, Neighbor{IndexItem: item, Distance: d})
+		}
+	}
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].Distance < neighbors[j].Distance })
+	return
+}
+
+func TestSpatialIndex_Nearest(t *testing.T) {
+	items := randomItems(5000)
+	bulk := NewSpatialIndex(items)
+	inserted := NewSpatialIndex(nil)
+	for _, item := range items {
+		inserted.Insert(item.Point, item.Data)
+	}
+	queries := []Point{{0, 0}, {13.75, 100.5}, {0, 180}, {-12, -179.99}, {89.9, 45}, {-90, 0}}
+	for _, index := range []*SpatialIndex{bulk, inserted} {
+		if index.Len() != len(items) {
+			t.Errorf("Len() = %v, want %v", index.Len(), len(items))
+		}
+		for _, q := range queries {
+			want := bruteForce(items, q, math.Inf(1))[:10]
+			got := index.Nearest(q, 10)
+			if len(got) != len(want) {
+				t.Fatalf("Nearest(%v) returned %v items", q, len(got))
+			}
+			for i := range got {
+				if got[i].Data != want[i].Data || got[i].Distance != want[i].Distance {
+					t.Errorf("Nearest(%v)[%v] = %v, want %v", q, i, got[i], want[i])
+				}
+			}
+		}
+	}
+	if got := bulk.Nearest(Point{}, 0); len(got) != 0 {
+		t.Errorf("Nearest(0) = %v", got)
+	}
+	if got := NewSpatialIndex(nil).Nearest(Point{}, 3); len(got) != 0 {
+		t.Errorf("Nearest() on empty index = %v", got)
+	}
+}
+
+func TestSpatialIndex_Radius(t *testing.T) {
+	items := randomItems(5000)
+	index := NewSpatialIndex(items)
+	tests := []struct {
+		p      Point
+		radius float64
+	}{
+		{Point{0, 0}, 500000},
+		{Point{13.75, 100.5}, 1000000},
+		{Point{10, 179.9}, 800000},
+		{Point{89, -60}, 1500000},
+		{Point{0, 0}, 30000000},
+	}
+	for _, tt := range tests {
+		want := bruteForce(items, tt.p, tt.radius)
+		got := index.Radius(tt.p, tt.radius)
+		if len(got) != len(want) {
+			t.Errorf("Radius(%v, %v) returned %v items, want %v", tt.p, tt.radius, len(got), len(want))
+			continue
+		}
+		for i := range got {
+			if got[i].Distance != want[i].Distance {
+				t.Errorf("Radius(%v, %v)[%v] = %v, want %v", tt.p, tt.radius, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestSpatialIndex_InBoundingBox(t *testing.T) {
+	items := randomItems(5000)
+	index := NewSpatialIndex(items)
+	boxes := []BoundingBox{
+		{MinLat: 5, MinLon: 97, MaxLat: 21, MaxLon: 106},
+		{MinLat: -60, MinLon: -180, MaxLat: -40, MaxLon: -150},
+		{MinLat: 70, MinLon: -180, MaxLat: 90, MaxLon: 180},
+		{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180},
+		{MinLat: -10, MinLon: 0, MaxLat: 10, MaxLon: 0},
+	}
+	for _, b := range boxes {
+		want := map[interface{}]bool{}
+		for _, item := range items {
+			if b.Contains(item.Point) {
+				want[item.Data] = true
+			}
+		}
+		got := index.InBoundingBox(b)
+		if len(got) != len(want) {
+			t.Errorf("InBoundingBox(%v) returned %v items, want %v", b, len(got), len(want))
+		}
+		for _, item := range got {
+			if !want[item.Data] {
+				t.Errorf("InBoundingBox(%v) returned %v", b, item)
+			}
+		}
+	}
+	// Points on the equator at the edge of a wide box are farther from its center than the corners
+	index = NewSpatialIndex([]IndexItem{{Point{0, 165}, "east"}, {Point{0, 0}, "center"}, {Point{0, -175}, "out"}})
+	for _, b := range []BoundingBox{{MinLat: -80, MinLon: -170, MaxLat: 80, MaxLon: 170}, {MinLat: -10, MinLon: -10, MaxLat: 10, MaxLon: 170}} {
+		if got := index.InBoundingBox(b); len(got) != 2 {
+			t.Errorf("InBoundingBox(%v) = %v, want 2 items", b, got)
+		}
+	}
+}
+
+func TestSpatialIndex_Delete(t *testing.T) {
+	items := randomItems(1000)
+	index := NewSpatialIndex(items)
+	for i, item := range items {
+		if i%3 == 0 {
+			continue
+		}
+		if !index.Delete(item.Point, item.Data) {
+			t.Fatalf("Delete(%v) = false", item)
+		}
+	}
+	if index.Delete(items[1].Point, items[1].Data) {
+		t.Errorf("Delete() of a deleted item = true")
+	}
+	if index.Delete(items[0].Point, "other") {
+		t.Errorf("Delete() with another payload = true")
+	}
+
+	var left []IndexItem
+	for i, item := range items {
+		if i%3 == 0 {
+			left = append(left, item)
+		}
+	}
+	if index.Len() != len(left) || len(index.Items()) != len(left) {
+		t.Fatalf("Len() = %v, want %v", index.Len(), len(left))
+	}
+	want := bruteForce(left, Point{48.85, 2.35}, 2000000)
+	got := index.Radius(Point{48.85, 2.35}, 2000000)
+	if len(got) != len(want) {
+		t.Errorf("Radius() after Delete returned %v items, want %v", len(got), len(want))
+	}
+	if n := index.Nearest(Point{48.85, 2.35}, 1); n[0].Data != want[0].Data {
+		t.Errorf("Nearest() after Delete = %v, want %v", n[0], want[0])
+	}
+}
+
+func TestSpatialIndex_DeleteFunc(t *testing.T) {
+	// Payloads that cannot be compared with ==
+	p := Point{13.7563, 100.5018}
+	index := NewSpatialIndex([]IndexItem{{Point: p, Data: []byte("a")}, {Point: p, Data: []byte("b")}, {Point: p, Data: map[string]int{"c": 1}}})
+	if index.Delete(p, []byte("a")) || index.Delete(p, map[string]int{"c": 1}) || index.Delete(p, "a") {
+		t.Error("Delete(uncomparable payload) = true")
+	}
+	match := func(key string) func(interface{}) bool {
+		return func(data interface{}) bool {
+			b, ok := data.([]byte)
+			return ok && bytes.Equal(b, []byte(key))
+		}
+	}
+	if !index.DeleteFunc(p, match("b")) || index.DeleteFunc(p, match("b")) || index.DeleteFunc(Point{13, 100}, match("a")) {
+		t.Error("DeleteFunc() did not delete the matching item once")
+	}
+	if items := index.Items(); len(items) != 2 || index.Len() != 2 || !index.DeleteFunc(p, match("a")) {
+		t.Errorf("Items() after DeleteFunc = %v", items)
+	}
+	// Comparable types holding uncomparable values
+	type payload struct{ V interface{} }
+	index = NewSpatialIndex([]IndexItem{{Point: p, Data: payload{[]int{1}}}, {Point: p, Data: payload{2}}})
+	if index.Delete(p, payload{[]int{1}}) || index.Delete(p, payload{3}) || !index.Delete(p, payload{2}) || index.Len() != 1 {
+		t.Error("Delete(struct holding a slice) did not delete only the comparable payload")
+	}
+}
+
+func BenchmarkSpatialIndex_Nearest(b *testing.B) {
+	index := NewSpatialIndex(randomItems(100000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Nearest(Point{13.75, 100.5}, 10)
+	}
+}
+
+func BenchmarkBruteForce_Nearest(b *testing.B) {
+	items := randomItems(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bruteForce(items, Point{13.75, 100.5}, math.Inf(1))
+	}
+}
+
+func BenchmarkSpatialIndex_Radius(b *testing.B) {
+	index := NewSpatialIndex(randomItems(100000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Radius(Point{13.75, 100.5}, 100000)
+	}
+}
+
+func BenchmarkBruteForce_Radius(b *testing.B) {
+	items := randomItems(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bruteForce(items, Point{13.75, 100.5}, 100000)
+	}
+}
+
+func BenchmarkNewSpatialIndex(b *testing.B) {
+	items := randomItems(100000)
+	for i := 0; i < b.N; i++ {
+		NewSpatialIndex(items)
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/frontware/geo/internal/gentimezones
+
+go 1.24
+
+require (
+	github.com/ringsaturn/tzf v1.0.2
+	github.com/ringsaturn/tzf-rel-lite v0.0.2025-b2
+	google.golang.org/protobuf v1.36.9
+)
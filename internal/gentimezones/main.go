@@ -0,0 +1,110 @@
+// Command gentimezones writes timezones.bin.gz, the timezone boundaries embedded by the geo package.
+//
+// Boundaries are from timezone-boundary-builder https://github.com/evansiroky/timezone-boundary-builder
+// as simplified by tzf https://github.com/ringsaturn/tzf-rel-lite, under the Open Database License.
+// The release of the boundaries is the version of tzf-rel-lite in go.mod, update it to refresh the data:
+//
+//  cd internal/gentimezones
+//  go get github.com/ringsaturn/tzf-rel-lite@latest
+//  cd ../..
+//  go generate -run gentimezones
+//
+// gentimezones is a module of its own, so the geo package keeps using the standard library only.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+
+	tzfrellite "github.com/ringsaturn/tzf-rel-lite"
+	pb "github.com/ringsaturn/tzf/gen/go/tzf/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// scale is the number of units of the data in one degree, timezoneScale of the geo package.
+const scale = 1e4
+
+func main() {
+	output := flag.String("o", "timezones.bin.gz", "output file")
+	flag.Parse()
+
+	timezones := &pb.Timezones{}
+	if err := proto.Unmarshal(tzfrellite.LiteData, timezones); err != nil {
+		log.Fatal(err)
+	}
+	data, rings, points := encode(timezones)
+
+	var gz bytes.Buffer
+	w, err := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if err != nil {
+		log.Fatal(err)
+	}
+	w.Write(data)
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, gz.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: release %s, %d timezones, %d rings, %d points, %d bytes\n",
+		*output, timezones.Version, len(timezones.Timezones), rings, points, gz.Len())
+}
+
+// encode returns the boundaries in the format read by readTimezones of the geo package: a sequence of varints,
+// the release of the boundaries, the number of timezones, then for each timezone its name, its number of polygons
+// and for each polygon its number of rings, the outer ring first. Each ring is its number of points and the
+// differences in longitude and latitude from the previous point, in 1e-4 degrees.
+// Rings are not closed, and points repeated after rounding are left out.
+func encode(timezones *pb.Timezones) (data []byte, rings, points int) {
+	var buf bytes.Buffer
+	tmp := make([]byte, binary.MaxVarintLen64)
+	uvarint := func(v uint64) { buf.Write(tmp[:binary.PutUvarint(tmp, v)]) }
+	varint := func(v int64) { buf.Write(tmp[:binary.PutVarint(tmp, v)]) }
+	text := func(s string) {
+		uvarint(uint64(len(s)))
+		buf.WriteString(s)
+	}
+
+	text(timezones.Version)
+	uvarint(uint64(len(timezones.Timezones)))
+	for _, tz := range timezones.Timezones {
+		text(tz.Name)
+		uvarint(uint64(len(tz.Polygons)))
+		for _, polygon := range tz.Polygons {
+			all := [][]*pb.Point{polygon.Points}
+			for _, hole := range polygon.Holes {
+				all = append(all, hole.Points)
+			}
+			uvarint(uint64(len(all)))
+			for _, ring := range all {
+				if n := len(ring); n > 1 && ring[0].Lng == ring[n-1].Lng && ring[0].Lat == ring[n-1].Lat {
+					ring = ring[:n-1]
+				}
+				var rounded [][2]int64
+				for _, p := range ring {
+					q := [2]int64{int64(math.Round(float64(p.Lng) * scale)), int64(math.Round(float64(p.Lat) * scale))}
+					if len(rounded) > 0 && rounded[len(rounded)-1] == q {
+						continue
+					}
+					rounded = append(rounded, q)
+				}
+				uvarint(uint64(len(rounded)))
+				var x, y int64
+				for _, q := range rounded {
+					varint(q[0] - x)
+					varint(q[1] - y)
+					x, y = q[0], q[1]
+				}
+				rings++
+				points += len(rounded)
+			}
+		}
+	}
+	return buf.Bytes(), rings, points
+}
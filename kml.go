@@ -0,0 +1,528 @@
+package geo
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KMLNamespace is the XML namespace of KML 2.2 documents.
+const KMLNamespace = "http://www.opengis.net/kml/2.2"
+
+type (
+	// KMLFolder is a KML document or a folder of a document, with placemarks and sub folders.
+	// Styles, overlays and network links are not supported, they are skipped when reading.
+	// Details https://developers.google.com/kml/documentation/kmlreference
+	KMLFolder struct {
+		Name        string
+		Description string
+		Placemarks  []KMLPlacemark
+		Folders     []KMLFolder
+	}
+
+	// KMLPlacemark is a geometry with a name, a description and extended data.
+	// Geometry is a Point, a LineString, a Polygon, a MultiPoint, a MultiLineString, a MultiPolygon,
+	// a GeometryCollection or nil. Altitudes are ignored.
+	KMLPlacemark struct {
+		ID          string
+		Name        string
+		Description string
+		// ExtendedData holds the values of the Data and SimpleData elements by name.
+		ExtendedData map[string]string
+		Geometry     Geometry
+	}
+
+	// kmlExtendedData is used to decode ExtendedData elements, with untyped data and data of a schema.
+	kmlExtendedData struct {
+		Data []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value"`
+		} `xml:"Data"`
+		SimpleData []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"SchemaData>SimpleData"`
+	}
+
+	// kmlGeometry is used to decode Point, LineString, LinearRing, Polygon and MultiGeometry elements.
+	kmlGeometry struct {
+		XMLName     xml.Name
+		Coordinates string   `xml:"coordinates"`
+		Outer       string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+		Inner       []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+		// Geometries are the children of MultiGeometry elements, other elements are ignored.
+		Geometries []kmlGeometry `xml:",any"`
+	}
+)
+
+// ErrInvalidKML is returned when reading XML documents that are not KML documents or have invalid coordinates,
+// and when writing geometries that KML does not support.
+var ErrInvalidKML = errors.New("Invalid KML")
+
+// ReadKML reads a KML document, such as the export of a Google My Maps map.
+// The folder returned is the Document element of the file.
+//  file, _ := os.Open("zones.kml")
+//  doc, err := geo.ReadKML(file)
+func ReadKML(r io.Reader) (*KMLFolder, error) {
+	d := xml.NewDecoder(r)
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, ErrInvalidKML
+		} else if err != nil {
+			return nil, err
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			if start.Name.Local != "kml" {
+				return nil, ErrInvalidKML
+			}
+			f := &KMLFolder{}
+			if err := readKMLFolder(d, f); err != nil {
+				return nil, err
+			}
+			return f, nil
+		}
+	}
+}
+
+// ReadKMZ reads a KMZ file: a zip archive with a KML document named doc.kml, or else the first .kml file of the archive.
+//  file, _ := os.Open("zones.kmz")
+//  info, _ := file.Stat()
+//  doc, err := geo.ReadKMZ(file, info.Size())
+func ReadKMZ(r io.ReaderAt, size int64) (*KMLFolder, error) {
+	z, err := zip.NewReader(r, size)
+	if err == zip.ErrFormat {
+		return nil, ErrInvalidKML
+	} else if err != nil {
+		return nil, err
+	}
+	var doc *zip.File
+	for _, f := range z.File {
+		if strings.EqualFold(path.Ext(f.Name), ".kml") && (doc == nil || f.Name == "doc.kml") {
+			doc = f
+		}
+	}
+	if doc == nil {
+		return nil, ErrInvalidKML
+	}
+	rc, err := doc.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ReadKML(rc)
+}
+
+// readKMLFolder reads the children of an element into f, up to the end of the element.
+// Document elements are read into f: a file has a single document, holding the features of the file.
+func readKMLFolder(d *xml.Decoder, f *KMLFolder) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		} else if err != nil {
+			return err
+		}
+		switch t := t.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "name":
+				err = d.DecodeElement(&f.Name, &t)
+			case "description":
+				err = d.DecodeElement(&f.Description, &t)
+			case "Document":
+				err = readKMLFolder(d, f)
+			case "Folder":
+				var folder KMLFolder
+				if err = readKMLFolder(d, &folder); err == nil {
+					f.Folders = append(f.Folders, folder)
+				}
+			case "Placemark":
+				var p KMLPlacemark
+				if p, err = readKMLPlacemark(d, t); err == nil {
+					f.Placemarks = append(f.Placemarks, p)
+				}
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readKMLPlacemark reads a Placemark element. The first geometry of the placemark is kept.
+func readKMLPlacemark(d *xml.Decoder, start xml.StartElement) (KMLPlacemark, error) {
+	var p KMLPlacemark
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "id" {
+			p.ID = attr.Value
+		}
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return p, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return p, err
+		}
+		switch t := t.(type) {
+		case xml.EndElement:
+			return p, nil
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "name":
+				err = d.DecodeElement(&p.Name, &t)
+			case "description":
+				err = d.DecodeElement(&p.Description, &t)
+			case "ExtendedData":
+				var data kmlExtendedData
+				if err = d.DecodeElement(&data, &t); err != nil {
+					break
+				}
+				p.ExtendedData = make(map[string]string, len(data.Data)+len(data.SimpleData))
+				for _, v := range data.Data {
+					p.ExtendedData[v.Name] = v.Value
+				}
+				for _, v := range data.SimpleData {
+					p.ExtendedData[v.Name] = v.Value
+				}
+			case "Point", "LineString", "LinearRing", "Polygon", "MultiGeometry":
+				var g kmlGeometry
+				if err = d.DecodeElement(&g, &t); err == nil && p.Geometry == nil {
+					p.Geometry, err = g.geometry()
+				}
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return p, err
+			}
+		}
+	}
+}
+
+// geometry returns the geometry of the element, or nil for elements that are not geometries.
+// A LinearRing is a polygon without holes. A MultiGeometry is a MultiPoint, a MultiLineString or a MultiPolygon
+// when its geometries have the same type, or a GeometryCollection.
+func (g kmlGeometry) geometry() (Geometry, error) {
+	switch g.XMLName.Local {
+	case "Point":
+		points, err := parseKMLCoordinates(g.Coordinates)
+		if err != nil || len(points) != 1 {
+			return nil, ErrInvalidKML
+		}
+		return points[0], nil
+	case "LineString":
+		points, err := parseKMLCoordinates(g.Coordinates)
+		return LineString(points), err
+	case "LinearRing":
+		points, err := parseKMLCoordinates(g.Coordinates)
+		return Polygon{Ring(points)}, err
+	case "Polygon":
+		p := make(Polygon, 0, 1+len(g.Inner))
+		for _, c := range append([]string{g.Outer}, g.Inner...) {
+			points, err := parseKMLCoordinates(c)
+			if err != nil {
+				return nil, err
+			}
+			if len(points) > 0 {
+				p = append(p, Ring(points))
+			}
+		}
+		return p, nil
+	case "MultiGeometry":
+		c := make(GeometryCollection, 0, len(g.Geometries))
+		types := map[string]bool{}
+		for _, child := range g.Geometries {
+			geometry, err := child.geometry()
+			if err != nil {
+				return nil, err
+			}
+			if geometry != nil {
+				c = append(c, geometry)
+				types[geometry.GeometryType()] = true
+			}
+		}
+		if len(types) != 1 {
+			return c, nil
+		}
+		switch c[0].(type) {
+		case Point:
+			m := make(MultiPoint, len(c))
+			for i, p := range c {
+				m[i] = p.(Point)
+			}
+			return m, nil
+		case LineString:
+			m := make(MultiLineString, len(c))
+			for i, l := range c {
+				m[i] = l.(LineString)
+			}
+			return m, nil
+		case Polygon:
+			m := make(MultiPolygon, len(c))
+			for i, p := range c {
+				m[i] = p.(Polygon)
+			}
+			return m, nil
+		}
+		return c, nil
+	}
+	return nil, nil
+}
+
+// parseKMLCoordinates returns the points of KML coordinates: tuples of longitude, latitude and optional altitude
+// separated by commas, separated by white space.
+func parseKMLCoordinates(s string) ([]Point, error) {
+	tuples := strings.Fields(s)
+	points := make([]Point, len(tuples))
+	for i, tuple := range tuples {
+		c := strings.Split(tuple, ",")
+		if len(c) < 2 || len(c) > 3 {
+			return nil, ErrInvalidKML
+		}
+		lon, err := strconv.ParseFloat(c[0], 64)
+		if err != nil {
+			return nil, ErrInvalidKML
+		}
+		lat, err := strconv.ParseFloat(c[1], 64)
+		if err != nil {
+			return nil, ErrInvalidKML
+		}
+		points[i] = Point{Lat: lat, Lon: lon}
+	}
+	return points, nil
+}
+
+// WriteKML writes a KML 2.2 document with the folder as Document element. Rings of polygons are closed if needed.
+//  err := geo.WriteKML(file, &geo.KMLFolder{Name: "Zones", Placemarks: []geo.KMLPlacemark{{Name: "Zone 1", Geometry: zone}}})
+func WriteKML(w io.Writer, f *KMLFolder) error {
+	e := &xmlWriter{w: bufio.NewWriter(w)}
+	e.raw(xml.Header)
+	e.start("kml", "xmlns", KMLNamespace)
+	e.kmlFolder("Document", *f)
+	e.end("kml")
+	e.raw("\n")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// WriteKMZ writes a KMZ file: a zip archive with the KML document in doc.kml.
+//  err := geo.WriteKMZ(file, doc)
+func WriteKMZ(w io.Writer, f *KMLFolder) error {
+	z := zip.NewWriter(w)
+	doc, err := z.Create("doc.kml")
+	if err != nil {
+		return err
+	}
+	if err := WriteKML(doc, f); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// kmlFolder writes a Document or a Folder element.
+func (e *xmlWriter) kmlFolder(name string, f KMLFolder) {
+	e.start(name)
+	e.text("name", f.Name)
+	e.text("description", f.Description)
+	for _, p := range f.Placemarks {
+		e.kmlPlacemark(p)
+	}
+	for _, folder := range f.Folders {
+		e.kmlFolder("Folder", folder)
+	}
+	e.end(name)
+}
+
+// kmlPlacemark writes a Placemark element. Extended data are written in the order of names.
+func (e *xmlWriter) kmlPlacemark(p KMLPlacemark) {
+	if p.ID != "" {
+		e.start("Placemark", "id", p.ID)
+	} else {
+		e.start("Placemark")
+	}
+	e.text("name", p.Name)
+	e.text("description", p.Description)
+	if len(p.ExtendedData) > 0 {
+		names := make([]string, 0, len(p.ExtendedData))
+		for name := range p.ExtendedData {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		e.start("ExtendedData")
+		for _, name := range names {
+			e.start("Data", "name", name)
+			e.text("value", p.ExtendedData[name])
+			e.end("Data")
+		}
+		e.end("ExtendedData")
+	}
+	if p.Geometry != nil {
+		e.kmlGeometry(p.Geometry)
+	}
+	e.end("Placemark")
+}
+
+// kmlGeometry writes the element of a geometry, multi geometries and collections as MultiGeometry elements.
+func (e *xmlWriter) kmlGeometry(g Geometry) {
+	switch g := g.(type) {
+	case Point:
+		e.start("Point")
+		e.text("coordinates", kmlCoordinates([]Point{g}, false))
+		e.end("Point")
+	case LineString:
+		e.start("LineString")
+		e.text("coordinates", kmlCoordinates(g, false))
+		e.end("LineString")
+	case Polygon:
+		e.start("Polygon")
+		for i, r := range g {
+			boundary := map[bool]string{true: "outerBoundaryIs", false: "innerBoundaryIs"}[i == 0]
+			e.start(boundary)
+			e.start("LinearRing")
+			e.text("coordinates", kmlCoordinates(r, true))
+			e.end("LinearRing")
+			e.end(boundary)
+		}
+		e.end("Polygon")
+	case MultiPoint:
+		e.start("MultiGeometry")
+		for _, p := range g {
+			e.kmlGeometry(p)
+		}
+		e.end("MultiGeometry")
+	case MultiLineString:
+		e.start("MultiGeometry")
+		for _, l := range g {
+			e.kmlGeometry(l)
+		}
+		e.end("MultiGeometry")
+	case MultiPolygon:
+		e.start("MultiGeometry")
+		for _, p := range g {
+			e.kmlGeometry(p)
+		}
+		e.end("MultiGeometry")
+	case GeometryCollection:
+		e.start("MultiGeometry")
+		for _, child := range g {
+			e.kmlGeometry(child)
+		}
+		e.end("MultiGeometry")
+	default:
+		if e.err == nil {
+			e.err = ErrInvalidKML
+		}
+	}
+}
+
+// kmlCoordinates returns the KML coordinates of points, closing the ring if asked.
+func kmlCoordinates(points []Point, closed bool) string {
+	var b strings.Builder
+	for i, p := range points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(formatFloat(p.Lon) + "," + formatFloat(p.Lat))
+	}
+	if closed && len(points) > 0 && points[0] != points[len(points)-1] {
+		b.WriteString(" " + formatFloat(points[0].Lon) + "," + formatFloat(points[0].Lat))
+	}
+	return b.String()
+}
+
+// Feature returns the placemark as a GeoJSON feature. The ID of the placemark is the ID of the feature,
+// name, description and extended data are the properties.
+//  for _, p := range doc.Placemarks {
+//      data, err := json.Marshal(p.Feature())
+//  }
+func (p KMLPlacemark) Feature() Feature {
+	f := Feature{Geometry: p.Geometry, Properties: make(map[string]interface{}, len(p.ExtendedData)+2)}
+	if p.ID != "" {
+		f.ID = p.ID
+	}
+	for name, v := range p.ExtendedData {
+		f.Properties[name] = v
+	}
+	if p.Name != "" {
+		f.Properties["name"] = p.Name
+	}
+	if p.Description != "" {
+		f.Properties["description"] = p.Description
+	}
+	return f
+}
+
+// NewKMLPlacemark returns a placemark of a GeoJSON feature. String properties "name" and "description"
+// are the name and description of the placemark, other properties are extended data.
+// Values that are not strings are kept as JSON.
+//  p := geo.NewKMLPlacemark(feature)
+func NewKMLPlacemark(f Feature) KMLPlacemark {
+	p := KMLPlacemark{Geometry: f.Geometry}
+	if f.ID != nil {
+		p.ID = kmlValue(f.ID)
+	}
+	for name, v := range f.Properties {
+		s, ok := v.(string)
+		switch {
+		case name == "name" && ok:
+			p.Name = s
+		case name == "description" && ok:
+			p.Description = s
+		default:
+			if p.ExtendedData == nil {
+				p.ExtendedData = map[string]string{}
+			}
+			p.ExtendedData[name] = kmlValue(v)
+		}
+	}
+	return p
+}
+
+// kmlValue returns the text of a property: strings as they are, other values as JSON.
+func kmlValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// FeatureCollection returns the placemarks of the folder and of its sub folders as GeoJSON features.
+//  data, err := json.Marshal(doc.FeatureCollection())
+func (f KMLFolder) FeatureCollection() FeatureCollection {
+	var c FeatureCollection
+	for _, p := range f.Placemarks {
+		c.Features = append(c.Features, p.Feature())
+	}
+	for _, folder := range f.Folders {
+		c.Features = append(c.Features, folder.FeatureCollection().Features...)
+	}
+	return c
+}
+
+// NewKMLFolder returns a folder with placemarks of the GeoJSON features, to write GeoJSON as KML.
+//  var c geo.FeatureCollection
+//  err := json.Unmarshal(data, &c)
+//  err = geo.WriteKML(file, geo.NewKMLFolder("Zones", c))
+func NewKMLFolder(name string, c FeatureCollection) *KMLFolder {
+	f := &KMLFolder{Name: name, Placemarks: make([]KMLPlacemark, len(c.Features))}
+	for i, feature := range c.Features {
+		f.Placemarks[i] = NewKMLPlacemark(feature)
+	}
+	return f
+}
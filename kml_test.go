@@ -0,0 +1,280 @@
+package geo
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testKML is a document exported from Google My Maps, with styles and a layer per folder.
+const testKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Delivery zones</name>
+    <description/>
+    <Style id="poly-000000-1200-77-normal">
+      <LineStyle><color>ff000000</color><width>1.2</width></LineStyle>
+    </Style>
+    <Folder>
+      <name>Zones</name>
+      <Placemark>
+        <name>Sukhumvit</name>
+        <description><![CDATA[Same day <b>delivery</b>]]></description>
+        <styleUrl>#poly-000000-1200-77-normal</styleUrl>
+        <ExtendedData>
+          <Data name="fee"><value>50</value></Data>
+          <Data name="driver"><value>Somchai</value></Data>
+        </ExtendedData>
+        <Polygon>
+          <outerBoundaryIs>
+            <LinearRing>
+              <tessellate>1</tessellate>
+              <coordinates>
+                100.55,13.73,0
+                100.62,13.73,0
+                100.62,13.78,0
+                100.55,13.78,0
+                100.55,13.73,0
+              </coordinates>
+            </LinearRing>
+          </outerBoundaryIs>
+          <innerBoundaryIs>
+            <LinearRing><coordinates>100.58,13.75 100.59,13.75 100.59,13.76 100.58,13.75</coordinates></LinearRing>
+          </innerBoundaryIs>
+        </Polygon>
+      </Placemark>
+      <Placemark id="silom">
+        <name>Silom</name>
+        <ExtendedData>
+          <SchemaData schemaUrl="#zones"><SimpleData name="fee">80</SimpleData></SchemaData>
+        </ExtendedData>
+        <MultiGeometry>
+          <Polygon><outerBoundaryIs><LinearRing><coordinates>100.52,13.72 100.54,13.72 100.54,13.74 100.52,13.72</coordinates></LinearRing></outerBoundaryIs></Polygon>
+          <Polygon><outerBoundaryIs><LinearRing><coordinates>100.50,13.70 100.51,13.70 100.51,13.71 100.50,13.70</coordinates></LinearRing></outerBoundaryIs></Polygon>
+        </MultiGeometry>
+      </Placemark>
+    </Folder>
+    <Folder>
+      <name>Depots</name>
+      <Placemark>
+        <name>Office</name>
+        <Point><coordinates>100.6068431,13.7665217,0</coordinates></Point>
+      </Placemark>
+      <Placemark>
+        <name>Route</name>
+        <LineString><coordinates>100.6068431,13.7665217 100.5197898,13.7199345</coordinates></LineString>
+      </Placemark>
+      <Placemark>
+        <name>Depot and access</name>
+        <MultiGeometry>
+          <Point><coordinates>100.5,13.7</coordinates></Point>
+          <LineString><coordinates>100.5,13.7 100.51,13.71</coordinates></LineString>
+        </MultiGeometry>
+      </Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+// testKMLFolder is the document of testKML.
+var testKMLFolder = &KMLFolder{
+	Name: "Delivery zones",
+	Folders: []KMLFolder{
+		{
+			Name: "Zones",
+			Placemarks: []KMLPlacemark{
+				{
+					Name:         "Sukhumvit",
+					Description:  "Same day <b>delivery</b>",
+					ExtendedData: map[string]string{"fee": "50", "driver": "Somchai"},
+					Geometry: Polygon{
+						Ring{{13.73, 100.55}, {13.73, 100.62}, {13.78, 100.62}, {13.78, 100.55}, {13.73, 100.55}},
+						Ring{{13.75, 100.58}, {13.75, 100.59}, {13.76, 100.59}, {13.75, 100.58}},
+					},
+				},
+				{
+					ID:           "silom",
+					Name:         "Silom",
+					ExtendedData: map[string]string{"fee": "80"},
+					Geometry: MultiPolygon{
+						{Ring{{13.72, 100.52}, {13.72, 100.54}, {13.74, 100.54}, {13.72, 100.52}}},
+						{Ring{{13.70, 100.50}, {13.70, 100.51}, {13.71, 100.51}, {13.70, 100.50}}},
+					},
+				},
+			},
+		},
+		{
+			Name: "Depots",
+			Placemarks: []KMLPlacemark{
+				{Name: "Office", Geometry: Point{13.7665217, 100.6068431}},
+				{Name: "Route", Geometry: LineString{{13.7665217, 100.6068431}, {13.7199345, 100.5197898}}},
+				{Name: "Depot and access", Geometry: GeometryCollection{Point{13.7, 100.5}, LineString{{13.7, 100.5}, {13.71, 100.51}}}},
+			},
+		},
+	},
+}
+
+func TestReadKML(t *testing.T) {
+	doc, err := ReadKML(strings.NewReader(testKML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc, testKMLFolder) {
+		t.Errorf("ReadKML() = %+v, want %+v", doc, testKMLFolder)
+	}
+
+	// Placemarks without document, rings as polygons, multi points and empty multi geometries
+	doc, err = ReadKML(strings.NewReader(`<kml xmlns="http://earth.google.com/kml/2.1">
+		<Placemark><name>Zone</name><LinearRing><coordinates>1,2 3,4 5,6 1,2</coordinates></LinearRing></Placemark>
+		<Placemark><MultiGeometry><Point><coordinates>1,2</coordinates></Point><Point><coordinates>3,4</coordinates></Point></MultiGeometry></Placemark>
+		<Placemark><MultiGeometry/></Placemark>
+	</kml>`))
+	want := &KMLFolder{Placemarks: []KMLPlacemark{
+		{Name: "Zone", Geometry: Polygon{Ring{{2, 1}, {4, 3}, {6, 5}, {2, 1}}}},
+		{Geometry: MultiPoint{{2, 1}, {4, 3}}},
+		{Geometry: GeometryCollection{}},
+	}}
+	if err != nil || !reflect.DeepEqual(doc, want) {
+		t.Errorf("ReadKML() = %+v, %v, want %+v", doc, err, want)
+	}
+
+	for _, doc := range []string{
+		"",
+		"<gpx></gpx>",
+		"<kml><Document>",
+		"<kml><Placemark><Point><coordinates>1,2 3,4</coordinates></Point></Placemark></kml>",
+		"<kml><Placemark><LineString><coordinates>1;2 3;4</coordinates></LineString></Placemark></kml>",
+		"<kml><Placemark><Polygon><outerBoundaryIs><LinearRing><coordinates>1,x</coordinates></LinearRing></outerBoundaryIs></Polygon></Placemark></kml>",
+	} {
+		if _, err := ReadKML(strings.NewReader(doc)); err == nil {
+			t.Errorf("ReadKML(%q) error = nil", doc)
+		}
+	}
+}
+
+func TestWriteKML(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteKML(&b, testKMLFolder); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ReadKML(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc, testKMLFolder) {
+		t.Errorf("ReadKML(WriteKML()) = %+v, want %+v", doc, testKMLFolder)
+	}
+
+	b.Reset()
+	zone := Polygon{Ring{{13.73, 100.55}, {13.73, 100.62}, {13.78, 100.62}}}
+	if err := WriteKML(&b, &KMLFolder{Name: "Zones", Placemarks: []KMLPlacemark{{ID: "z1", Name: "A & B", ExtendedData: map[string]string{"fee": "50"}, Geometry: zone}}}); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Zones</name>
+    <Placemark id="z1">
+      <name>A &amp; B</name>
+      <ExtendedData>
+        <Data name="fee">
+          <value>50</value>
+        </Data>
+      </ExtendedData>
+      <Polygon>
+        <outerBoundaryIs>
+          <LinearRing>
+            <coordinates>100.55,13.73 100.62,13.73 100.62,13.78 100.55,13.73</coordinates>
+          </LinearRing>
+        </outerBoundaryIs>
+      </Polygon>
+    </Placemark>
+  </Document>
+</kml>
+`
+	if b.String() != want {
+		t.Errorf("WriteKML() = %v, want %v", b.String(), want)
+	}
+
+	// Geometry types of other packages
+	if err := WriteKML(&b, &KMLFolder{Placemarks: []KMLPlacemark{{Geometry: struct{ Point }{}}}}); err != ErrInvalidKML {
+		t.Errorf("WriteKML(unknown geometry) error = %v", err)
+	}
+}
+
+func TestKMZ(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteKMZ(&b, testKMLFolder); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ReadKMZ(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil || !reflect.DeepEqual(doc, testKMLFolder) {
+		t.Errorf("ReadKMZ(WriteKMZ()) = %+v, %v", doc, err)
+	}
+
+	// doc.kml is read before other KML files
+	b.Reset()
+	z := zip.NewWriter(&b)
+	for _, name := range []string{"images/icon.png", "layers/other.kml", "doc.kml"} {
+		w, _ := z.Create(name)
+		w.Write([]byte("<kml><Document><name>" + name + "</name></Document></kml>"))
+	}
+	z.Close()
+	if doc, err := ReadKMZ(bytes.NewReader(b.Bytes()), int64(b.Len())); err != nil || doc.Name != "doc.kml" {
+		t.Errorf("ReadKMZ() = %+v, %v", doc, err)
+	}
+
+	if _, err := ReadKMZ(strings.NewReader(testKML), int64(len(testKML))); err != ErrInvalidKML {
+		t.Errorf("ReadKMZ(KML) error = %v", err)
+	}
+	b.Reset()
+	z = zip.NewWriter(&b)
+	z.Create("readme.txt")
+	z.Close()
+	if _, err := ReadKMZ(bytes.NewReader(b.Bytes()), int64(b.Len())); err != ErrInvalidKML {
+		t.Errorf("ReadKMZ(no KML) error = %v", err)
+	}
+}
+
+func TestKMLGeoJSON(t *testing.T) {
+	c := testKMLFolder.FeatureCollection()
+	if len(c.Features) != 5 {
+		t.Fatalf("FeatureCollection() = %v features", len(c.Features))
+	}
+	data, err := json.Marshal(c.Features[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"geometry":{"coordinates":[[[[100.52,13.72],[100.54,13.72],[100.54,13.74],[100.52,13.72]]],[[[100.5,13.7],[100.51,13.7],[100.51,13.71],[100.5,13.7]]]],"type":"MultiPolygon"},` +
+		`"id":"silom","properties":{"fee":"80","name":"Silom"},"type":"Feature"}`
+	if string(data) != want {
+		t.Errorf("Feature() = %s, want %s", data, want)
+	}
+
+	var features FeatureCollection
+	if err := json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":7,"geometry":{"type":"Point","coordinates":[100.5,13.7]},"properties":{"name":"Depot","open":true,"fee":50,"tags":["a"],"note":null}},
+		{"type":"Feature","geometry":null,"properties":{"name":1,"description":"No location"}}
+	]}`), &features); err != nil {
+		t.Fatal(err)
+	}
+	f := NewKMLFolder("Depots", features)
+	want2 := &KMLFolder{Name: "Depots", Placemarks: []KMLPlacemark{
+		{ID: "7", Name: "Depot", ExtendedData: map[string]string{"open": "true", "fee": "50", "tags": `["a"]`, "note": "null"}, Geometry: Point{13.7, 100.5}},
+		{Description: "No location", ExtendedData: map[string]string{"name": "1"}},
+	}}
+	if !reflect.DeepEqual(f, want2) {
+		t.Errorf("NewKMLFolder() = %+v, want %+v", f, want2)
+	}
+
+	// Placemarks survive GeoJSON, with their extended data
+	for _, folder := range testKMLFolder.Folders {
+		for _, p := range folder.Placemarks {
+			if got := NewKMLPlacemark(p.Feature()); !reflect.DeepEqual(got, p) {
+				t.Errorf("NewKMLPlacemark(Feature()) = %+v, want %+v", got, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	// plusCodeAlphabet holds the 20 digits of plus codes, without vowels and look-alike characters.
+	plusCodeAlphabet = "23456789CFGHJMPQRVWX"
+	// plusCodeSeparator follows the 8th digit of full codes.
+	plusCodeSeparator         = '+'
+	plusCodeSeparatorPosition = 8
+	// plusCodePadding replaces the digits of pairs left out of codes shorter than 8 digits.
+	plusCodePadding = '0'
+	// plusCodePairLength is the number of digits encoded as pairs of latitude and longitude digits.
+	plusCodePairLength = 10
+	// plusCodeMaxLength is the number of digits of the most precise codes, about 14 cm wide.
+	plusCodeMaxLength = 15
+	// plusCodeMinTrimmable is the minimum number of digits of codes that can be shortened.
+	plusCodeMinTrimmable = 6
+	// plusCodeLatPrecision and plusCodeLngPrecision are the number of cells of the most precise codes in one degree.
+	plusCodeLatPrecision = 25000000 // 20^3 * 5^5
+	plusCodeLngPrecision = 8192000  // 20^3 * 4^5
+)
+
+var (
+	// ErrInvalidPlusCode is returned for malformed plus codes, and full codes expected where short codes are given.
+	ErrInvalidPlusCode = errors.New("Invalid plus code")
+	// ErrPlusCodeNotShortenable is returned when shortening padded codes or codes of less than 6 digits.
+	ErrPlusCodeNotShortenable = errors.New("Plus code cannot be shortened")
+)
+
+// plusCodePairResolutions are the sizes in degrees of the cells of the pairs of digits.
+var plusCodePairResolutions = [...]float64{20, 1, 0.05, 0.0025, 0.000125}
+
+// PlusCodeEncode returns the Open Location Code of the point with length digits, from 2 to 15.
+// Lengths below 10 must be even, odd lengths are rounded up. Lengths of 0 or less give 10 digits,
+// about 14 meters wide, and lengths above 15 give 15 digits.
+// Details https://github.com/google/open-location-code/blob/main/Documentation/Specification/specification.md
+//  geo.PlusCodeEncode(geo.Point{Lat: 47.365590, Lon: 8.524997}, 10) // 8FVC9G8F+6X
+func PlusCodeEncode(p Point, length int) string {
+	switch {
+	case length <= 0:
+		length = plusCodePairLength
+	case length < plusCodePairLength && length%2 == 1:
+		length++
+	case length > plusCodeMaxLength:
+		length = plusCodeMaxLength
+	}
+
+	// Integers avoid the rounding errors of floating point divisions
+	lat := int64(math.Floor(p.Lat*plusCodeLatPrecision)) + 90*plusCodeLatPrecision
+	if lat < 0 {
+		lat = 0
+	} else if lat >= 180*plusCodeLatPrecision {
+		// Points at the north pole are in the top cells
+		lat = 180*plusCodeLatPrecision - 1
+	}
+	lng := int64(math.Floor(p.Lon*plusCodeLngPrecision)) % (360 * plusCodeLngPrecision)
+	lng += 180 * plusCodeLngPrecision
+	if lng < 0 {
+		lng += 360 * plusCodeLngPrecision
+	} else if lng >= 360*plusCodeLngPrecision {
+		lng -= 360 * plusCodeLngPrecision
+	}
+
+	digits := make([]byte, plusCodeMaxLength)
+	// Grid digits split the cell in 5 rows and 4 columns
+	for i := plusCodeMaxLength - 1; i >= plusCodePairLength; i-- {
+		digits[i] = plusCodeAlphabet[lat%5*4+lng%4]
+		lat /= 5
+		lng /= 4
+	}
+	for i := plusCodePairLength - 2; i >= 0; i -= 2 {
+		digits[i] = plusCodeAlphabet[lat%20]
+		digits[i+1] = plusCodeAlphabet[lng%20]
+		lat /= 20
+		lng /= 20
+	}
+
+	var b strings.Builder
+	if length < plusCodeSeparatorPosition {
+		b.Write(digits[:length])
+		b.WriteString(strings.Repeat(string(plusCodePadding), plusCodeSeparatorPosition-length))
+		b.WriteByte(plusCodeSeparator)
+		return b.String()
+	}
+	b.Write(digits[:plusCodeSeparatorPosition])
+	b.WriteByte(plusCodeSeparator)
+	b.Write(digits[plusCodeSeparatorPosition:length])
+	return b.String()
+}
+
+// PlusCodeDecode returns the point at the center of the area of a full plus code.
+func PlusCodeDecode(code string) (p Point, err error) {
+	box, err := PlusCodeBox(code)
+	if err != nil {
+		return
+	}
+	p = box.Center()
+	return
+}
+
+// PlusCodeBox returns the area of a full plus code.
+// Digits beyond the 15th are ignored.
+//  box, err := geo.PlusCodeBox("7P52QG42+GP")
+func PlusCodeBox(code string) (box BoundingBox, err error) {
+	if !PlusCodeFull(code) {
+		err = ErrInvalidPlusCode
+		return
+	}
+	digits := plusCodeDigits(code)
+	if len(digits) > plusCodeMaxLength {
+		digits = digits[:plusCodeMaxLength]
+	}
+
+	// Resolutions of the digits in cells of the most precise codes
+	var lat, lng int64
+	latRes, lngRes := int64(20*plusCodeLatPrecision), int64(20*plusCodeLngPrecision)
+	for i, d := range digits {
+		switch {
+		case i >= plusCodePairLength:
+			latRes /= 5
+			lngRes /= 4
+			lat += int64(d/4) * latRes
+			lng += int64(d%4) * lngRes
+		case i%2 == 0:
+			if i > 0 {
+				latRes /= 20
+				lngRes /= 20
+			}
+			lat += int64(d) * latRes
+		default:
+			lng += int64(d) * lngRes
+		}
+	}
+	box = BoundingBox{
+		MinLat: float64(lat)/plusCodeLatPrecision - 90,
+		MinLon: float64(lng)/plusCodeLngPrecision - 180,
+		MaxLat: float64(lat+latRes)/plusCodeLatPrecision - 90,
+		MaxLon: float64(lng+lngRes)/plusCodeLngPrecision - 180,
+	}
+	return
+}
+
+// plusCodeDigits returns the values of the digits of a valid code, without separator and padding.
+func plusCodeDigits(code string) []int {
+	digits := make([]int, 0, len(code))
+	for i := 0; i < len(code); i++ {
+		if d := strings.IndexByte(plusCodeAlphabet, toUpperASCII(code[i])); d >= 0 {
+			digits = append(digits, d)
+		}
+	}
+	return digits
+}
+
+// toUpperASCII returns the upper case of an ASCII lower case letter, other bytes are unchanged.
+func toUpperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// PlusCodeValid tells whether the code is a valid full or short plus code.
+// Letters can be lower case.
+func PlusCodeValid(code string) bool {
+	if len(code) < 2 {
+		return false
+	}
+	separator := strings.IndexByte(code, plusCodeSeparator)
+	// A single separator at an even position, at most after 8 digits
+	if separator < 0 || separator != strings.LastIndexByte(code, plusCodeSeparator) ||
+		separator > plusCodeSeparatorPosition || separator%2 == 1 {
+		return false
+	}
+	// A single digit after the separator is not allowed
+	if len(code)-separator-1 == 1 {
+		return false
+	}
+
+	if padding := strings.IndexByte(code, plusCodePadding); padding >= 0 {
+		// Padded codes are full codes without digits after the separator,
+		// with an even number of padding characters after at least 2 digits
+		if separator < plusCodeSeparatorPosition || padding == 0 || padding%2 == 1 || separator != len(code)-1 {
+			return false
+		}
+		for i := padding; i < separator; i++ {
+			if code[i] != plusCodePadding {
+				return false
+			}
+		}
+		code = code[:padding]
+	}
+
+	for i := 0; i < len(code); i++ {
+		if i != separator && strings.IndexByte(plusCodeAlphabet, toUpperASCII(code[i])) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PlusCodeShort tells whether the code is a valid short code, with less than 8 digits before the separator,
+// such as "CJ+2VX" that must be recovered with a reference point by PlusCodeRecover.
+func PlusCodeShort(code string) bool {
+	return PlusCodeValid(code) && strings.IndexByte(code, plusCodeSeparator) < plusCodeSeparatorPosition
+}
+
+// PlusCodeFull tells whether the code is a valid full code, that can be decoded without a reference point.
+func PlusCodeFull(code string) bool {
+	if !PlusCodeValid(code) || PlusCodeShort(code) {
+		return false
+	}
+	// The first digits must not be beyond latitude 90 or longitude 180
+	if strings.IndexByte(plusCodeAlphabet, toUpperASCII(code[0]))*20 >= 180 {
+		return false
+	}
+	return strings.IndexByte(plusCodeAlphabet, toUpperASCII(code[1]))*20 < 360
+}
+
+// PlusCodeShorten removes the first digits of a full code that can be recovered from the reference point,
+// a town or a place close to the area of the code.
+// Details https://github.com/google/open-location-code/wiki/Guidance-for-shortening-codes
+//  geo.PlusCodeShorten("9C3W9QCJ+2VX", geo.Point{Lat: 51.3701125, Lon: -1.217765625}) // +2VX
+func PlusCodeShorten(code string, ref Point) (string, error) {
+	if !PlusCodeFull(code) {
+		return "", ErrInvalidPlusCode
+	}
+	if strings.IndexByte(code, plusCodePadding) >= 0 {
+		return "", ErrPlusCodeNotShortenable
+	}
+	code = strings.ToUpper(code)
+	box, err := PlusCodeBox(code)
+	if err != nil {
+		return "", err
+	}
+	if len(plusCodeDigits(code)) < plusCodeMinTrimmable {
+		return "", ErrPlusCodeNotShortenable
+	}
+
+	center := box.Center()
+	lat := math.Max(-90, math.Min(90, ref.Lat))
+	distance := math.Max(math.Abs(center.Lat-lat), math.Abs(center.Lon-normalizeLongitude(ref.Lon)))
+	// Remove the pairs whose cells are much larger than the distance, so recovery finds the nearest cell
+	for i := len(plusCodePairResolutions) - 2; i >= 1; i-- {
+		if distance < plusCodePairResolutions[i]*0.3 {
+			return code[(i+1)*2:], nil
+		}
+	}
+	return code, nil
+}
+
+// PlusCodeRecover returns the full code of a short code, using the cell closest to the reference point.
+// Full codes are returned in upper case.
+//  geo.PlusCodeRecover("9G8F+6X", geo.Point{Lat: 47.4, Lon: 8.6}) // 8FVC9G8F+6X
+func PlusCodeRecover(short string, ref Point) (string, error) {
+	if !PlusCodeShort(short) {
+		if PlusCodeFull(short) {
+			return strings.ToUpper(short), nil
+		}
+		return "", ErrInvalidPlusCode
+	}
+	lat := math.Max(-90, math.Min(90, ref.Lat))
+	lon := normalizeLongitude(ref.Lon)
+
+	// Digits missing before the separator are taken from the code of the reference point
+	missing := plusCodeSeparatorPosition - strings.IndexByte(short, plusCodeSeparator)
+	resolution := math.Pow(20, float64(2-missing/2))
+	code := PlusCodeEncode(Point{Lat: lat, Lon: lon}, plusCodeMaxLength)[:missing] + short
+	box, err := PlusCodeBox(code)
+	if err != nil {
+		return "", err
+	}
+
+	// The nearest matching cell may be the neighbor of the cell of the reference point
+	center := box.Center()
+	if lat+resolution/2 < center.Lat && center.Lat-resolution >= -90 {
+		center.Lat -= resolution
+	} else if lat-resolution/2 > center.Lat && center.Lat+resolution <= 90 {
+		center.Lat += resolution
+	}
+	if lon+resolution/2 < center.Lon {
+		center.Lon -= resolution
+	} else if lon-resolution/2 > center.Lon {
+		center.Lon += resolution
+	}
+	return PlusCodeEncode(center, len(plusCodeDigits(code))), nil
+}
@@ -0,0 +1,251 @@
+package geo
+
+import (
+	"encoding/csv"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// readPlusCodeTests returns the records of a CSV file of testdata/pluscode, in the format of the test data
+// of the open-location-code project https://github.com/google/open-location-code/tree/main/test_data
+// so its files can replace ours.
+func readPlusCodeTests(t *testing.T, name string) [][]string {
+	f, err := os.Open(filepath.Join("testdata", "pluscode", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+// parsePlusCodeFloat returns the number of a CSV field of the plus code tests.
+func parsePlusCodeFloat(t *testing.T, s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestPlusCode_TestData(t *testing.T) {
+	// Latitude, longitude, [latitude and longitude as integers,] length, code
+	for _, r := range readPlusCodeTests(t, "encoding.csv") {
+		p := Point{parsePlusCodeFloat(t, r[0]), parsePlusCodeFloat(t, r[1])}
+		length, _ := strconv.Atoi(r[len(r)-2])
+		if got := PlusCodeEncode(p, length); got != r[len(r)-1] {
+			t.Errorf("PlusCodeEncode(%v, %v) = %v, want %v", p, length, got, r[len(r)-1])
+		}
+	}
+	// Code, length, box
+	for _, r := range readPlusCodeTests(t, "decoding.csv") {
+		want := BoundingBox{parsePlusCodeFloat(t, r[2]), parsePlusCodeFloat(t, r[3]), parsePlusCodeFloat(t, r[4]), parsePlusCodeFloat(t, r[5])}
+		got, err := PlusCodeBox(r[0])
+		if err != nil || math.Abs(got.MinLat-want.MinLat) > 1e-10 || math.Abs(got.MinLon-want.MinLon) > 1e-10 ||
+			math.Abs(got.MaxLat-want.MaxLat) > 1e-10 || math.Abs(got.MaxLon-want.MaxLon) > 1e-10 {
+			t.Errorf("PlusCodeBox(%q) = %v, %v, want %v", r[0], got, err, want)
+		}
+	}
+	// Code, valid, short, full
+	for _, r := range readPlusCodeTests(t, "validityTests.csv") {
+		valid, short, full := PlusCodeValid(r[0]), PlusCodeShort(r[0]), PlusCodeFull(r[0])
+		if strconv.FormatBool(valid) != r[1] || strconv.FormatBool(short) != r[2] || strconv.FormatBool(full) != r[3] {
+			t.Errorf("%q valid, short, full = %v, %v, %v, want %v", r[0], valid, short, full, r[1:])
+		}
+	}
+	// Full code, reference, short code, B to shorten and recover, S to shorten only, R to recover only
+	for _, r := range readPlusCodeTests(t, "shortCodeTests.csv") {
+		ref := Point{parsePlusCodeFloat(t, r[1]), parsePlusCodeFloat(t, r[2])}
+		if r[4] != "R" {
+			if got, err := PlusCodeShorten(r[0], ref); err != nil || got != r[3] {
+				t.Errorf("PlusCodeShorten(%q, %v) = %v, %v, want %v", r[0], ref, got, err, r[3])
+			}
+		}
+		if r[4] != "S" {
+			if got, err := PlusCodeRecover(r[3], ref); err != nil || got != r[0] {
+				t.Errorf("PlusCodeRecover(%q, %v) = %v, %v, want %v", r[3], ref, got, err, r[0])
+			}
+		}
+	}
+}
+
+func TestPlusCodeEncode(t *testing.T) {
+	tests := []struct {
+		p      Point
+		length int
+		want   string
+	}{
+		{Point{47.365590, 8.524997}, 10, "8FVC9G8F+6X"},
+		{Point{13.7563, 100.5018}, 10, "7P52QG42+GP"},
+		{Point{20.375, 2.775}, 6, "7FG49Q00+"},
+		{Point{20.3700625, 2.7821875}, 10, "7FG49QCJ+2V"},
+		{Point{20.3701125, 2.782234375}, 11, "7FG49QCJ+2VX"},
+		{Point{20.3701135, 2.78223535156}, 13, "7FG49QCJ+2VXGJ"},
+		{Point{47.0000625, 8.0000625}, 10, "8FVC2222+22"},
+		{Point{-41.2730625, 174.7859375}, 10, "4VCPPQGP+Q9"},
+		{Point{0.5, -179.5}, 4, "62G20000+"},
+		{Point{-89.5, -179.5}, 4, "22220000+"},
+		{Point{20.5, 2.5}, 4, "7FG40000+"},
+		{Point{-89.9999375, -179.9999375}, 10, "22222222+22"},
+		{Point{0.5, 179.5}, 4, "6VGX0000+"},
+		{Point{1, 1}, 11, "6FH32222+222"},
+		// Latitudes are clipped and longitudes normalized
+		{Point{90, 1}, 4, "CFX30000+"},
+		{Point{92, 1}, 4, "CFX30000+"},
+		{Point{90, 1}, 10, "CFX3X2X2+X2"},
+		{Point{1, 180}, 4, "62H20000+"},
+		{Point{1, 181}, 4, "62H30000+"},
+		{Point{1, -180}, 4, "62H20000+"},
+		// Lengths are rounded up to pairs, or clipped
+		{Point{20.375, 2.775}, 5, "7FG49Q00+"},
+		{Point{20.375, 2.775}, 1, "7F000000+"},
+		{Point{47.365590, 8.524997}, 0, "8FVC9G8F+6X"},
+		{Point{47.365590, 8.524997}, 20, "8FVC9G8F+6XQQ435"},
+	}
+	for _, tt := range tests {
+		if got := PlusCodeEncode(tt.p, tt.length); got != tt.want {
+			t.Errorf("PlusCodeEncode(%v, %v) = %v, want %v", tt.p, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestPlusCodeBox(t *testing.T) {
+	tests := []struct {
+		code string
+		want BoundingBox
+	}{
+		{"7FG49QCJ+2V", BoundingBox{20.37, 2.782125, 20.370125, 2.78225}},
+		{"7fg49qcj+2vx", BoundingBox{20.3701, 2.78221875, 20.370125, 2.78225}},
+		{"7FG49QCJ+2VXGJ", BoundingBox{20.370113, 2.782234375, 20.370114, 2.7822363281}},
+		{"7FG49Q00+", BoundingBox{20.35, 2.75, 20.4, 2.8}},
+		{"CFX30000+", BoundingBox{89, 1, 90, 2}},
+		{"62G20000+", BoundingBox{0, -180, 1, -179}},
+		{"22222222+22", BoundingBox{-90, -180, -89.999875, -179.999875}},
+		// Digits beyond 15 are ignored
+		{"8FVC9G8F+6X3FQ6QWC", BoundingBox{47.36551332, 8.5249202881, 47.36551336, 8.5249204102}},
+	}
+	for _, tt := range tests {
+		got, err := PlusCodeBox(tt.code)
+		if err != nil || math.Abs(got.MinLat-tt.want.MinLat) > 1e-8 || math.Abs(got.MinLon-tt.want.MinLon) > 1e-8 ||
+			math.Abs(got.MaxLat-tt.want.MaxLat) > 1e-8 || math.Abs(got.MaxLon-tt.want.MaxLon) > 1e-8 {
+			t.Errorf("PlusCodeBox(%q) = %v, %v, want %v", tt.code, got, err, tt.want)
+		}
+	}
+
+	for _, code := range []string{"9G8F+6X", "8FVC9G8F+6", "F2222222+", "8FVC9G8F"} {
+		if _, err := PlusCodeBox(code); err != ErrInvalidPlusCode {
+			t.Errorf("PlusCodeBox(%q) error = %v", code, err)
+		}
+	}
+
+	// Codes of random points decode to areas holding the points
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		p := Point{Lat: r.Float64()*180 - 90, Lon: r.Float64()*360 - 180}
+		code := PlusCodeEncode(p, 2+i%14)
+		box, err := PlusCodeBox(code)
+		if err != nil || p.Lat < box.MinLat-1e-9 || p.Lat > box.MaxLat+1e-9 || p.Lon < box.MinLon-1e-9 || p.Lon > box.MaxLon+1e-9 {
+			t.Fatalf("PlusCodeBox(%q) = %v, %v does not hold %v", code, box, err, p)
+		}
+	}
+	if p, err := PlusCodeDecode("8FVC9G8F+6X"); err != nil || math.Abs(p.Lat-47.3655625) > 1e-9 || math.Abs(p.Lon-8.5249375) > 1e-9 {
+		t.Errorf("PlusCodeDecode() = %v, %v", p, err)
+	}
+}
+
+func TestPlusCodeShorten(t *testing.T) {
+	tests := []struct {
+		code  string
+		ref   Point
+		short string
+	}{
+		{"9C3W9QCJ+2VX", Point{51.3701125, -1.217765625}, "+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3708675, -1.217765625}, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3693575, -1.217765625}, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3701125, -1.218520625}, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3701125, -1.217010625}, "CJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3852125, -1.217765625}, "9QCJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3550125, -1.217765625}, "9QCJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3701125, -1.232865625}, "9QCJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.3701125, -1.202665625}, "9QCJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{51.6, -1.2}, "9QCJ+2VX"},
+		{"9C3W9QCJ+2VX", Point{60, 10}, "9C3W9QCJ+2VX"},
+		{"8FJFW222+", Point{42.899, 9.012}, "22+"},
+		{"796RXG22+", Point{14.95125, -23.5001}, "22+"},
+		{"8fvc9g8f+6x", Point{47.4, 8.6}, "9G8F+6X"},
+		{"7P52QG42+GP", Point{13.75, 100.5}, "42+GP"},
+	}
+	for _, tt := range tests {
+		got, err := PlusCodeShorten(tt.code, tt.ref)
+		if err != nil || got != tt.short {
+			t.Errorf("PlusCodeShorten(%q, %v) = %v, %v, want %v", tt.code, tt.ref, got, err, tt.short)
+		}
+	}
+
+	if _, err := PlusCodeShorten("8FVC9G00+", Point{47.4, 8.6}); err != ErrPlusCodeNotShortenable {
+		t.Errorf("PlusCodeShorten(padded code) error = %v", err)
+	}
+	if _, err := PlusCodeShorten("9G8F+6X", Point{47.4, 8.6}); err != ErrInvalidPlusCode {
+		t.Errorf("PlusCodeShorten(short code) error = %v", err)
+	}
+}
+
+func TestPlusCodeRecover(t *testing.T) {
+	tests := []struct {
+		short string
+		ref   Point
+		want  string
+	}{
+		{"9G8F+6X", Point{47.4, 8.6}, "8FVC9G8F+6X"},
+		{"+2VX", Point{51.3701125, -1.217765625}, "9C3W9QCJ+2VX"},
+		{"CJ+2VX", Point{51.3708675, -1.217765625}, "9C3W9QCJ+2VX"},
+		{"9QCJ+2VX", Point{51.3852125, -1.217765625}, "9C3W9QCJ+2VX"},
+		{"22+", Point{42.899, 9.012}, "8FJFW222+"},
+		{"42+gp", Point{13.75, 100.5}, "7P52QG42+GP"},
+		// The nearest cell is across a cell boundary of the reference point
+		{"XXXX+XX", Point{-81.0, 0.0}, "2CCXXXXX+XX"},
+		{"2222+22", Point{-7.2, 139.9}, "6R522222+22"},
+		// Across the antimeridian
+		{"2222+22", Point{11.0, 179.9}, "72322222+22"},
+		{"XXXX+XX", Point{11.0, -179.9}, "7V2XXXXX+XX"},
+		// Not beyond the poles
+		{"2222+22", Point{89.6, 0.0}, "CFX22222+22"},
+		{"XXXX+XX", Point{-89.6, 0.0}, "2C2XXXXX+XX"},
+		// Full codes are returned
+		{"8fvc9g8f+6x", Point{0, 0}, "8FVC9G8F+6X"},
+	}
+	for _, tt := range tests {
+		got, err := PlusCodeRecover(tt.short, tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("PlusCodeRecover(%q, %v) = %v, %v, want %v", tt.short, tt.ref, got, err, tt.want)
+		}
+	}
+	if _, err := PlusCodeRecover("9G8F+6", Point{47.4, 8.6}); err != ErrInvalidPlusCode {
+		t.Errorf("PlusCodeRecover(invalid code) error = %v", err)
+	}
+
+	// Shortened codes of random points are recovered from nearby points
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		p := Point{Lat: r.Float64()*178 - 89, Lon: r.Float64()*360 - 180}
+		code := PlusCodeEncode(p, 10+i%6)
+		ref := p.Destination(r.Float64()*360, r.Float64()*10000)
+		short, err := PlusCodeShorten(code, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := PlusCodeRecover(short, ref); err != nil || got != code {
+			t.Fatalf("PlusCodeRecover(%q, %v) = %v, %v, want %v", short, ref, got, err, code)
+		}
+	}
+}
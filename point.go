@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// Point is a geographic location with latitude and longitude in decimal degrees.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+var (
+	// ErrInvalidLatitude is returned when a latitude is not in the range [-90, 90].
+	ErrInvalidLatitude = errors.New("Invalid latitude")
+	// ErrInvalidLongitude is returned when a longitude is not in the range [-180, 180].
+	ErrInvalidLongitude = errors.New("Invalid longitude")
+	// ErrNotFound is returned when a geocoding service finds no result.
+	ErrNotFound = errors.New("Not found")
+)
+
+// NewPoint returns a point after checking latitude and longitude are in valid ranges.
+//  p, err := geo.NewPoint(13.7665217, 100.6068431)
+func NewPoint(lat, lon float64) (p Point, err error) {
+	p = Point{Lat: lat, Lon: lon}
+	err = p.Validate()
+	return
+}
+
+// Validate checks the latitude is in the range [-90, 90] and the longitude in the range [-180, 180].
+func (p Point) Validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return ErrInvalidLatitude
+	}
+	if math.IsNaN(p.Lon) || p.Lon < -180 || p.Lon > 180 {
+		return ErrInvalidLongitude
+	}
+	return nil
+}
+
+// Valid returns true if the point has valid latitude and longitude.
+func (p Point) Valid() bool {
+	return p.Validate() == nil
+}
+
+// Normalize returns the same location with latitude in [-90, 90] and longitude in (-180, 180].
+// A latitude going beyond a pole comes back on the other side of the globe,
+// so the point (95, 10) becomes (85, -170).
+func (p Point) Normalize() Point {
+	lat := math.Mod(p.Lat, 360)
+	lon := p.Lon
+	if lat > 180 {
+		lat -= 360
+	} else if lat <= -180 {
+		lat += 360
+	}
+	if lat > 90 {
+		lat = 180 - lat
+		lon += 180
+	} else if lat < -90 {
+		lat = -180 - lat
+		lon += 180
+	}
+	return Point{Lat: lat, Lon: normalizeLongitude(lon)}
+}
+
+// Equal returns true if both points are less than tolerance meters apart.
+// Longitudes 180 and -180 are the same meridian and all longitudes are equal at the poles.
+//  p.Equal(q, 1)
+func (p Point) Equal(q Point, tolerance float64) bool {
+	return p.Distance(q) <= tolerance
+}
+
+// Distance returns the distance in meters between two points.
+// See Distance function for details.
+func (p Point) Distance(q Point) float64 {
+	return Distance(p.Lat, p.Lon, q.Lat, q.Lon)
+}
+
+// Bearing returns the initial bearing in degrees from p to q.
+// See Bearing function for details.
+func (p Point) Bearing(q Point) float64 {
+	return Bearing(p.Lat, p.Lon, q.Lat, q.Lon)
+}
+
+// Destination returns the point reached when travelling distance meters from p with the given bearing.
+// See Destination function for details.
+func (p Point) Destination(bearing, distance float64) Point {
+	lat, lon := Destination(p.Lat, p.Lon, bearing, distance)
+	return Point{Lat: lat, Lon: lon}
+}
+
+// Reverse returns location name of the point from openstreetmap API.
+// See Reverse function for details.
+func (p Point) Reverse() (Nominatim, error) {
+	return Reverse(p.Lat, p.Lon)
+}
+
+// GeoLocatePoint returns the point of an address.
+// Unlike GeoLocate, it returns ErrNotFound when the address is unknown.
+//  p, err := GeoLocatePoint(geo.Address{City:"Bangkok",Road:"Latprao 94, Town in Town",Postcode:"10310"})
+func GeoLocatePoint(address Address) (p Point, err error) {
+	p.Lat, p.Lon, err = geoLocate(address)
+	return
+}
+
+// Point returns the location of the place.
+func (p Place) Point() Point {
+	return Point{Lat: p.Lat, Lon: p.Long}
+}
+
+// Point returns the location of the Google place.
+func (g GooglePlace) Point() Point {
+	return Point{Lat: g.Geometry.Location.Lat, Lon: g.Geometry.Location.Lng}
+}
+
+// Point returns the location found by ipapi.
+func (ip IPAPI) Point() Point {
+	return Point{Lat: ip.Latitude, Lon: ip.Longitude}
+}
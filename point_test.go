@@ -0,0 +1,86 @@
+package geo
+
+import "testing"
+
+func TestNewPoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		lat     float64
+		lon     float64
+		wantErr error
+	}{
+		{name: "Office", lat: 13.7665217, lon: 100.6068431},
+		{name: "North pole", lat: 90, lon: 0},
+		{name: "Antimeridian", lat: 0, lon: -180},
+		{name: "Latitude too big", lat: 90.1, lon: 0, wantErr: ErrInvalidLatitude},
+		{name: "Longitude too small", lat: 0, lon: -180.5, wantErr: ErrInvalidLongitude},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPoint(tt.lat, tt.lon); err != tt.wantErr {
+				t.Errorf("NewPoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPoint_Normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Point
+		want Point
+	}{
+		{name: "Valid", p: Point{13.5, 100.5}, want: Point{13.5, 100.5}},
+		{name: "Wrap east", p: Point{10, 190}, want: Point{10, -170}},
+		{name: "Wrap west", p: Point{10, -540}, want: Point{10, 180}},
+		{name: "Over north pole", p: Point{95, 10}, want: Point{85, -170}},
+		{name: "Over south pole", p: Point{-100, -170}, want: Point{-80, 10}},
+		{name: "Full turn", p: Point{370, 720}, want: Point{10, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.Normalize()
+			if !got.Valid() || !got.Equal(tt.want, 1e-6) {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoint_Equal(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         Point
+		q         Point
+		tolerance float64
+		want      bool
+	}{
+		{name: "Same", p: Point{13.5, 100.5}, q: Point{13.5, 100.5}, tolerance: 0, want: true},
+		{name: "Antimeridian", p: Point{10, 180}, q: Point{10, -180}, tolerance: 1e-6, want: true},
+		{name: "Pole", p: Point{90, 10}, q: Point{90, -50}, tolerance: 1e-6, want: true},
+		{name: "Close", p: Point{13.5, 100.5}, q: Point{13.50001, 100.5}, tolerance: 2, want: true},
+		{name: "Far", p: Point{13.5, 100.5}, q: Point{13.6, 100.5}, tolerance: 2, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Equal(tt.q, tt.tolerance); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoint_Conversions(t *testing.T) {
+	want := Point{13.7665217, 100.6068431}
+	if got := (Place{Lat: want.Lat, Long: want.Lon}).Point(); got != want {
+		t.Errorf("Place.Point() = %v, want %v", got, want)
+	}
+	var g GooglePlace
+	g.Geometry.Location.Lat, g.Geometry.Location.Lng = want.Lat, want.Lon
+	if got := g.Point(); got != want {
+		t.Errorf("GooglePlace.Point() = %v, want %v", got, want)
+	}
+	if got := (IPAPI{Latitude: want.Lat, Longitude: want.Lon}).Point(); got != want {
+		t.Errorf("IPAPI.Point() = %v, want %v", got, want)
+	}
+}
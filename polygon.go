@@ -0,0 +1,252 @@
+package geo
+
+import "math"
+
+type (
+	// Ring is a closed line. Closing the ring by repeating the first point at the end is optional.
+	Ring []Point
+
+	// Polygon is an area delimited by an outer ring (first ring) and optional holes (next rings).
+	Polygon []Ring
+
+	// MultiPolygon is a set of polygons, such as a country with islands.
+	MultiPolygon []Polygon
+)
+
+// epsilon is the tolerance in degrees used to decide a point is on an edge (about 0.1 mm)
+const epsilon = 1e-9
+
+// Contains returns true if the point is inside the polygon or on its border.
+// A point inside a hole is not in the polygon, but a point on the border of a hole is.
+// Edges are straight lines in latitude and longitude, which is accurate for
+// small polygons like delivery zones. Polygons can cross the antimeridian but must not contain a pole.
+//  zone.Contains(geo.Point{Lat: 13.7665217, Lon: 100.6068431})
+func (p Polygon) Contains(pt Point) bool {
+	if len(p) == 0 || p[0].locate(pt) < 0 {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.locate(pt) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Area returns the area of the polygon in square meters, holes excluded.
+// The area is computed on the sphere used by Distance, with edges following great circles.
+func (p Polygon) Area() float64 {
+	if len(p) == 0 {
+		return 0
+	}
+	area := p[0].Area()
+	for _, hole := range p[1:] {
+		area -= hole.Area()
+	}
+	return area
+}
+
+// Perimeter returns the length in meters of all rings of the polygon, holes included.
+func (p Polygon) Perimeter() (perimeter float64) {
+	for _, r := range p {
+		perimeter += r.Perimeter()
+	}
+	return
+}
+
+// Centroid returns the center of mass of the polygon on the sphere.
+// The centroid of a concave polygon can be outside of the polygon.
+//  address, err := zone.Centroid().Reverse()
+func (p Polygon) Centroid() Point {
+	return vectorToPoint(p.centroidVector())
+}
+
+// Contains returns true if the point is in one of the polygons.
+func (m MultiPolygon) Contains(pt Point) bool {
+	for _, p := range m {
+		if p.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Area returns the total area of the polygons in square meters.
+func (m MultiPolygon) Area() (area float64) {
+	for _, p := range m {
+		area += p.Area()
+	}
+	return
+}
+
+// Perimeter returns the total length in meters of the rings of all polygons.
+func (m MultiPolygon) Perimeter() (perimeter float64) {
+	for _, p := range m {
+		perimeter += p.Perimeter()
+	}
+	return
+}
+
+// Centroid returns the center of mass of all polygons on the sphere.
+func (m MultiPolygon) Centroid() Point {
+	var v vector
+	for _, p := range m {
+		v = v.add(p.centroidVector())
+	}
+	return vectorToPoint(v)
+}
+
+// Area returns the area in square meters enclosed by the ring on the sphere used by Distance.
+func (r Ring) Area() float64 {
+	return math.Abs(r.signedArea())
+}
+
+// Perimeter returns the length in meters of the ring, closing edge included.
+func (r Ring) Perimeter() (perimeter float64) {
+	n := r.size()
+	for i := 0; i < n; i++ {
+		perimeter += r[i].Distance(r[(i+1)%n])
+	}
+	return
+}
+
+// IsClockwise returns true if points of the ring are in clockwise order.
+func (r Ring) IsClockwise() bool {
+	return r.signedArea() < 0
+}
+
+// size returns the number of distinct points of the ring, ignoring the closing point.
+func (r Ring) size() int {
+	n := len(r)
+	if n > 1 && r[0] == r[n-1] {
+		n--
+	}
+	return n
+}
+
+// signedArea returns the area of the ring, positive if the ring is counterclockwise.
+// Each edge and the north pole make a spherical triangle, the area is the sum of their spherical excess.
+func (r Ring) signedArea() float64 {
+	n := r.size()
+	if n < 3 {
+		return 0
+	}
+	var excess float64
+	for i := 0; i < n; i++ {
+		p1, p2 := r[i], r[(i+1)%n]
+		t1 := math.Tan(toRadians(p1.Lat) / 2)
+		t2 := math.Tan(toRadians(p2.Lat) / 2)
+		deltaLambda := toRadians(angleDiff(p1.Lon, p2.Lon))
+		excess += 2 * math.Atan2(math.Tan(deltaLambda/2)*(t1+t2), 1+t1*t2)
+	}
+	return -excess * EarthRadius * EarthRadius
+}
+
+// locate returns 1 if the point is inside the ring, 0 if it is on the border and -1 if it is outside.
+// Longitudes are unwrapped around the point so rings can cross the antimeridian.
+func (r Ring) locate(pt Point) int {
+	n := r.size()
+	if n == 0 {
+		return -1
+	}
+	inside := false
+	x := func(p Point) float64 { return angleDiff(pt.Lon, p.Lon) }
+	for i := 0; i < n; i++ {
+		a, b := r[i], r[(i+1)%n]
+		ax, ay := x(a), a.Lat-pt.Lat
+		bx, by := x(b), b.Lat-pt.Lat
+
+		// Is the point on the edge?
+		cross := ax*by - ay*bx
+		if math.Abs(cross) <= epsilon*math.Max(1, math.Hypot(bx-ax, by-ay)) &&
+			math.Min(ax, bx) <= epsilon && math.Max(ax, bx) >= -epsilon &&
+			math.Min(ay, by) <= epsilon && math.Max(ay, by) >= -epsilon {
+			return 0
+		}
+
+		// Does a ray going east from the point cross the edge?
+		if (ay > 0) != (by > 0) {
+			if xCross := ax + (0-ay)*(bx-ax)/(by-ay); xCross > 0 {
+				inside = !inside
+			}
+		}
+	}
+	if inside {
+		return 1
+	}
+	return -1
+}
+
+// centroidVector returns the integral of the position vector over the polygon surface.
+func (p Polygon) centroidVector() (v vector) {
+	for i, r := range p {
+		rv := r.centroidVector()
+		if (i == 0) == r.IsClockwise() {
+			// outer ring must be counterclockwise and holes clockwise
+			rv = rv.scale(-1)
+		}
+		v = v.add(rv)
+	}
+	return
+}
+
+// centroidVector returns the integral of the position vector over the surface enclosed
+// by a counterclockwise ring, on the unit sphere: half the sum of the edge normals weighted by edge lengths.
+func (r Ring) centroidVector() (v vector) {
+	n := r.size()
+	if n < 3 {
+		return
+	}
+	for i := 0; i < n; i++ {
+		a := pointToVector(r[i])
+		b := pointToVector(r[(i+1)%n])
+		normal := a.cross(b)
+		norm := normal.norm()
+		if norm == 0 {
+			continue
+		}
+		angle := math.Atan2(norm, a.dot(b))
+		v = v.add(normal.scale(angle / norm / 2))
+	}
+	return
+}
+
+// vector is a point of the 3D space, we use it for points of the unit sphere.
+type vector struct {
+	X, Y, Z float64
+}
+
+// pointToVector returns the position on the unit sphere of the point.
+func pointToVector(p Point) vector {
+	sinLat, cosLat := math.Sincos(toRadians(p.Lat))
+	sinLon, cosLon := math.Sincos(toRadians(p.Lon))
+	return vector{X: cosLat * cosLon, Y: cosLat * sinLon, Z: sinLat}
+}
+
+// vectorToPoint returns the point in the direction of the vector.
+func vectorToPoint(v vector) Point {
+	return Point{
+		Lat: toDegrees(math.Atan2(v.Z, math.Hypot(v.X, v.Y))),
+		Lon: toDegrees(math.Atan2(v.Y, v.X)),
+	}
+}
+
+func (v vector) add(w vector) vector {
+	return vector{v.X + w.X, v.Y + w.Y, v.Z + w.Z}
+}
+
+func (v vector) scale(f float64) vector {
+	return vector{v.X * f, v.Y * f, v.Z * f}
+}
+
+func (v vector) dot(w vector) float64 {
+	return v.X*w.X + v.Y*w.Y + v.Z*w.Z
+}
+
+func (v vector) cross(w vector) vector {
+	return vector{v.Y*w.Z - v.Z*w.Y, v.Z*w.X - v.X*w.Z, v.X*w.Y - v.Y*w.X}
+}
+
+func (v vector) norm() float64 {
+	return math.Sqrt(v.dot(v))
+}
@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// square returns a counterclockwise square polygon ring.
+func square(minLat, minLon, maxLat, maxLon float64) Ring {
+	return Ring{{minLat, minLon}, {minLat, maxLon}, {maxLat, maxLon}, {maxLat, minLon}, {minLat, minLon}}
+}
+
+func TestPolygon_Contains(t *testing.T) {
+	zone := Polygon{square(13, 100, 14, 101), square(13.4, 100.4, 13.6, 100.6)}
+	fiji := Polygon{Ring{{-16, 179}, {-16, -179}, {-18, -179}, {-18, 179}}}
+	tests := []struct {
+		name    string
+		polygon Polygon
+		p       Point
+		want    bool
+	}{
+		{name: "Inside", polygon: zone, p: Point{13.2, 100.2}, want: true},
+		{name: "Outside", polygon: zone, p: Point{14.2, 100.2}, want: false},
+		{name: "In hole", polygon: zone, p: Point{13.5, 100.5}, want: false},
+		{name: "Vertex", polygon: zone, p: Point{13, 100}, want: true},
+		{name: "Edge", polygon: zone, p: Point{13, 100.3}, want: true},
+		{name: "Vertical edge", polygon: zone, p: Point{13.7, 101}, want: true},
+		{name: "Hole edge", polygon: zone, p: Point{13.5, 100.4}, want: true},
+		{name: "Ray through vertex", polygon: Polygon{Ring{{0, 1}, {1, 2}, {2, 1}, {1, 0}}}, p: Point{1, 0.5}, want: true},
+		{name: "Ray through vertex outside", polygon: Polygon{Ring{{0, 1}, {1, 2}, {2, 1}, {1, 0}}}, p: Point{1, -0.5}, want: false},
+		{name: "Across antimeridian east", polygon: fiji, p: Point{-17, 179.5}, want: true},
+		{name: "Across antimeridian west", polygon: fiji, p: Point{-17, -179.5}, want: true},
+		{name: "Antimeridian", polygon: fiji, p: Point{-17, 180}, want: true},
+		{name: "Outside antimeridian", polygon: fiji, p: Point{-17, 178.5}, want: false},
+		{name: "Empty", polygon: Polygon{}, p: Point{0, 0}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.polygon.Contains(tt.p); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolygon_Area(t *testing.T) {
+	octant := Polygon{Ring{{0, 0}, {0, 90}, {90, 0}}}
+	if got, want := octant.Area(), math.Pi/2*EarthRadius*EarthRadius; math.Abs(got-want)/want > 1e-12 {
+		t.Errorf("Area() = %v, want %v", got, want)
+	}
+	if got, want := octant.Perimeter(), 3*math.Pi/2*EarthRadius; math.Abs(got-want) > 1e-6 {
+		t.Errorf("Perimeter() = %v, want %v", got, want)
+	}
+
+	// Clockwise ring has the same area
+	clockwise := Polygon{Ring{{0, 0}, {90, 0}, {0, 90}}}
+	if !clockwise[0].IsClockwise() || octant[0].IsClockwise() {
+		t.Errorf("IsClockwise() is wrong")
+	}
+	if clockwise.Area() != octant.Area() {
+		t.Errorf("Area() = %v, want %v", clockwise.Area(), octant.Area())
+	}
+
+	// Small box area is close to the area between parallels
+	box := Polygon{square(13, 100, 14, 101), square(13.4, 100.4, 13.6, 100.6)}
+	want := EarthRadius * EarthRadius * toRadians(1) * (math.Sin(toRadians(14)) - math.Sin(toRadians(13)))
+	want -= EarthRadius * EarthRadius * toRadians(0.2) * (math.Sin(toRadians(13.6)) - math.Sin(toRadians(13.4)))
+	if got := box.Area(); math.Abs(got-want)/want > 1e-3 {
+		t.Errorf("Area() = %v, want %v", got, want)
+	}
+	multi := MultiPolygon{box, octant}
+	if got := multi.Area(); got != box.Area()+octant.Area() {
+		t.Errorf("MultiPolygon.Area() = %v", got)
+	}
+}
+
+func TestPolygon_Centroid(t *testing.T) {
+	tests := []struct {
+		name    string
+		polygon Polygon
+		want    Point
+	}{
+		{name: "Square", polygon: Polygon{square(-1, 99, 1, 101)}, want: Point{0, 100}},
+		{name: "Octant", polygon: Polygon{Ring{{0, 0}, {0, 90}, {90, 0}}}, want: Point{toDegrees(math.Atan(1 / math.Sqrt2)), 45}},
+		{name: "Clockwise", polygon: Polygon{Ring{{0, 0}, {90, 0}, {0, 90}}}, want: Point{toDegrees(math.Atan(1 / math.Sqrt2)), 45}},
+		{name: "Antimeridian", polygon: Polygon{square(-1, 179, 1, -179)}, want: Point{0, 180}},
+		{name: "Hole", polygon: Polygon{square(-1, 99, 1, 101), square(-1, 99, 1, 100)}, want: Point{0, 100.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.polygon.Centroid(); !got.Equal(tt.want, 10) {
+				t.Errorf("Centroid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	multi := MultiPolygon{Polygon{square(-1, 99, 1, 100)}, Polygon{square(-1, 100, 1, 101)}}
+	if got := multi.Centroid(); !got.Equal(Point{0, 100}, 1e-3) {
+		t.Errorf("MultiPolygon.Centroid() = %v", got)
+	}
+}
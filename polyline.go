@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// Common precisions of encoded polylines.
+// Google uses 5 decimals, Mapbox and OSRM can return polylines with 6 decimals.
+const (
+	PolylinePrecision5 = 5
+	PolylinePrecision6 = 6
+)
+
+// ErrInvalidPolyline is returned when an encoded polyline is malformed.
+var ErrInvalidPolyline = errors.New("Invalid polyline")
+
+// PolylineDecoder reads points one by one from an encoded polyline.
+// It is useful for very long polylines that we do not want to decode in memory at once.
+type PolylineDecoder struct {
+	r      io.ByteReader
+	factor float64
+	lat    int64
+	lon    int64
+}
+
+// EncodePolyline encodes points with the encoded polyline algorithm.
+// precision is the number of decimals kept: 5 for Google, 6 for Mapbox or OSRM.
+// Details https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+//  geo.EncodePolyline([]geo.Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}, geo.PolylinePrecision5)
+func EncodePolyline(points []Point, precision int) string {
+	factor := math.Pow10(precision)
+	var b strings.Builder
+	var prevLat, prevLon int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * factor))
+		lon := int64(math.Round(p.Lon * factor))
+		encodePolylineValue(&b, lat-prevLat)
+		encodePolylineValue(&b, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+	return b.String()
+}
+
+// DecodePolyline decodes an encoded polyline with the given precision.
+//  points, err := geo.DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@", geo.PolylinePrecision5)
+func DecodePolyline(polyline string, precision int) (points []Point, err error) {
+	d := NewPolylineDecoder(strings.NewReader(polyline), precision)
+	for {
+		var p Point
+		p, err = d.Next()
+		if err == io.EOF {
+			return points, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+}
+
+// NewPolylineDecoder returns a decoder reading an encoded polyline with the given precision from r.
+//  d := geo.NewPolylineDecoder(file, geo.PolylinePrecision6)
+//  for p, err := d.Next(); err == nil; p, err = d.Next() {
+//      fmt.Println(p)
+//  }
+func NewPolylineDecoder(r io.Reader, precision int) *PolylineDecoder {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &PolylineDecoder{r: br, factor: math.Pow10(precision)}
+}
+
+// Next returns the next point of the polyline.
+// It returns io.EOF when there are no more points and ErrInvalidPolyline if the polyline is malformed.
+func (d *PolylineDecoder) Next() (p Point, err error) {
+	dlat, err := d.readValue()
+	if err != nil {
+		return
+	}
+	dlon, err := d.readValue()
+	if err == io.EOF {
+		// a latitude without longitude
+		err = ErrInvalidPolyline
+	}
+	if err != nil {
+		return
+	}
+	d.lat += dlat
+	d.lon += dlon
+	p.Lat = float64(d.lat) / d.factor
+	p.Lon = float64(d.lon) / d.factor
+	return
+}
+
+// readValue reads one encoded value.
+// It returns io.EOF if there is nothing more to read.
+func (d *PolylineDecoder) readValue() (v int64, err error) {
+	var result uint64
+	var shift uint
+	for {
+		var c byte
+		c, err = d.r.ReadByte()
+		if err == io.EOF && shift > 0 {
+			// value is truncated
+			err = ErrInvalidPolyline
+		}
+		if err != nil {
+			return
+		}
+		if c < 63 || c > 127 || shift > 60 {
+			err = ErrInvalidPolyline
+			return
+		}
+		chunk := uint64(c - 63)
+		result |= (chunk & 0x1f) << shift
+		shift += 5
+		if chunk < 0x20 {
+			break
+		}
+	}
+	if result&1 != 0 {
+		return ^int64(result >> 1), nil
+	}
+	return int64(result >> 1), nil
+}
+
+// PolylineLength returns the length in meters of an encoded polyline, computed with Distance.
+// The polyline is decoded as a stream, so it works with very long polylines.
+func PolylineLength(r io.Reader, precision int) (length float64, err error) {
+	d := NewPolylineDecoder(r, precision)
+	prev, err := d.Next()
+	for err == nil {
+		var p Point
+		if p, err = d.Next(); err == nil {
+			length += prev.Distance(p)
+			prev = p
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return
+}
+
+// PathLength returns the length in meters of the path joining points, computed with Distance.
+func PathLength(points []Point) (length float64) {
+	for i := 1; i < len(points); i++ {
+		length += points[i-1].Distance(points[i])
+	}
+	return
+}
+
+// encodePolylineValue writes one value of the encoded polyline.
+func encodePolylineValue(b *strings.Builder, v int64) {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		b.WriteByte(byte(0x20|(u&0x1f)) + 63)
+		u >>= 5
+	}
+	b.WriteByte(byte(u) + 63)
+}
@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncodePolyline(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    []Point
+		precision int
+		want      string
+	}{
+		{
+			name:      "Google example",
+			points:    []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+			precision: PolylinePrecision5,
+			want:      "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+		},
+		{
+			name:      "Google example precision 6",
+			points:    []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}},
+			precision: PolylinePrecision6,
+			want:      "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI",
+		},
+		{
+			name:      "Empty",
+			precision: PolylinePrecision5,
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodePolyline(tt.points, tt.precision); got != tt.want {
+				t.Errorf("EncodePolyline() = %v, want %v", got, tt.want)
+			}
+			got, err := DecodePolyline(tt.want, tt.precision)
+			if err != nil {
+				t.Fatalf("DecodePolyline() error = %v", err)
+			}
+			if len(got) != len(tt.points) {
+				t.Fatalf("DecodePolyline() = %v, want %v", got, tt.points)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.points[i], 0.01) {
+					t.Errorf("DecodePolyline() = %v, want %v", got, tt.points)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodePolyline_Invalid(t *testing.T) {
+	for _, polyline := range []string{"_p~iF", "_p~iF~ps|", "_p~iF ~ps|U", "\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f"} {
+		if _, err := DecodePolyline(polyline, PolylinePrecision5); err != ErrInvalidPolyline {
+			t.Errorf("DecodePolyline(%q) error = %v, want %v", polyline, err, ErrInvalidPolyline)
+		}
+	}
+}
+
+func TestPolyline_RoundTrip(t *testing.T) {
+	points := Interpolate(13.7665217, 100.6068431, -33.8688, 151.2093, 1000)
+	path := make([]Point, len(points))
+	for i, p := range points {
+		path[i] = Point{Lat: math.Round(p[0]*1e6) / 1e6, Lon: math.Round(p[1]*1e6) / 1e6}
+	}
+	path = append(path, Point{-89.999999, -179.999999}, Point{89.999999, 180})
+
+	for _, precision := range []int{PolylinePrecision5, PolylinePrecision6} {
+		encoded := EncodePolyline(path, precision)
+		decoded, err := DecodePolyline(encoded, precision)
+		if err != nil {
+			t.Fatalf("DecodePolyline() error = %v", err)
+		}
+		tolerance := math.Pow10(-precision)/2 + 1e-9
+		for i := range path {
+			if math.Abs(decoded[i].Lat-path[i].Lat) > tolerance || math.Abs(decoded[i].Lon-path[i].Lon) > tolerance {
+				t.Fatalf("precision %v point %v = %v, want %v", precision, i, decoded[i], path[i])
+			}
+		}
+		if encoded != EncodePolyline(decoded, precision) {
+			t.Errorf("precision %v: encoding is not stable", precision)
+		}
+
+		length, err := PolylineLength(strings.NewReader(encoded), precision)
+		if err != nil {
+			t.Fatalf("PolylineLength() error = %v", err)
+		}
+		if want := PathLength(decoded); math.Abs(length-want) > 1e-6 {
+			t.Errorf("PolylineLength() = %v, want %v", length, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package geo
+
+import (
+	"container/heap"
+	"math"
+)
+
+type (
+	// vwPoint is a point of the line being simplified with Visvalingam-Whyatt algorithm.
+	vwPoint struct {
+		index      int     // index in the line
+		area       float64 // effective area of the triangle made with the previous and next points
+		prev, next *vwPoint
+		heapIndex  int
+	}
+
+	// vwHeap is a priority queue of points, the point with the smallest area first.
+	vwHeap []*vwPoint
+)
+
+// SimplifyDouglasPeucker simplifies the line with Douglas-Peucker algorithm:
+// points closer than tolerance meters to the simplified line are removed. First and last points are kept.
+// It also returns the maximum distance in meters between a removed point and the simplified line.
+// Details https://en.wikipedia.org/wiki/Ramer%E2%80%93Douglas%E2%80%93Peucker_algorithm
+//  simple, deviation := geo.SimplifyDouglasPeucker(trace, 10)
+func SimplifyDouglasPeucker(l LineString, tolerance float64) (simplified LineString, deviation float64) {
+	if len(l) < 3 {
+		return append(LineString{}, l...), 0
+	}
+	keep := make([]bool, len(l))
+	keep[0], keep[len(l)-1] = true, true
+
+	// We use a stack instead of recursion, as traces can have a lot of points
+	stack := [][2]int{{0, len(l) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		var max float64
+		index := -1
+		for i := first + 1; i < last; i++ {
+			closest, _ := ClosestPointOnSegment(l[i], l[first], l[last])
+			if d := l[i].Distance(closest); d > max {
+				max, index = d, i
+			}
+		}
+		if index >= 0 && max > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+	return keptPoints(l, keep)
+}
+
+// SimplifyVisvalingam simplifies the line with Visvalingam-Whyatt algorithm:
+// the point making the smallest triangle with its neighbors is removed until all triangles are bigger
+// than a square of tolerance meters side. First and last points are kept.
+// It also returns the maximum distance in meters between a removed point and the simplified line.
+// Visvalingam-Whyatt gives smoother results than Douglas-Peucker for natural features.
+// Details https://en.wikipedia.org/wiki/Visvalingam%E2%80%93Whyatt_algorithm
+//  simple, deviation := geo.SimplifyVisvalingam(trace, 10)
+func SimplifyVisvalingam(l LineString, tolerance float64) (simplified LineString, deviation float64) {
+	if len(l) < 3 {
+		return append(LineString{}, l...), 0
+	}
+	threshold := tolerance * tolerance
+
+	points := make([]vwPoint, len(l))
+	for i := range points {
+		points[i].index = i
+		if i > 0 {
+			points[i].prev = &points[i-1]
+		}
+		if i < len(points)-1 {
+			points[i].next = &points[i+1]
+		}
+	}
+	h := make(vwHeap, 0, len(l)-2)
+	for i := 1; i < len(points)-1; i++ {
+		points[i].area = triangleArea(l[i-1], l[i], l[i+1])
+		h = append(h, &points[i])
+		points[i].heapIndex = len(h) - 1
+	}
+	heap.Init(&h)
+
+	keep := make([]bool, len(l))
+	for i := range keep {
+		keep[i] = true
+	}
+	for h.Len() > 0 && h[0].area < threshold {
+		p := heap.Pop(&h).(*vwPoint)
+		keep[p.index] = false
+		p.prev.next, p.next.prev = p.next, p.prev
+		// Neighbors get a new triangle, which can not be smaller than the removed one
+		for _, n := range []*vwPoint{p.prev, p.next} {
+			if n.prev == nil || n.next == nil {
+				continue
+			}
+			n.area = math.Max(p.area, triangleArea(l[n.prev.index], l[n.index], l[n.next.index]))
+			heap.Fix(&h, n.heapIndex)
+		}
+	}
+	return keptPoints(l, keep)
+}
+
+// keptPoints returns the points of the line we keep and the maximum distance between
+// a removed point and the segment of the simplified line replacing it.
+func keptPoints(l LineString, keep []bool) (simple LineString, deviation float64) {
+	last := 0
+	simple = LineString{l[0]}
+	for i := 1; i < len(l); i++ {
+		if !keep[i] {
+			continue
+		}
+		for j := last + 1; j < i; j++ {
+			closest, _ := ClosestPointOnSegment(l[j], l[last], l[i])
+			deviation = math.Max(deviation, l[j].Distance(closest))
+		}
+		simple = append(simple, l[i])
+		last = i
+	}
+	return
+}
+
+// triangleArea returns the area in square meters of a small triangle, in a local equirectangular projection.
+func triangleArea(a, b, c Point) float64 {
+	scale := EarthRadius * math.Pi / 180
+	cos := math.Cos(toRadians(b.Lat))
+	x1, y1 := angleDiff(b.Lon, a.Lon)*cos*scale, (a.Lat-b.Lat)*scale
+	x2, y2 := angleDiff(b.Lon, c.Lon)*cos*scale, (c.Lat-b.Lat)*scale
+	return math.Abs(x1*y2-x2*y1) / 2
+}
+
+func (h vwHeap) Len() int { return len(h) }
+
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *vwHeap) Push(x interface{}) {
+	p := x.(*vwPoint)
+	p.heapIndex = len(*h)
+	*h = append(*h, p)
+}
+
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	p := old[len(old)-1]
+	*h = old[:len(old)-1]
+	p.heapIndex = -1
+	return p
+}
@@ -0,0 +1,79 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// gpsTrace returns a noisy trace following a great circle.
+func gpsTrace(n int, noise float64) LineString {
+	r := rand.New(rand.NewSource(42))
+	route := Interpolate(13.7665217, 100.6068431, 13.7199345, 100.5197898, n)
+	trace := make(LineString, n)
+	for i, p := range route {
+		trace[i] = Point{p[0], p[1]}.Destination(r.Float64()*360, r.Float64()*noise)
+	}
+	return trace
+}
+
+func TestSimplifyDouglasPeucker(t *testing.T) {
+	trace := gpsTrace(10000, 5)
+	for _, tolerance := range []float64{1, 10, 100} {
+		simple, deviation := SimplifyDouglasPeucker(trace, tolerance)
+		if simple[0] != trace[0] || simple[len(simple)-1] != trace[len(trace)-1] {
+			t.Errorf("SimplifyDouglasPeucker(%v) must keep endpoints", tolerance)
+		}
+		if deviation > tolerance {
+			t.Errorf("SimplifyDouglasPeucker(%v) deviation = %v", tolerance, deviation)
+		}
+		if len(simple) >= len(trace) {
+			t.Errorf("SimplifyDouglasPeucker(%v) kept %v points", tolerance, len(simple))
+		}
+	}
+	// Noise is 5 meters, so with 10 meters tolerance the straight trace becomes a segment
+	if simple, _ := SimplifyDouglasPeucker(trace, 10); len(simple) != 2 {
+		t.Errorf("SimplifyDouglasPeucker() kept %v points, want 2", len(simple))
+	}
+
+	// A corner is kept
+	corner := LineString{{0, 0}, {0, 0.5}, {0, 1}, {0.5, 1}, {1, 1}}
+	simple, deviation := SimplifyDouglasPeucker(corner, 100)
+	if len(simple) != 3 || simple[1] != (Point{0, 1}) || deviation > 1e-6 {
+		t.Errorf("SimplifyDouglasPeucker() = %v, %v", simple, deviation)
+	}
+}
+
+func TestSimplifyVisvalingam(t *testing.T) {
+	trace := gpsTrace(10000, 5)
+	previous := len(trace) + 1
+	for _, tolerance := range []float64{1, 10, 100} {
+		simple, deviation := SimplifyVisvalingam(trace, tolerance)
+		if simple[0] != trace[0] || simple[len(simple)-1] != trace[len(trace)-1] {
+			t.Errorf("SimplifyVisvalingam(%v) must keep endpoints", tolerance)
+		}
+		if len(simple) >= previous {
+			t.Errorf("SimplifyVisvalingam(%v) kept %v points", tolerance, len(simple))
+		}
+		if deviation <= 0 || math.IsNaN(deviation) {
+			t.Errorf("SimplifyVisvalingam(%v) deviation = %v", tolerance, deviation)
+		}
+		previous = len(simple)
+	}
+
+	corner := LineString{{0, 0}, {0, 0.5}, {0, 1}, {0.5, 1}, {1, 1}}
+	simple, deviation := SimplifyVisvalingam(corner, 100)
+	if len(simple) != 3 || simple[1] != (Point{0, 1}) || deviation > 1e-6 {
+		t.Errorf("SimplifyVisvalingam() = %v, %v", simple, deviation)
+	}
+}
+
+func TestSimplify_Short(t *testing.T) {
+	line := LineString{{0, 0}, {1, 1}}
+	if simple, deviation := SimplifyDouglasPeucker(line, 10); len(simple) != 2 || deviation != 0 {
+		t.Errorf("SimplifyDouglasPeucker() = %v", simple)
+	}
+	if simple, deviation := SimplifyVisvalingam(nil, 10); len(simple) != 0 || deviation != 0 {
+		t.Errorf("SimplifyVisvalingam() = %v", simple)
+	}
+}
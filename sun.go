@@ -0,0 +1,184 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Elevations in degrees of the center of the sun at sunrise, sunset and the end of twilights.
+// Sunrise is when the upper edge of the sun appears, taking the atmospheric refraction into account.
+const (
+	SunriseElevation              = -0.833
+	CivilTwilightElevation        = -6.0
+	NauticalTwilightElevation     = -12.0
+	AstronomicalTwilightElevation = -18.0
+)
+
+var (
+	// ErrSunAlwaysUp is returned when the sun stays above the elevation the whole day, as during polar days.
+	ErrSunAlwaysUp = errors.New("Sun above the elevation all day")
+	// ErrSunAlwaysDown is returned when the sun stays below the elevation the whole day, as during polar nights.
+	ErrSunAlwaysDown = errors.New("Sun below the elevation all day")
+)
+
+// SunTimes holds the times of the sun during a day. Events that do not happen during the day,
+// such as the sunset of a polar day, are zero times.
+type SunTimes struct {
+	AstronomicalDawn time.Time
+	NauticalDawn     time.Time
+	CivilDawn        time.Time
+	Sunrise          time.Time
+	SolarNoon        time.Time
+	Sunset           time.Time
+	CivilDusk        time.Time
+	NauticalDusk     time.Time
+	AstronomicalDusk time.Time
+	// PolarDay is true when the sun does not set, and PolarNight when it does not rise.
+	PolarDay   bool
+	PolarNight bool
+}
+
+// Daylight returns the duration between sunrise and sunset, 24 hours for polar days and 0 for polar nights.
+func (s SunTimes) Daylight() time.Duration {
+	switch {
+	case s.PolarDay:
+		return 24 * time.Hour
+	case s.PolarNight:
+		return 0
+	}
+	return s.Sunset.Sub(s.Sunrise)
+}
+
+// SunPosition returns the elevation of the sun above the horizon and its azimuth clockwise from the north,
+// in degrees, seen from the point at the time. The elevation is geometric, without atmospheric refraction.
+// Positions are within 0.01 degree between years 1800 and 2100.
+//  elevation, azimuth := geo.SunPosition(geo.Point{Lat: 13.7563, Lon: 100.5018}, time.Now())
+func SunPosition(p Point, t time.Time) (elevation, azimuth float64) {
+	declination, hourAngle := sunAngles(p.Lon, t)
+	phi := toRadians(p.Lat)
+	delta := toRadians(declination)
+	h := toRadians(hourAngle)
+
+	elevation = toDegrees(math.Asin(math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*math.Cos(h)))
+	azimuth = normalizeBearing(toDegrees(math.Atan2(math.Sin(h), math.Cos(h)*math.Sin(phi)-math.Tan(delta)*math.Cos(phi))) + 180)
+	return
+}
+
+// SunTimesIn returns the times of the sun at the point during the day of date, with times in the location.
+// The day is the year, month and day of date, whatever its location. Events are the closest to the solar noon
+// of the day, near polar circles the sunset may be after midnight.
+//  times := geo.SunTimesIn(geo.Point{Lat: 13.7563, Lon: 100.5018}, time.Now(), loc)
+func SunTimesIn(p Point, date time.Time, loc *time.Location) SunTimes {
+	noon := solarNoon(p, date, loc)
+	s := SunTimes{SolarNoon: noon}
+	s.AstronomicalDawn, s.AstronomicalDusk, _ = sunElevationTimes(p, noon, AstronomicalTwilightElevation)
+	s.NauticalDawn, s.NauticalDusk, _ = sunElevationTimes(p, noon, NauticalTwilightElevation)
+	s.CivilDawn, s.CivilDusk, _ = sunElevationTimes(p, noon, CivilTwilightElevation)
+	var err error
+	s.Sunrise, s.Sunset, err = sunElevationTimes(p, noon, SunriseElevation)
+	s.PolarDay = err == ErrSunAlwaysUp
+	s.PolarNight = err == ErrSunAlwaysDown
+	return s
+}
+
+// LocalSunTimes returns the times of the sun at the point during the day of date in the timezone
+// of the point found by TimezoneLocation, with times in this timezone.
+//  times, err := geo.LocalSunTimes(place.Point(), time.Now())
+//  fmt.Println("Open until", times.Sunset.Format("15:04"))
+func LocalSunTimes(p Point, date time.Time) (SunTimes, error) {
+	loc, err := TimezoneLocation(p)
+	if err != nil {
+		return SunTimes{}, err
+	}
+	return SunTimesIn(p, date.In(loc), loc), nil
+}
+
+// SunElevationTimes returns the times when the sun rises above and sets below the elevation in degrees
+// at the point during the day of date, with times in the location. Elevations of 6 degrees give the end
+// and the start of the golden hours for instance.
+// ErrSunAlwaysUp or ErrSunAlwaysDown are returned when the sun does not cross the elevation.
+func SunElevationTimes(p Point, date time.Time, loc *time.Location, elevation float64) (rise, set time.Time, err error) {
+	return sunElevationTimes(p, solarNoon(p, date, loc), elevation)
+}
+
+// solarNoon returns the time when the sun is the highest at the point, the closest to noon of the day of date in the location.
+func solarNoon(p Point, date time.Time, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	t := time.Date(year, month, day, 12, 0, 0, 0, loc)
+	// The hour angle of the sun increases by about 15 degrees per hour
+	for i := 0; i < 3; i++ {
+		_, hourAngle := sunAngles(p.Lon, t)
+		t = t.Add(time.Duration(-hourAngle / 15 * float64(time.Hour)))
+	}
+	return t.Round(time.Second)
+}
+
+// sunElevationTimes returns the times when the sun crosses the elevation before and after the solar noon.
+func sunElevationTimes(p Point, noon time.Time, elevation float64) (rise, set time.Time, err error) {
+	times := [2]time.Time{}
+	for i, sign := range []float64{-1, 1} {
+		t := noon
+		for j := 0; j < 4; j++ {
+			declination, hourAngle := sunAngles(p.Lon, t)
+			target, e := sunHourAngle(p.Lat, declination, elevation)
+			if e != nil {
+				return time.Time{}, time.Time{}, e
+			}
+			// The sun rises at negative hour angles, counted from the solar noon
+			d := math.Mod(sign*target-hourAngle+540, 360) - 180
+			t = t.Add(time.Duration(d / 15 * float64(time.Hour)))
+		}
+		times[i] = t.Round(time.Second)
+	}
+	return times[0], times[1], nil
+}
+
+// sunHourAngle returns the hour angle in degrees when the sun is at the elevation, seen from the latitude.
+func sunHourAngle(lat, declination, elevation float64) (float64, error) {
+	phi := toRadians(math.Max(-89.9999, math.Min(89.9999, lat)))
+	delta := toRadians(declination)
+	cos := (math.Sin(toRadians(elevation)) - math.Sin(phi)*math.Sin(delta)) / (math.Cos(phi) * math.Cos(delta))
+	switch {
+	case cos > 1:
+		return 0, ErrSunAlwaysDown
+	case cos < -1:
+		return 0, ErrSunAlwaysUp
+	}
+	return toDegrees(math.Acos(cos)), nil
+}
+
+// sunAngles returns the declination of the sun and its hour angle at the longitude, in degrees,
+// with the formulas of the NOAA solar calculator from Astronomical Algorithms by Jean Meeus.
+// The hour angle is in the range [-180, 180), 0 at the solar noon.
+func sunAngles(lon float64, t time.Time) (declination, hourAngle float64) {
+	t = t.UTC()
+	// Julian centuries since J2000.0
+	jc := ((float64(t.Unix())+float64(t.Nanosecond())/1e9)/86400 + 2440587.5 - 2451545) / 36525
+
+	meanLongitude := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360)
+	meanAnomaly := 357.52911 + jc*(35999.05029-0.0001537*jc)
+	eccentricity := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+	m := toRadians(meanAnomaly)
+	center := math.Sin(m)*(1.914602-jc*(0.004817+0.000014*jc)) + math.Sin(2*m)*(0.019993-0.000101*jc) + math.Sin(3*m)*0.000289
+	omega := toRadians(125.04 - 1934.136*jc)
+	apparentLongitude := toRadians(meanLongitude + center - 0.00569 - 0.00478*math.Sin(omega))
+	meanObliquity := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliquity := toRadians(meanObliquity + 0.00256*math.Cos(omega))
+	declination = toDegrees(math.Asin(math.Sin(obliquity) * math.Sin(apparentLongitude)))
+
+	// Equation of time in minutes
+	y := math.Pow(math.Tan(obliquity/2), 2)
+	l0 := toRadians(meanLongitude)
+	equationOfTime := 4 * toDegrees(y*math.Sin(2*l0)-2*eccentricity*math.Sin(m)+4*eccentricity*y*math.Sin(m)*math.Cos(2*l0)-
+		0.5*y*y*math.Sin(4*l0)-1.25*eccentricity*eccentricity*math.Sin(2*m))
+
+	minutes := float64(t.Hour()*60+t.Minute()) + (float64(t.Second())+float64(t.Nanosecond())/1e9)/60
+	trueSolarTime := minutes + equationOfTime + 4*lon
+	hourAngle = math.Mod(trueSolarTime/4, 360)
+	if hourAngle < 0 {
+		hourAngle += 360
+	}
+	hourAngle -= 180
+	return
+}
@@ -0,0 +1,120 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSunPosition(t *testing.T) {
+	bangkok := Point{13.7563, 100.5018}
+	tests := []struct {
+		p                  Point
+		t                  time.Time
+		elevation, azimuth float64
+	}{
+		// Solar noon at the equinox
+		{bangkok, time.Date(2021, 3, 20, 5, 25, 27, 0, time.UTC), 76.17, 180},
+		{bangkok, time.Date(2021, 3, 20, 17, 25, 27, 0, time.UTC), -76.12, 0.16},
+		// Sun over the tropic of Cancer at the solstice
+		{Point{23.437, 0}, time.Date(2021, 6, 21, 12, 1, 52, 0, time.UTC), 90, 0},
+		{Point{0, 0}, time.Date(2021, 6, 21, 12, 1, 52, 0, time.UTC), 66.56, 0},
+		{Point{51.4779, 0}, time.Date(2021, 6, 21, 3, 42, 47, 0, time.UTC), -0.833, 48.93},
+	}
+	for _, tt := range tests {
+		elevation, azimuth := SunPosition(tt.p, tt.t)
+		// The azimuth is meaningless at the zenith
+		if math.Abs(elevation-tt.elevation) > 0.02 || (tt.elevation < 89 && math.Abs(angleDiff(azimuth, tt.azimuth)) > 0.2) {
+			t.Errorf("SunPosition(%v, %v) = %v, %v, want %v, %v", tt.p, tt.t, elevation, azimuth, tt.elevation, tt.azimuth)
+		}
+	}
+}
+
+func TestSunTimesIn(t *testing.T) {
+	// Times of almanacs, rounded to the minute
+	at := func(loc *time.Location, month time.Month, day, hour, min int) time.Time {
+		return time.Date(2021, month, day, hour, min, 0, 0, loc)
+	}
+	bangkok := time.FixedZone("ICT", 7*3600)
+	s := SunTimesIn(Point{13.7563, 100.5018}, time.Date(2021, 3, 20, 0, 0, 0, 0, time.UTC), bangkok)
+	checks := []struct {
+		name      string
+		got, want time.Time
+	}{
+		{"AstronomicalDawn", s.AstronomicalDawn, at(bangkok, 3, 20, 5, 12)},
+		{"NauticalDawn", s.NauticalDawn, at(bangkok, 3, 20, 5, 36)},
+		{"CivilDawn", s.CivilDawn, at(bangkok, 3, 20, 6, 1)},
+		{"Sunrise", s.Sunrise, at(bangkok, 3, 20, 6, 22)},
+		{"SolarNoon", s.SolarNoon, at(bangkok, 3, 20, 12, 25)},
+		{"Sunset", s.Sunset, at(bangkok, 3, 20, 18, 29)},
+		{"CivilDusk", s.CivilDusk, at(bangkok, 3, 20, 18, 50)},
+		{"NauticalDusk", s.NauticalDusk, at(bangkok, 3, 20, 19, 15)},
+		{"AstronomicalDusk", s.AstronomicalDusk, at(bangkok, 3, 20, 19, 40)},
+	}
+	london := time.FixedZone("BST", 3600)
+	s = SunTimesIn(Point{51.5074, -0.1278}, time.Date(2021, 6, 21, 0, 0, 0, 0, london), london)
+	checks = append(checks, []struct {
+		name      string
+		got, want time.Time
+	}{
+		{"London Sunrise", s.Sunrise, at(london, 6, 21, 4, 43)},
+		{"London Sunset", s.Sunset, at(london, 6, 21, 21, 21)},
+	}...)
+	for _, c := range checks {
+		if d := c.got.Sub(c.want); d < -time.Minute || d > time.Minute {
+			t.Errorf("%v = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if s.PolarDay || s.PolarNight || s.Sunrise.Location() != london {
+		t.Errorf("SunTimesIn() = %+v", s)
+	}
+	if d := s.Daylight(); d < 16*time.Hour+37*time.Minute || d > 16*time.Hour+39*time.Minute {
+		t.Errorf("Daylight() = %v", d)
+	}
+}
+
+func TestSunTimesIn_polar(t *testing.T) {
+	tromso := Point{69.6496, 18.9560}
+	cet := time.FixedZone("CET", 3600)
+
+	s := SunTimesIn(tromso, time.Date(2021, 6, 21, 0, 0, 0, 0, time.UTC), cet)
+	if !s.PolarDay || s.PolarNight || !s.Sunrise.IsZero() || !s.Sunset.IsZero() || !s.CivilDusk.IsZero() || s.Daylight() != 24*time.Hour {
+		t.Errorf("SunTimesIn(midnight sun) = %+v", s)
+	}
+	s = SunTimesIn(tromso, time.Date(2021, 12, 21, 0, 0, 0, 0, time.UTC), cet)
+	if s.PolarDay || !s.PolarNight || !s.Sunrise.IsZero() || s.Daylight() != 0 {
+		t.Errorf("SunTimesIn(polar night) = %+v", s)
+	}
+	// Civil twilight around noon during the polar night
+	if s.CivilDawn.Hour() != 9 || s.CivilDusk.Hour() != 13 {
+		t.Errorf("SunTimesIn(polar night) civil twilight = %v, %v", s.CivilDawn, s.CivilDusk)
+	}
+
+	// The sun sets after midnight
+	cest := time.FixedZone("CEST", 2*3600)
+	rise, set, err := SunElevationTimes(tromso, time.Date(2021, 5, 16, 0, 0, 0, 0, time.UTC), cest, SunriseElevation)
+	if err != nil || rise.Day() != 16 || set.Day() != 17 {
+		t.Errorf("SunElevationTimes() = %v, %v, %v", rise, set, err)
+	}
+	if _, _, err := SunElevationTimes(Point{90, 0}, time.Date(2021, 6, 21, 0, 0, 0, 0, time.UTC), time.UTC, 30); err != ErrSunAlwaysDown {
+		t.Errorf("SunElevationTimes(north pole) error = %v", err)
+	}
+	if _, _, err := SunElevationTimes(Point{-90, 0}, time.Date(2021, 12, 21, 0, 0, 0, 0, time.UTC), time.UTC, 0); err != ErrSunAlwaysUp {
+		t.Errorf("SunElevationTimes(south pole) error = %v", err)
+	}
+}
+
+func TestLocalSunTimes(t *testing.T) {
+	s, err := LocalSunTimes(Point{13.7563, 100.5018}, time.Date(2021, 3, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Sunrise.Format("15:04") != "06:22" || s.Sunset.Format("15:04") != "18:28" {
+		t.Errorf("LocalSunTimes() = %v, %v", s.Sunrise, s.Sunset)
+	}
+	// 20:00 UTC is already the next day in Bangkok
+	s, err = LocalSunTimes(Point{13.7563, 100.5018}, time.Date(2021, 3, 20, 20, 0, 0, 0, time.UTC))
+	if err != nil || s.Sunrise.Day() != 21 || s.Sunset.Day() != 21 {
+		t.Errorf("LocalSunTimes(next local day) = %v, %v, %v", s.Sunrise, s.Sunset, err)
+	}
+}
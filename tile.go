@@ -0,0 +1,262 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WebMercatorMaxLatitude is the latitude of the top edge of the Web Mercator square world.
+// Points beyond it are clamped by the Web Mercator conversions.
+const WebMercatorMaxLatitude = 85.0511287798066
+
+// webMercatorRadius is the radius of the sphere used by EPSG:3857, the semi-major axis of WGS84.
+const webMercatorRadius = 6378137
+
+// tileMaxZoom is the highest zoom level supported by tiles.
+const tileMaxZoom = 30
+
+// ErrInvalidTile is returned when a tile is outside of the tile grid of its zoom level.
+var ErrInvalidTile = errors.New("Invalid tile")
+
+// ErrInvalidQuadkey is returned when a quadkey contains characters other than 0, 1, 2 and 3.
+var ErrInvalidQuadkey = errors.New("Invalid quadkey")
+
+// Tile is a slippy map tile, X from 0 at longitude -180 eastwards and Y from 0 at the top southwards.
+// Details https://wiki.openstreetmap.org/wiki/Slippy_map_tilenames
+type Tile struct {
+	Z int
+	X int
+	Y int
+}
+
+// mercatorUnit returns the Web Mercator coordinates of the point in a world 1 wide:
+// x from 0 at longitude -180 to 1 at longitude 180, y from 0 at the top to 1 at the bottom.
+func mercatorUnit(p Point) (x, y float64) {
+	lat := math.Max(-WebMercatorMaxLatitude, math.Min(WebMercatorMaxLatitude, p.Lat))
+	x = (p.Lon + 180) / 360
+	sin := math.Sin(toRadians(lat))
+	y = 0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)
+	return
+}
+
+// mercatorUnitPoint returns the point at the Web Mercator coordinates in a world 1 wide.
+func mercatorUnitPoint(x, y float64) Point {
+	return Point{
+		Lat: toDegrees(math.Atan(math.Sinh(math.Pi * (1 - 2*y)))),
+		Lon: x*360 - 180,
+	}
+}
+
+// WebMercator returns the EPSG:3857 coordinates of the point in meters.
+// Latitudes are clamped to WebMercatorMaxLatitude.
+//  x, y := geo.Point{Lat: 52, Lon: 9}.WebMercator() // 1001875.417, 6800125.454
+func (p Point) WebMercator() (x, y float64) {
+	lat := math.Max(-WebMercatorMaxLatitude, math.Min(WebMercatorMaxLatitude, p.Lat))
+	x = webMercatorRadius * toRadians(p.Lon)
+	y = webMercatorRadius * math.Log(math.Tan(math.Pi/4+toRadians(lat)/2))
+	return
+}
+
+// WebMercatorPoint returns the point at the EPSG:3857 coordinates in meters.
+func WebMercatorPoint(x, y float64) Point {
+	return Point{
+		Lat: toDegrees(2*math.Atan(math.Exp(y/webMercatorRadius)) - math.Pi/2),
+		Lon: toDegrees(x / webMercatorRadius),
+	}
+}
+
+// Pixel returns the pixel coordinates of the point in the world map at the zoom level,
+// made of tiles of tileSize pixels, from the top left corner.
+//  x, y := p.Pixel(10, 256)
+func (p Point) Pixel(zoom, tileSize int) (x, y float64) {
+	size := float64(tileSize) * math.Exp2(float64(zoom))
+	x, y = mercatorUnit(p)
+	return x * size, y * size
+}
+
+// PixelPoint returns the point at the pixel coordinates of the world map at the zoom level,
+// made of tiles of tileSize pixels.
+func PixelPoint(x, y float64, zoom, tileSize int) Point {
+	size := float64(tileSize) * math.Exp2(float64(zoom))
+	return mercatorUnitPoint(x/size, y/size)
+}
+
+// Tile returns the tile holding the point at the zoom level.
+// Points on the antimeridian and beyond WebMercatorMaxLatitude belong to the edge tiles.
+// Zoom levels are clamped to the range 0 to 30.
+//  geo.Point{Lat: 13.7563, Lon: 100.5018}.Tile(10) // 10/797/472
+func (p Point) Tile(zoom int) Tile {
+	zoom = clampTile(zoom, tileMaxZoom+1)
+	n := 1 << uint(zoom)
+	x, y := mercatorUnit(p)
+	return Tile{
+		Z: zoom,
+		X: clampTile(int(math.Floor(snapTile(x*float64(n), n))), n),
+		Y: clampTile(int(math.Floor(snapTile(y*float64(n), n))), n),
+	}
+}
+
+// snapTile returns the tile coordinate v of a grid of n tiles rounded to the tile edge when it is on the edge,
+// to cancel rounding errors of the conversions of tile corners to points and back.
+// The tolerance is 1e-12 of the world, below a millimeter, so real points are not moved to the next tile.
+func snapTile(v float64, n int) float64 {
+	if r := math.Round(v); math.Abs(v-r) < 1e-12*float64(n) {
+		return r
+	}
+	return v
+}
+
+// clampTile returns the tile index i clamped to the n tiles of a row or column.
+func clampTile(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// Validate returns ErrInvalidTile when the tile is outside of the tile grid of its zoom level.
+func (t Tile) Validate() error {
+	if t.Z < 0 || t.Z > tileMaxZoom {
+		return ErrInvalidTile
+	}
+	n := 1 << uint(t.Z)
+	if t.X < 0 || t.X >= n || t.Y < 0 || t.Y >= n {
+		return ErrInvalidTile
+	}
+	return nil
+}
+
+// BoundingBox returns the bounding box of the tile.
+func (t Tile) BoundingBox() BoundingBox {
+	n := math.Exp2(float64(t.Z))
+	nw := mercatorUnitPoint(float64(t.X)/n, float64(t.Y)/n)
+	se := mercatorUnitPoint(float64(t.X+1)/n, float64(t.Y+1)/n)
+	return BoundingBox{MinLat: se.Lat, MinLon: nw.Lon, MaxLat: nw.Lat, MaxLon: se.Lon}
+}
+
+// Parent returns the tile of the lower zoom level holding the tile.
+// The tile of zoom level 0 is its own parent.
+func (t Tile) Parent() Tile {
+	if t.Z == 0 {
+		return t
+	}
+	return Tile{Z: t.Z - 1, X: t.X >> 1, Y: t.Y >> 1}
+}
+
+// Children returns the 4 tiles of the higher zoom level covering the tile,
+// in the order top left, top right, bottom left, bottom right.
+func (t Tile) Children() []Tile {
+	x, y := t.X<<1, t.Y<<1
+	return []Tile{
+		{Z: t.Z + 1, X: x, Y: y},
+		{Z: t.Z + 1, X: x + 1, Y: y},
+		{Z: t.Z + 1, X: x, Y: y + 1},
+		{Z: t.Z + 1, X: x + 1, Y: y + 1},
+	}
+}
+
+// Quadkey returns the Bing Maps quadkey of the tile, one digit per zoom level.
+// Details https://docs.microsoft.com/en-us/bingmaps/articles/bing-maps-tile-system
+//  geo.Tile{Z: 3, X: 3, Y: 5}.Quadkey() // "213"
+func (t Tile) Quadkey() string {
+	var b strings.Builder
+	for i := t.Z; i > 0; i-- {
+		digit := byte('0')
+		mask := 1 << uint(i-1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		b.WriteByte(digit)
+	}
+	return b.String()
+}
+
+// ParseQuadkey returns the tile of the Bing Maps quadkey.
+// The empty quadkey is the tile of zoom level 0.
+func ParseQuadkey(quadkey string) (t Tile, err error) {
+	if len(quadkey) > tileMaxZoom {
+		return t, ErrInvalidQuadkey
+	}
+	t.Z = len(quadkey)
+	for i := 0; i < len(quadkey); i++ {
+		c := quadkey[i]
+		if c < '0' || c > '3' {
+			return Tile{}, ErrInvalidQuadkey
+		}
+		t.X = t.X<<1 | int(c-'0')&1
+		t.Y = t.Y<<1 | int(c-'0')>>1
+	}
+	return
+}
+
+// String returns the tile as z/x/y, the path of the tile in most tile servers.
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// ParseTile returns the tile of a z/x/y string.
+// An extension is ignored so paths of tile servers like "10/797/480.png" are accepted.
+func ParseTile(s string) (t Tile, err error) {
+	s = strings.TrimPrefix(s, "/")
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return t, ErrInvalidTile
+	}
+	values := make([]int, 3)
+	for i, part := range parts {
+		if values[i], err = strconv.Atoi(part); err != nil {
+			return Tile{}, ErrInvalidTile
+		}
+	}
+	t = Tile{Z: values[0], X: values[1], Y: values[2]}
+	if err = t.Validate(); err != nil {
+		return Tile{}, err
+	}
+	return
+}
+
+// TilesInBoundingBox returns the tiles of the zoom level covering the bounding box,
+// row by row from the top left tile. The east and south edges of the box are excluded,
+// so the bounding box of a tile is covered by the tile alone.
+// Like BoundingBox.Contains, the box must not cross the antimeridian.
+//  tiles := geo.TilesInBoundingBox(geo.BoundingBox{MinLat: 13.5, MinLon: 100.3, MaxLat: 14, MaxLon: 100.8}, 10)
+func TilesInBoundingBox(b BoundingBox, zoom int) []Tile {
+	if b.MinLat > b.MaxLat || b.MinLon > b.MaxLon {
+		return nil
+	}
+	nw := Point{Lat: b.MaxLat, Lon: b.MinLon}.Tile(zoom)
+	zoom = nw.Z
+	n := 1 << uint(zoom)
+	x, y := mercatorUnit(Point{Lat: b.MinLat, Lon: b.MaxLon})
+	se := Tile{
+		Z: zoom,
+		X: clampTile(int(math.Ceil(snapTile(x*float64(n), n)))-1, n),
+		Y: clampTile(int(math.Ceil(snapTile(y*float64(n), n)))-1, n),
+	}
+	// Boxes without width or height on a tile edge
+	if se.X < nw.X {
+		se.X = nw.X
+	}
+	if se.Y < nw.Y {
+		se.Y = nw.Y
+	}
+	tiles := make([]Tile, 0, (se.X-nw.X+1)*(se.Y-nw.Y+1))
+	for y := nw.Y; y <= se.Y; y++ {
+		for x := nw.X; x <= se.X; x++ {
+			tiles = append(tiles, Tile{Z: zoom, X: x, Y: y})
+		}
+	}
+	return tiles
+}
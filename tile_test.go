@@ -0,0 +1,217 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPoint_WebMercator(t *testing.T) {
+	tests := []struct {
+		p    Point
+		x, y float64
+	}{
+		{Point{52, 9}, 1001875.4171394621, 6800125.454397305},
+		{Point{0, 0}, 0, 0},
+		{Point{0, 180}, 20037508.342789244, 0},
+		{Point{WebMercatorMaxLatitude, -180}, -20037508.342789244, 20037508.342789244},
+		{Point{-90, 0}, 0, -20037508.342789244},
+	}
+	for _, tt := range tests {
+		x, y := tt.p.WebMercator()
+		if math.Abs(x-tt.x) > 1e-6 || math.Abs(y-tt.y) > 1e-6 {
+			t.Errorf("%v.WebMercator() = %v, %v, want %v, %v", tt.p, x, y, tt.x, tt.y)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := Point{Lat: r.Float64()*170 - 85, Lon: r.Float64()*360 - 180}
+		if q := WebMercatorPoint(p.WebMercator()); q.Distance(p) > 1e-6 {
+			t.Fatalf("WebMercatorPoint(%v.WebMercator()) = %v", p, q)
+		}
+		x, y := p.Pixel(12, 512)
+		if q := PixelPoint(x, y, 12, 512); q.Distance(p) > 1e-6 {
+			t.Fatalf("PixelPoint(%v.Pixel()) = %v", p, q)
+		}
+	}
+}
+
+func TestPoint_Pixel(t *testing.T) {
+	tests := []struct {
+		p        Point
+		zoom     int
+		tileSize int
+		x, y     float64
+	}{
+		{Point{0, 0}, 0, 256, 128, 128},
+		{Point{WebMercatorMaxLatitude, -180}, 1, 256, 0, 0},
+		{Point{-WebMercatorMaxLatitude, 180}, 2, 512, 2048, 2048},
+		{Point{13.7563, 100.5018}, 10, 256, 204255.18, 120957.32},
+	}
+	for _, tt := range tests {
+		x, y := tt.p.Pixel(tt.zoom, tt.tileSize)
+		if math.Abs(x-tt.x) > 1e-2 || math.Abs(y-tt.y) > 1e-2 {
+			t.Errorf("%v.Pixel(%v, %v) = %v, %v, want %v, %v", tt.p, tt.zoom, tt.tileSize, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+func TestPoint_Tile(t *testing.T) {
+	tests := []struct {
+		p    Point
+		zoom int
+		want Tile
+	}{
+		{Point{13.7563, 100.5018}, 10, Tile{10, 797, 472}},
+		{Point{13.7563, 100.5018}, 0, Tile{0, 0, 0}},
+		{Point{51.5074, -0.1278}, 15, Tile{15, 16372, 10896}},
+		{Point{-33.8688, 151.2093}, 8, Tile{8, 235, 153}},
+		{Point{90, 180}, 3, Tile{3, 7, 0}},
+		{Point{-90, -180}, 3, Tile{3, 0, 7}},
+		// Points a few meters from tile edges at low zoom levels
+		{Point{10, -0.0001}, 1, Tile{1, 0, 0}},
+		{Point{10, 0.0001}, 1, Tile{1, 1, 0}},
+		{Point{0.0001, 10}, 1, Tile{1, 1, 0}},
+		{Point{-0.0001, 10}, 1, Tile{1, 1, 1}},
+		{Point{0, 0}, 1, Tile{1, 1, 1}},
+		{Point{10, 89.9999}, 2, Tile{2, 2, 1}},
+		{Point{10, 90.0001}, 2, Tile{2, 3, 1}},
+		// Zoom levels out of range
+		{Point{-90, -180}, -1, Tile{0, 0, 0}},
+		{Point{-90, -180}, 64, Tile{30, 0, 1<<30 - 1}},
+	}
+	for _, tt := range tests {
+		got := tt.p.Tile(tt.zoom)
+		if got != tt.want {
+			t.Errorf("%v.Tile(%v) = %v, want %v", tt.p, tt.zoom, got, tt.want)
+		}
+		if math.Abs(tt.p.Lat) < WebMercatorMaxLatitude && tt.p.Lon < 180 && !got.BoundingBox().Contains(tt.p) {
+			t.Errorf("%v.BoundingBox() = %v does not contain %v", got, got.BoundingBox(), tt.p)
+		}
+	}
+}
+
+func TestTile_BoundingBox(t *testing.T) {
+	tests := []struct {
+		t    Tile
+		want BoundingBox
+	}{
+		{Tile{0, 0, 0}, BoundingBox{-WebMercatorMaxLatitude, -180, WebMercatorMaxLatitude, 180}},
+		{Tile{1, 1, 0}, BoundingBox{0, 0, WebMercatorMaxLatitude, 180}},
+		{Tile{10, 797, 472}, BoundingBox{13.581921, 100.195313, 13.923404, 100.546875}},
+	}
+	for _, tt := range tests {
+		got := tt.t.BoundingBox()
+		if math.Abs(got.MinLat-tt.want.MinLat) > 1e-6 || math.Abs(got.MinLon-tt.want.MinLon) > 1e-6 ||
+			math.Abs(got.MaxLat-tt.want.MaxLat) > 1e-6 || math.Abs(got.MaxLon-tt.want.MaxLon) > 1e-6 {
+			t.Errorf("%v.BoundingBox() = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestTile_ParentChildren(t *testing.T) {
+	tile := Tile{10, 797, 472}
+	if got := tile.Parent(); got != (Tile{9, 398, 236}) {
+		t.Errorf("Parent() = %v", got)
+	}
+	if got := (Tile{}).Parent(); got != (Tile{}) {
+		t.Errorf("Parent() of zoom 0 = %v", got)
+	}
+	children := tile.Children()
+	want := []Tile{{11, 1594, 944}, {11, 1595, 944}, {11, 1594, 945}, {11, 1595, 945}}
+	for i := range want {
+		if children[i] != want[i] || children[i].Parent() != tile {
+			t.Errorf("Children()[%v] = %v, want %v", i, children[i], want[i])
+		}
+	}
+}
+
+func TestTile_Quadkey(t *testing.T) {
+	tests := []struct {
+		t    Tile
+		want string
+	}{
+		{Tile{3, 3, 5}, "213"},
+		{Tile{0, 0, 0}, ""},
+		{Tile{1, 1, 1}, "3"},
+		{Tile{10, 797, 472}, "1322033101"},
+	}
+	for _, tt := range tests {
+		if got := tt.t.Quadkey(); got != tt.want {
+			t.Errorf("%v.Quadkey() = %q, want %q", tt.t, got, tt.want)
+		}
+		if got, err := ParseQuadkey(tt.want); err != nil || got != tt.t {
+			t.Errorf("ParseQuadkey(%q) = %v, %v, want %v", tt.want, got, err, tt.t)
+		}
+	}
+	for _, s := range []string{"214", "12a", "0123012301230123012301230123012"} {
+		if _, err := ParseQuadkey(s); err != ErrInvalidQuadkey {
+			t.Errorf("ParseQuadkey(%q) error = %v", s, err)
+		}
+	}
+}
+
+func TestParseTile(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Tile
+		wantErr bool
+	}{
+		{"10/797/480", Tile{10, 797, 480}, false},
+		{"/10/797/480.png", Tile{10, 797, 480}, false},
+		{"0/0/0", Tile{}, false},
+		{"10/1024/480", Tile{}, true},
+		{"10/797/-1", Tile{}, true},
+		{"31/0/0", Tile{}, true},
+		{"10/797", Tile{}, true},
+		{"10/x/480", Tile{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTile(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTile(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+	if got := (Tile{10, 797, 480}).String(); got != "10/797/480" {
+		t.Errorf("String() = %v", got)
+	}
+}
+
+func TestTilesInBoundingBox(t *testing.T) {
+	b := BoundingBox{MinLat: 13.5, MinLon: 100.3, MaxLat: 14, MaxLon: 100.8}
+	got := TilesInBoundingBox(b, 10)
+	want := []Tile{{10, 797, 471}, {10, 798, 471}, {10, 797, 472}, {10, 798, 472}, {10, 797, 473}, {10, 798, 473}}
+	if len(got) != len(want) {
+		t.Fatalf("TilesInBoundingBox() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TilesInBoundingBox()[%v] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	world := BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+	if n := len(TilesInBoundingBox(world, 3)); n != 64 {
+		t.Errorf("TilesInBoundingBox(world) returned %v tiles, want 64", n)
+	}
+	if got := TilesInBoundingBox(BoundingBox{MinLat: 14, MinLon: 100, MaxLat: 13, MaxLon: 101}, 10); got != nil {
+		t.Errorf("TilesInBoundingBox(empty box) = %v", got)
+	}
+
+	// Boxes of tiles are covered by the tile, or by its children at the next zoom level
+	for _, tile := range []Tile{{10, 797, 472}, {0, 0, 0}, {3, 7, 7}, {15, 16372, 10896}, {29, 1 << 28, 12345}} {
+		if got := TilesInBoundingBox(tile.BoundingBox(), tile.Z); !reflect.DeepEqual(got, []Tile{tile}) {
+			t.Errorf("TilesInBoundingBox(%v) = %v", tile, got)
+		}
+		if got := TilesInBoundingBox(tile.BoundingBox(), tile.Z+1); !reflect.DeepEqual(got, tile.Children()) {
+			t.Errorf("TilesInBoundingBox(%v, %v) = %v, want %v", tile, tile.Z+1, got, tile.Children())
+		}
+	}
+	// Points on tile edges
+	p := Tile{10, 797, 472}.BoundingBox()
+	if got := TilesInBoundingBox(BoundingBox{MinLat: p.MaxLat, MinLon: p.MinLon, MaxLat: p.MaxLat, MaxLon: p.MinLon}, 10); !reflect.DeepEqual(got, []Tile{{10, 797, 472}}) {
+		t.Errorf("TilesInBoundingBox(corner) = %v", got)
+	}
+}
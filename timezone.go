@@ -0,0 +1,314 @@
+package geo
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed" // timezone boundaries
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"math"
+	"sync"
+	"time"
+)
+
+// timezoneData holds the boundaries of the timezones, oceans included, simplified to about 100 meters.
+// Boundaries are from timezone-boundary-builder https://github.com/evansiroky/timezone-boundary-builder
+// release 2025b, as simplified by tzf https://github.com/ringsaturn/tzf-rel-lite v0.0.2025-b2,
+// under the Open Database License. They are written by internal/gentimezones, run go generate -run gentimezones to update them.
+//
+// The gzip compressed data is a sequence of varints: the release of the boundaries, the number of timezones,
+// then for each timezone its name, its number of polygons and for each polygon its number of rings,
+// the outer ring first. Each ring is its number of points and the differences in longitude and latitude
+// from the previous point, in 1e-4 degrees.
+//
+//go:generate sh -c "cd internal/gentimezones && go run . -o ../../timezones.bin.gz"
+//go:embed timezones.bin.gz
+var timezoneData []byte
+
+// timezoneScale is the number of units of the timezone data in one degree.
+const timezoneScale = 1e4
+
+// ErrTimezoneNotFound is returned when no timezone holds the point, which should not happen as oceans have timezones.
+var ErrTimezoneNotFound = errors.New("Timezone not found")
+
+// errInvalidTimezones is returned when reading malformed timezone data.
+var errInvalidTimezones = errors.New("Invalid timezone data")
+
+type (
+	// timezoneIndex holds the polygons of all timezones with a grid of cells of 1 degree
+	// listing the polygons overlapping each cell.
+	timezoneIndex struct {
+		release  string
+		names    []string
+		polygons []timezonePolygon
+		cells    [360 * 180][]int32
+	}
+
+	// timezonePolygon is a polygon of a timezone. Rings are flat lists of longitudes and latitudes
+	// in 1e-4 degrees, the first ring is the outer ring.
+	timezonePolygon struct {
+		zone                   int
+		minX, minY, maxX, maxY int32
+		rings                  [][]int32
+	}
+)
+
+var (
+	timezones     *timezoneIndex
+	timezonesErr  error
+	timezonesOnce sync.Once
+)
+
+// Timezone returns the IANA name of the timezone of the point, such as Asia/Bangkok.
+// Points at sea beyond territorial waters are in nautical timezones such as Etc/GMT-7.
+// Boundaries are embedded in the package, they are loaded at the first call, which takes about 100 ms.
+//  tz, err := geo.Timezone(place.Point())
+func Timezone(p Point) (string, error) {
+	timezonesOnce.Do(loadTimezones)
+	if timezonesErr != nil {
+		return "", timezonesErr
+	}
+	return timezones.lookup(p)
+}
+
+// TimezoneLocation returns the location of the timezone of the point, to convert times to the local time.
+// The location is loaded from the timezone database of the system, or from the database embedded
+// in programs importing time/tzdata.
+//  loc, err := geo.TimezoneLocation(geo.Point{Lat: 13.7563, Lon: 100.5018})
+//  fmt.Println(time.Now().In(loc))
+func TimezoneLocation(p Point) (*time.Location, error) {
+	name, err := Timezone(p)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(name)
+}
+
+// TimezoneRelease returns the release of timezone-boundary-builder of the embedded boundaries, such as 2025b.
+func TimezoneRelease() string {
+	timezonesOnce.Do(loadTimezones)
+	if timezonesErr != nil {
+		return ""
+	}
+	return timezones.release
+}
+
+// loadTimezones decompresses and indexes the embedded boundaries.
+func loadTimezones() {
+	timezones, timezonesErr = readTimezones(timezoneData)
+}
+
+// readTimezones reads the gzip compressed timezone boundaries.
+func readTimezones(data []byte) (*timezoneIndex, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(raw)
+	// Keep the first error, next reads fail as well
+	readUvarint := func() int {
+		v, e := binary.ReadUvarint(r)
+		if e != nil && err == nil {
+			err = e
+		}
+		return int(v)
+	}
+	// Counts of items of at least one byte, min items or more
+	readCount := func(min int) int {
+		n := readUvarint()
+		if (n < min || n > r.Len()) && err == nil {
+			err = errInvalidTimezones
+		}
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+	readString := func() string {
+		b := make([]byte, readCount(0))
+		if _, e := r.Read(b); e != nil && err == nil && len(b) > 0 {
+			err = e
+		}
+		return string(b)
+	}
+
+	index := &timezoneIndex{release: readString()}
+	index.names = make([]string, readCount(0))
+	for zone := range index.names {
+		index.names[zone] = readString()
+		polygons := readCount(0)
+		for i := 0; i < polygons && err == nil; i++ {
+			// Polygons have an outer ring
+			polygon := timezonePolygon{zone: zone, rings: make([][]int32, readCount(1))}
+			for j := range polygon.rings {
+				ring := make([]int32, 2*readCount(0))
+				var x, y int64
+				for k := 0; k < len(ring) && err == nil; k += 2 {
+					dx, e := binary.ReadVarint(r)
+					if e != nil {
+						err = e
+					}
+					dy, e := binary.ReadVarint(r)
+					if e != nil && err == nil {
+						err = e
+					}
+					x += dx
+					y += dy
+					ring[k], ring[k+1] = int32(x), int32(y)
+				}
+				polygon.rings[j] = ring
+			}
+			index.polygons = append(index.polygons, polygon)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range index.polygons {
+		polygon := &index.polygons[i]
+		outer := polygon.rings[0]
+		if len(outer) == 0 {
+			continue
+		}
+		polygon.minX, polygon.minY, polygon.maxX, polygon.maxY = outer[0], outer[1], outer[0], outer[1]
+		for k := 2; k < len(outer); k += 2 {
+			polygon.minX = minInt32(polygon.minX, outer[k])
+			polygon.maxX = maxInt32(polygon.maxX, outer[k])
+			polygon.minY = minInt32(polygon.minY, outer[k+1])
+			polygon.maxY = maxInt32(polygon.maxY, outer[k+1])
+		}
+		minCol, minRow := timezoneCell(polygon.minX, polygon.minY)
+		maxCol, maxRow := timezoneCell(polygon.maxX, polygon.maxY)
+		for row := minRow; row <= maxRow; row++ {
+			for col := minCol; col <= maxCol; col++ {
+				index.cells[row*360+col] = append(index.cells[row*360+col], int32(i))
+			}
+		}
+	}
+	return index, nil
+}
+
+// timezoneCell returns the column and the row of the cell of 1 degree holding coordinates in 1e-4 degrees.
+func timezoneCell(x, y int32) (col, row int) {
+	col = int(math.Floor(float64(x)/timezoneScale)) + 180
+	row = int(math.Floor(float64(y)/timezoneScale)) + 90
+	if col > 359 {
+		col = 359
+	} else if col < 0 {
+		col = 0
+	}
+	if row > 179 {
+		row = 179
+	} else if row < 0 {
+		row = 0
+	}
+	return
+}
+
+// lookup returns the name of the timezone of the first polygon holding the point.
+// Simplified boundaries leave thin gaps between neighbor timezones, points in gaps get the timezone of the nearest polygon.
+func (index *timezoneIndex) lookup(p Point) (string, error) {
+	x := normalizeLongitude(p.Lon) * timezoneScale
+	y := math.Max(-90, math.Min(90, p.Lat)) * timezoneScale
+	col, row := timezoneCell(int32(math.Floor(x)), int32(math.Floor(y)))
+	for _, i := range index.cells[row*360+col] {
+		if index.polygons[i].contains(x, y) {
+			return index.names[index.polygons[i].zone], nil
+		}
+	}
+
+	// The nearest polygon of the neighbor cells
+	nearest, best := -1, math.Inf(1)
+	cos := math.Cos(toRadians(p.Lat))
+	for r := row - 1; r <= row+1; r++ {
+		if r < 0 || r > 179 {
+			continue
+		}
+		for c := col - 1; c <= col+1; c++ {
+			for _, i := range index.cells[r*360+(c+360)%360] {
+				if d := index.polygons[i].distance(x, y, cos); d < best {
+					nearest, best = int(i), d
+				}
+			}
+		}
+	}
+	if nearest < 0 {
+		return "", ErrTimezoneNotFound
+	}
+	return index.names[index.polygons[nearest].zone], nil
+}
+
+// contains returns true if the point in 1e-4 degrees is inside the outer ring of the polygon and outside its holes.
+func (polygon *timezonePolygon) contains(x, y float64) bool {
+	if x < float64(polygon.minX) || x > float64(polygon.maxX) || y < float64(polygon.minY) || y > float64(polygon.maxY) {
+		return false
+	}
+	if !ringContains(polygon.rings[0], x, y) {
+		return false
+	}
+	for _, hole := range polygon.rings[1:] {
+		if ringContains(hole, x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains returns true if a ray going east from the point crosses the ring an odd number of times.
+func ringContains(ring []int32, x, y float64) bool {
+	inside := false
+	n := len(ring)
+	for i, j := 0, n-2; i < n; j, i = i, i+2 {
+		x1, y1 := float64(ring[j]), float64(ring[j+1])
+		x2, y2 := float64(ring[i]), float64(ring[i+1])
+		if (y1 > y) != (y2 > y) && x < x1+(y-y1)*(x2-x1)/(y2-y1) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distance returns the distance from the point to the outer ring of the polygon, in 1e-4 degrees of latitude.
+// Longitudes are scaled by cos, the cosine of the latitude of the point.
+func (polygon *timezonePolygon) distance(x, y, cos float64) float64 {
+	ring := polygon.rings[0]
+	best := math.Inf(1)
+	n := len(ring)
+	for i, j := 0, n-2; i < n; j, i = i, i+2 {
+		ax, ay := (float64(ring[j])-x)*cos, float64(ring[j+1])-y
+		bx, by := (float64(ring[i])-x)*cos, float64(ring[i+1])-y
+		// Closest point of the segment to the origin
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l := dx*dx + dy*dy; l > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l))
+		}
+		best = math.Min(best, math.Hypot(ax+t*dx, ay+t*dy))
+	}
+	return best
+}
+
+// minInt32 returns the smaller of a and b.
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt32 returns the larger of a and b.
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,107 @@
+package geo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"math/rand"
+	"testing"
+	"time"
+
+	// The tests do not depend on the timezone database of the system
+	_ "time/tzdata"
+)
+
+func TestTimezone(t *testing.T) {
+	tests := []struct {
+		p    Point
+		want string
+	}{
+		{Point{13.7563, 100.5018}, "Asia/Bangkok"},
+		{Point{18.7883, 98.9853}, "Asia/Bangkok"},
+		{Point{17.9757, 102.6331}, "Asia/Vientiane"},
+		{Point{11.5564, 104.9282}, "Asia/Phnom_Penh"},
+		{Point{10.8231, 106.6297}, "Asia/Ho_Chi_Minh"},
+		{Point{35.6762, 139.6503}, "Asia/Tokyo"},
+		{Point{22.5726, 88.3639}, "Asia/Kolkata"},
+		{Point{48.8566, 2.3522}, "Europe/Paris"},
+		{Point{51.5074, -0.1278}, "Europe/London"},
+		{Point{40.7128, -74.0060}, "America/New_York"},
+		{Point{-33.8688, 151.2093}, "Australia/Sydney"},
+		{Point{-16.8, 179.999}, "Pacific/Fiji"},
+		// Both sides of the Thailand and Myanmar border, between Mae Sot and Myawaddy
+		{Point{16.7133, 98.5747}, "Asia/Bangkok"},
+		{Point{16.6880, 98.5089}, "Asia/Yangon"},
+		// Oceans
+		{Point{0, -150}, "Etc/GMT+10"},
+		{Point{0, 90}, "Etc/GMT-6"},
+		{Point{90, 0}, "Etc/GMT"},
+		{Point{-90, 0}, "Antarctica/McMurdo"},
+	}
+	for _, tt := range tests {
+		if got, err := Timezone(tt.p); err != nil || got != tt.want {
+			t.Errorf("Timezone(%v) = %v, %v, want %v", tt.p, got, err, tt.want)
+		}
+	}
+
+	// All points have a timezone
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		p := Point{Lat: r.Float64()*180 - 90, Lon: r.Float64()*360 - 180}
+		if got, err := Timezone(p); err != nil || got == "" {
+			t.Fatalf("Timezone(%v) = %v, %v", p, got, err)
+		}
+	}
+	if got := TimezoneRelease(); got != "2025b" {
+		t.Errorf("TimezoneRelease() = %v", got)
+	}
+}
+
+func TestTimezoneLocation(t *testing.T) {
+	loc, err := TimezoneLocation(Point{13.7563, 100.5018})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := time.Date(2021, 1, 1, 0, 0, 0, 0, loc).Zone(); offset != 7*3600 {
+		t.Errorf("TimezoneLocation() offset = %v, want 7 hours", offset)
+	}
+}
+
+func TestReadTimezones(t *testing.T) {
+	if _, err := readTimezones([]byte("not gzip")); err == nil {
+		t.Error("readTimezones(invalid data) error = nil")
+	}
+	// Data cut in the middle
+	if _, err := readTimezones(timezoneData[:len(timezoneData)/2]); err == nil {
+		t.Error("readTimezones(truncated data) error = nil")
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"polygon without rings", []byte{1, 'x', 1, 3, 'U', 'T', 'C', 1, 0}},
+		{"too many timezones", []byte{1, 'x', 0xff, 0xff, 0xff, 0xff, 0x0f}},
+		{"too many points", []byte{1, 'x', 1, 3, 'U', 'T', 'C', 1, 1, 0xff, 0xff, 0xff, 0xff, 0x0f}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(tt.data)
+		w.Close()
+		if _, err := readTimezones(buf.Bytes()); err != errInvalidTimezones {
+			t.Errorf("readTimezones(%v) error = %v", tt.name, err)
+		}
+	}
+}
+
+func BenchmarkTimezone(b *testing.B) {
+	Timezone(Point{})
+	r := rand.New(rand.NewSource(1))
+	points := make([]Point, 1000)
+	for i := range points {
+		points[i] = Point{Lat: r.Float64()*180 - 90, Lon: r.Float64()*360 - 180}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Timezone(points[i%len(points)])
+	}
+}
@@ -0,0 +1,257 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+type (
+	// Fix is a position of a GPS receiver at a time.
+	Fix struct {
+		Point
+		Time time.Time
+	}
+
+	// Trajectory is a sequence of fixes ordered by time, such as the track of a delivery vehicle.
+	Trajectory []Fix
+
+	// TripOptions are the settings of Trips.
+	TripOptions struct {
+		MaxSpeed     float64       // MaxSpeed in meters per second above which fixes are outliers, 70 (252 km/h) if 0
+		MaxGap       time.Duration // MaxGap is the longest time between fixes of a trip, 15 minutes if 0
+		StopRadius   float64       // StopRadius is the distance in meters around a stop the vehicle stays within, 50 if 0
+		StopDuration time.Duration // StopDuration is the shortest stop, 5 minutes if 0
+	}
+
+	// Stop is a place where a trajectory stayed: fixes within a radius of the first fix of the stop for some time.
+	Stop struct {
+		Point     Point     // Point is the centroid of the fixes of the stop
+		Arrival   time.Time // Arrival is the time of the first fix of the stop
+		Departure time.Time // Departure is the time of the last fix of the stop
+		First     int       // First is the index of the first fix of the stop in the trajectory
+		Last      int       // Last is the index of the last fix of the stop in the trajectory
+	}
+
+	// Trip is a part of a trajectory without gaps, with its stops.
+	Trip struct {
+		Fixes      Trajectory    // Fixes of the trip, without outliers
+		Start      time.Time     // Start is the time of the first fix
+		End        time.Time     // End is the time of the last fix
+		Distance   float64       // Distance in meters
+		MovingTime time.Duration // MovingTime is the duration of the trip out of stops
+		MaxSpeed   float64       // MaxSpeed is the highest speed in meters per second between two fixes
+		Stops      []Stop        // Stops with the indexes of their fixes in Fixes
+	}
+)
+
+// Trajectory returns the fixes of the points of the segment. Points must have a time.
+//  doc, err := geo.ReadGPX(file)
+//  trips := doc.Tracks[0].Segments[0].Trajectory().Trips(geo.TripOptions{})
+func (s GPXSegment) Trajectory() Trajectory {
+	t := make(Trajectory, len(s.Points))
+	for i, p := range s.Points {
+		t[i] = Fix{Point: p.Point(), Time: p.Time.Time}
+	}
+	return t
+}
+
+// Trajectory returns the fixes of the points of all the segments of the track.
+func (t GPXTrack) Trajectory() Trajectory {
+	var trajectory Trajectory
+	for _, s := range t.Segments {
+		trajectory = append(trajectory, s.Trajectory()...)
+	}
+	return trajectory
+}
+
+// LineString returns the line joining the fixes.
+func (t Trajectory) LineString() LineString {
+	line := make(LineString, len(t))
+	for i, f := range t {
+		line[i] = f.Point
+	}
+	return line
+}
+
+// Distance returns the length of the trajectory in meters, computed with Distance.
+func (t Trajectory) Distance() (distance float64) {
+	for i := 1; i < len(t); i++ {
+		distance += t[i-1].Distance(t[i].Point)
+	}
+	return
+}
+
+// Duration returns the time between the first and the last fix.
+func (t Trajectory) Duration() time.Duration {
+	if len(t) == 0 {
+		return 0
+	}
+	return t[len(t)-1].Time.Sub(t[0].Time)
+}
+
+// Speeds returns the speeds in meters per second between consecutive fixes:
+// speeds[i] is the speed from fix i to fix i+1.
+// The speed between two fixes at the same time is 0 if they are at the same place, +Inf otherwise.
+//  speeds := trajectory.Speeds()
+//  kmh := speeds[0] * 3.6
+func (t Trajectory) Speeds() []float64 {
+	if len(t) < 2 {
+		return nil
+	}
+	speeds := make([]float64, len(t)-1)
+	for i := range speeds {
+		speeds[i] = speed(t[i], t[i+1])
+	}
+	return speeds
+}
+
+// speed returns the speed in meters per second from fix a to fix b.
+func speed(a, b Fix) float64 {
+	distance := a.Distance(b.Point)
+	seconds := b.Time.Sub(a.Time).Seconds()
+	if seconds <= 0 {
+		if distance == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return distance / seconds
+}
+
+// RemoveOutliers returns the fixes without the outliers: fixes reached from the previous fix kept faster than maxSpeed,
+// in meters per second, such as points teleporting away from the track on a bad GPS signal.
+// Fixes earlier than the previous fix kept are outliers too, unless they are at the same place.
+// The first fix is an outlier when the vehicle would have to go faster than maxSpeed to reach the second fix,
+// while it would not from the second fix to the third one.
+//  trajectory, outliers := trajectory.RemoveOutliers(50)
+func (t Trajectory) RemoveOutliers(maxSpeed float64) (kept, outliers Trajectory) {
+	if len(t) == 0 {
+		return
+	}
+	kept = make(Trajectory, 0, len(t))
+	start := 0
+	if len(t) > 2 && speed(t[0], t[1]) > maxSpeed && speed(t[1], t[2]) <= maxSpeed {
+		outliers = append(outliers, t[0])
+		start = 1
+	}
+	kept = append(kept, t[start])
+	for _, f := range t[start+1:] {
+		if speed(kept[len(kept)-1], f) > maxSpeed {
+			outliers = append(outliers, f)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return
+}
+
+// Split splits the trajectory where the time between two fixes is longer than maxGap,
+// such as when the GPS receiver was turned off. Parts share the fixes of the trajectory.
+//  parts := trajectory.Split(15 * time.Minute)
+func (t Trajectory) Split(maxGap time.Duration) []Trajectory {
+	var parts []Trajectory
+	start := 0
+	for i := 1; i <= len(t); i++ {
+		if i == len(t) || t[i].Time.Sub(t[i-1].Time) > maxGap {
+			parts = append(parts, t[start:i])
+			start = i
+		}
+	}
+	return parts
+}
+
+// Stops returns the places where the trajectory stayed within radius meters for at least minDuration.
+// A stop starts at a fix and lasts as long as the next fixes are within radius of this fix.
+// This is the stay point detection of Li et al., Mining user similarity based on location history (2008).
+//  stops := trajectory.Stops(50, 5*time.Minute)
+func (t Trajectory) Stops(radius float64, minDuration time.Duration) []Stop {
+	var stops []Stop
+	for i := 0; i < len(t); {
+		j := i + 1
+		for j < len(t) && t[i].Distance(t[j].Point) <= radius {
+			j++
+		}
+		if t[j-1].Time.Sub(t[i].Time) < minDuration {
+			i++
+			continue
+		}
+		var v vector
+		for _, f := range t[i:j] {
+			v = v.add(pointToVector(f.Point))
+		}
+		stops = append(stops, Stop{Point: vectorToPoint(v), Arrival: t[i].Time, Departure: t[j-1].Time, First: i, Last: j - 1})
+		i = j
+	}
+	return stops
+}
+
+// Duration returns the time spent at the stop.
+func (s Stop) Duration() time.Duration {
+	return s.Departure.Sub(s.Arrival)
+}
+
+// Trips removes the outliers of the trajectory, splits it on gaps and returns the parts as trips with their stops.
+// Parts with a single fix are left out.
+//  trips := trajectory.Trips(geo.TripOptions{StopDuration: 2 * time.Minute})
+//  for _, trip := range trips {
+//      fmt.Println(trip.Start, trip.Distance, len(trip.Stops))
+//  }
+func (t Trajectory) Trips(options TripOptions) []Trip {
+	if options.MaxSpeed == 0 {
+		options.MaxSpeed = 70
+	}
+	if options.MaxGap == 0 {
+		options.MaxGap = 15 * time.Minute
+	}
+	if options.StopRadius == 0 {
+		options.StopRadius = 50
+	}
+	if options.StopDuration == 0 {
+		options.StopDuration = 5 * time.Minute
+	}
+
+	kept, _ := t.RemoveOutliers(options.MaxSpeed)
+	var trips []Trip
+	for _, fixes := range kept.Split(options.MaxGap) {
+		if len(fixes) < 2 {
+			continue
+		}
+		trip := Trip{
+			Fixes:      fixes,
+			Start:      fixes[0].Time,
+			End:        fixes[len(fixes)-1].Time,
+			Distance:   fixes.Distance(),
+			MovingTime: fixes.Duration(),
+			Stops:      fixes.Stops(options.StopRadius, options.StopDuration),
+		}
+		for _, s := range fixes.Speeds() {
+			trip.MaxSpeed = math.Max(trip.MaxSpeed, s)
+		}
+		for _, s := range trip.Stops {
+			trip.MovingTime -= s.Duration()
+		}
+		trips = append(trips, trip)
+	}
+	return trips
+}
+
+// Duration returns the time between the start and the end of the trip.
+func (t Trip) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// AverageSpeed returns the distance of the trip divided by its duration, in meters per second.
+func (t Trip) AverageSpeed() float64 {
+	if t.Duration() <= 0 {
+		return 0
+	}
+	return t.Distance / t.Duration().Seconds()
+}
+
+// MovingSpeed returns the distance of the trip divided by its moving time, in meters per second.
+func (t Trip) MovingSpeed() float64 {
+	if t.MovingTime <= 0 {
+		return 0
+	}
+	return t.Distance / t.MovingTime.Seconds()
+}
@@ -0,0 +1,199 @@
+package geo
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testTrajectory returns the fixes of a delivery: 10 minutes east at 10 m/s, a 10 minutes stop,
+// 5 minutes north with a teleporting fix, the GPS turned off for 30 minutes, then 5 minutes west.
+// Fixes are every 10 seconds.
+func testTrajectory() Trajectory {
+	start := time.Date(2021, 3, 20, 8, 0, 0, 0, time.UTC)
+	p := Point{13.7563, 100.5018}
+	var t Trajectory
+	add := func(seconds int, bearing, speed float64) {
+		for i := 0; i < seconds/10; i++ {
+			t = append(t, Fix{Point: p, Time: start})
+			p = p.Destination(bearing, 10*speed)
+			start = start.Add(10 * time.Second)
+		}
+	}
+	add(600, 90, 10)
+	stop := p
+	for i := 0; i < 60; i++ {
+		// Jitter of a few meters around the stop
+		t = append(t, Fix{Point: stop.Destination(float64(i*77%360), float64(i%5)), Time: start})
+		start = start.Add(10 * time.Second)
+	}
+	add(300, 0, 10)
+	t[len(t)-15].Point = t[len(t)-15].Destination(45, 5000)
+	start = start.Add(30 * time.Minute)
+	add(300, 270, 10)
+	return t
+}
+
+func TestTrajectory(t *testing.T) {
+	trajectory := testTrajectory()
+	if len(trajectory) != 60+60+30+30 {
+		t.Fatalf("%v fixes", len(trajectory))
+	}
+	line := trajectory.LineString()
+	if len(line) != len(trajectory) || line[10] != trajectory[10].Point {
+		t.Errorf("LineString() = %v", line)
+	}
+	if d := trajectory[:60].Distance(); math.Abs(d-5900) > 1e-6 {
+		t.Errorf("Distance() = %v, want 5900", d)
+	}
+	if d := trajectory[:60].Duration(); d != 590*time.Second {
+		t.Errorf("Duration() = %v", d)
+	}
+	speeds := trajectory[:60].Speeds()
+	for _, s := range speeds {
+		if math.Abs(s-10) > 1e-9 {
+			t.Fatalf("Speeds() = %v", speeds)
+		}
+	}
+	if len(speeds) != 59 || Trajectory(nil).Speeds() != nil || Trajectory(nil).Duration() != 0 || Trajectory(nil).Distance() != 0 {
+		t.Errorf("Speeds() = %v", speeds)
+	}
+	now := time.Now()
+	if s := (Trajectory{{Point{1, 2}, now}, {Point{1, 2}, now}, {Point{1, 3}, now}}).Speeds(); s[0] != 0 || !math.IsInf(s[1], 1) {
+		t.Errorf("Speeds(same time) = %v", s)
+	}
+}
+
+func TestRemoveOutliers(t *testing.T) {
+	trajectory := testTrajectory()
+	kept, outliers := trajectory.RemoveOutliers(70)
+	if len(outliers) != 1 || outliers[0] != trajectory[len(trajectory)-45] || len(kept) != len(trajectory)-1 {
+		t.Errorf("RemoveOutliers() = %v fixes, outliers %v", len(kept), outliers)
+	}
+
+	start := time.Date(2021, 3, 20, 8, 0, 0, 0, time.UTC)
+	fix := func(lat, lon float64, seconds int) Fix {
+		return Fix{Point{lat, lon}, start.Add(time.Duration(seconds) * time.Second)}
+	}
+	tests := []struct {
+		name     string
+		t        Trajectory
+		kept     Trajectory
+		outliers Trajectory
+	}{
+		{"empty", nil, nil, nil},
+		{"single", Trajectory{fix(13, 100, 0)}, Trajectory{fix(13, 100, 0)}, nil},
+		{
+			name:     "first fix",
+			t:        Trajectory{fix(14, 100, 0), fix(13, 100, 10), fix(13, 100.001, 20)},
+			kept:     Trajectory{fix(13, 100, 10), fix(13, 100.001, 20)},
+			outliers: Trajectory{fix(14, 100, 0)},
+		},
+		{
+			name:     "two fixes away",
+			t:        Trajectory{fix(13, 100, 0), fix(13, 100.0005, 5), fix(13.1, 100, 10), fix(13.1, 100, 20), fix(13, 100.001, 30)},
+			kept:     Trajectory{fix(13, 100, 0), fix(13, 100.0005, 5), fix(13, 100.001, 30)},
+			outliers: Trajectory{fix(13.1, 100, 10), fix(13.1, 100, 20)},
+		},
+		{
+			name:     "back in time",
+			t:        Trajectory{fix(13, 100, 0), fix(13, 100.001, 10), fix(13, 100.0005, 5), fix(13, 100.001, 5), fix(13, 100.002, 20)},
+			kept:     Trajectory{fix(13, 100, 0), fix(13, 100.001, 10), fix(13, 100.001, 5), fix(13, 100.002, 20)},
+			outliers: Trajectory{fix(13, 100.0005, 5)},
+		},
+	}
+	for _, tt := range tests {
+		kept, outliers := tt.t.RemoveOutliers(50)
+		if len(kept) != len(tt.kept) || len(kept) > 0 && !reflect.DeepEqual(kept, tt.kept) || !reflect.DeepEqual(outliers, tt.outliers) {
+			t.Errorf("%v: RemoveOutliers() = %v, %v, want %v, %v", tt.name, kept, outliers, tt.kept, tt.outliers)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	trajectory := testTrajectory()
+	parts := trajectory.Split(15 * time.Minute)
+	if len(parts) != 2 || len(parts[0]) != 150 || len(parts[1]) != 30 {
+		t.Errorf("Split() = %v parts", len(parts))
+	}
+	if parts := trajectory.Split(5 * time.Second); len(parts) != len(trajectory) {
+		t.Errorf("Split(5s) = %v parts", len(parts))
+	}
+	if parts := Trajectory(nil).Split(time.Minute); len(parts) != 0 {
+		t.Errorf("Split(empty) = %v", parts)
+	}
+}
+
+func TestStops(t *testing.T) {
+	trajectory := testTrajectory()
+	stops := trajectory.Stops(50, 5*time.Minute)
+	if len(stops) != 1 {
+		t.Fatalf("Stops() = %v", stops)
+	}
+	s := stops[0]
+	// The first fix after the stop is at the stop
+	if s.First != 60 || s.Last != 120 || s.Duration() != 600*time.Second || s.Point.Distance(trajectory[60].Point) > 3 {
+		t.Errorf("Stops() = %+v, %v", s, s.Duration())
+	}
+	if stops := trajectory.Stops(50, 11*time.Minute); len(stops) != 0 {
+		t.Errorf("Stops(11 minutes) = %v", stops)
+	}
+	// Fixes 100 meters apart are stops with a large radius
+	if stops := trajectory[:60].Stops(250, 20*time.Second); len(stops) != 20 || stops[1].First != 3 || stops[1].Last != 5 {
+		t.Errorf("Stops(250 meters) = %+v", stops)
+	}
+}
+
+func TestTrips(t *testing.T) {
+	trajectory := testTrajectory()
+	trips := trajectory.Trips(TripOptions{})
+	if len(trips) != 2 {
+		t.Fatalf("Trips() = %v trips", len(trips))
+	}
+	trip := trips[0]
+	if len(trip.Fixes) != 149 || !trip.Start.Equal(trajectory[0].Time) || !trip.End.Equal(trajectory[149].Time) || trip.Duration() != 1490*time.Second {
+		t.Errorf("Trips()[0] = %v fixes from %v to %v", len(trip.Fixes), trip.Start, trip.End)
+	}
+	// The outlier is replaced by a segment of 200 meters in 20 seconds
+	if len(trip.Stops) != 1 || trip.MovingTime != 890*time.Second || math.Abs(trip.MaxSpeed-10) > 1e-6 {
+		t.Errorf("Trips()[0] = %v stops, moving %v, max speed %v", len(trip.Stops), trip.MovingTime, trip.MaxSpeed)
+	}
+	if d := trip.Distance; d < 5900+100+2900 || d > 5900+100+2900+300 {
+		t.Errorf("Trips()[0] distance = %v", d)
+	}
+	if s := trip.AverageSpeed(); math.Abs(s-trip.Distance/1490) > 1e-9 {
+		t.Errorf("AverageSpeed() = %v", s)
+	}
+	if s := trip.MovingSpeed(); math.Abs(s-trip.Distance/890) > 1e-9 {
+		t.Errorf("MovingSpeed() = %v", s)
+	}
+	if trip := trips[1]; len(trip.Fixes) != 30 || len(trip.Stops) != 0 || trip.MovingTime != trip.Duration() || math.Abs(trip.Distance-2900) > 1e-6 {
+		t.Errorf("Trips()[1] = %+v", trip)
+	}
+
+	if trips := trajectory.Trips(TripOptions{MaxGap: time.Hour, StopDuration: 20 * time.Minute}); len(trips) != 1 || len(trips[0].Stops) != 0 {
+		t.Errorf("Trips(1 hour gaps) = %v trips", len(trips))
+	}
+	if trips := (Trajectory{{Point{1, 2}, time.Now()}}).Trips(TripOptions{}); len(trips) != 0 {
+		t.Errorf("Trips(single fix) = %v", trips)
+	}
+	if (Trip{}).AverageSpeed() != 0 || (Trip{}).MovingSpeed() != 0 {
+		t.Error("speeds of an empty trip are not 0")
+	}
+}
+
+func TestGPXTrajectory(t *testing.T) {
+	g, err := ReadGPX(strings.NewReader(testGPX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trajectory := g.Tracks[0].Trajectory()
+	if len(trajectory) != 3 || trajectory[1].Point != (Point{13.7666, 100.6069}) || !trajectory[1].Time.Equal(time.Date(2021, 3, 20, 23, 10, 10, 5e8, time.UTC)) {
+		t.Errorf("Trajectory() = %v", trajectory)
+	}
+	if s := g.Tracks[0].Segments[0].Trajectory().Speeds(); len(s) != 1 || math.Abs(s[0]-trajectory[0].Distance(trajectory[1].Point)/5.5) > 1e-9 {
+		t.Errorf("Speeds() = %v", s)
+	}
+}
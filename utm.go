@@ -0,0 +1,367 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	// TransverseMercator is a transverse Mercator projection of an ellipsoid, such as UTM zones.
+	// Formulas are Krüger series to the 6th order, accurate to a few nanometers within 4000 km of the central meridian.
+	// Details https://arxiv.org/abs/1002.1417
+	TransverseMercator struct {
+		Ellipsoid        Ellipsoid
+		CentralMeridian  float64 // CentralMeridian is the longitude of origin in degrees
+		LatitudeOfOrigin float64 // LatitudeOfOrigin is the latitude in degrees where the northing is FalseNorthing
+		Scale            float64 // Scale factor on the central meridian
+		FalseEasting     float64 // FalseEasting in meters added to the eastings
+		FalseNorthing    float64 // FalseNorthing in meters added to the northings
+	}
+
+	// UTM is a position in the Universal Transverse Mercator coordinate system on the WGS84 ellipsoid.
+	// Details https://en.wikipedia.org/wiki/Universal_Transverse_Mercator_coordinate_system
+	UTM struct {
+		Zone     int     // Zone from 1 to 60
+		North    bool    // North is true in the northern hemisphere
+		Easting  float64 // Easting in meters, 500000 on the central meridian of the zone
+		Northing float64 // Northing in meters from the equator, plus 10000000 in the southern hemisphere
+	}
+)
+
+var (
+	// ErrInvalidUTM is returned for UTM coordinates out of range or malformed.
+	ErrInvalidUTM = errors.New("Invalid UTM coordinates")
+	// ErrInvalidMGRS is returned for malformed MGRS references.
+	ErrInvalidMGRS = errors.New("Invalid MGRS reference")
+	// ErrOutsideUTM is returned for points beyond latitudes 84°N and 80°S, covered by UPS instead of UTM.
+	ErrOutsideUTM = errors.New("Latitude outside UTM limits")
+)
+
+const (
+	// utmBands are the latitude bands of 8 degrees from 80°S, band X is 12 degrees.
+	utmBands = "CDEFGHJKLMNPQRSTUVWX"
+	// mgrsColumns are the column letters of 100 km squares, for zones 1, 2 and 3 in turns.
+	mgrsColumns = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	// mgrsRows are the row letters of 100 km squares, shifted by 5 letters in even zones.
+	mgrsRows = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// UTMProjection returns the transverse Mercator projection of a UTM zone.
+func UTMProjection(zone int, north bool) TransverseMercator {
+	tm := TransverseMercator{
+		Ellipsoid:       WGS84,
+		CentralMeridian: float64(zone*6 - 183),
+		Scale:           0.9996,
+		FalseEasting:    500000,
+	}
+	if !north {
+		tm.FalseNorthing = 10000000
+	}
+	return tm
+}
+
+// Forward projects the point, it returns its easting and northing in meters.
+func (tm TransverseMercator) Forward(p Point) (easting, northing float64) {
+	a, _, _ := tm.series()
+	xi, eta := tm.xiEta(p.Lat, angleDiff(tm.CentralMeridian, p.Lon))
+	easting = tm.Scale*a*eta + tm.FalseEasting
+	northing = tm.Scale*a*(xi-tm.originXi()) + tm.FalseNorthing
+	return
+}
+
+// Inverse returns the point at the easting and northing in meters.
+func (tm TransverseMercator) Inverse(easting, northing float64) Point {
+	a, _, beta := tm.series()
+	e2 := tm.Ellipsoid.F * (2 - tm.Ellipsoid.F)
+	e := math.Sqrt(e2)
+
+	eta := (easting - tm.FalseEasting) / (tm.Scale * a)
+	xi := (northing-tm.FalseNorthing)/(tm.Scale*a) + tm.originXi()
+	xi1, eta1 := xi, eta
+	for j := 1; j <= 6; j++ {
+		xi1 -= beta[j-1] * math.Sin(2*float64(j)*xi) * math.Cosh(2*float64(j)*eta)
+		eta1 -= beta[j-1] * math.Cos(2*float64(j)*xi) * math.Sinh(2*float64(j)*eta)
+	}
+	sinhEta1 := math.Sinh(eta1)
+	sinXi1, cosXi1 := math.Sincos(xi1)
+	tau1 := sinXi1 / math.Sqrt(sinhEta1*sinhEta1+cosXi1*cosXi1)
+
+	// Newton's method to find tan of the latitude from tan of the conformal latitude
+	tau := tau1
+	for i := 0; i < 10; i++ {
+		sigma := math.Sinh(e * math.Atanh(e*tau/math.Sqrt(1+tau*tau)))
+		taui := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+		delta := (tau1 - taui) / math.Sqrt(1+taui*taui) * (1 + (1-e2)*tau*tau) / ((1 - e2) * math.Sqrt(1+tau*tau))
+		tau += delta
+		if math.Abs(delta) < 1e-14 {
+			break
+		}
+	}
+	return Point{
+		Lat: toDegrees(math.Atan(tau)),
+		Lon: normalizeLongitude(tm.CentralMeridian + toDegrees(math.Atan2(sinhEta1, cosXi1))),
+	}
+}
+
+// series returns the rectifying radius A and the Krüger coefficients of the ellipsoid.
+func (tm TransverseMercator) series() (a float64, alpha, beta [6]float64) {
+	f := tm.Ellipsoid.F
+	n := f / (2 - f)
+	n2, n3, n4, n5, n6 := n*n, n*n*n, n*n*n*n, n*n*n*n*n, n*n*n*n*n*n
+	a = tm.Ellipsoid.A / (1 + n) * (1 + n2/4 + n4/64 + n6/256)
+	alpha = [6]float64{
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+		13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+		61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+		49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+		34729*n5/80640 - 3418889*n6/1995840,
+		212378941 * n6 / 319334400,
+	}
+	beta = [6]float64{
+		n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+		n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+		17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+		4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+		4583*n5/161280 - 108847*n6/3991680,
+		20648693 * n6 / 638668800,
+	}
+	return
+}
+
+// xiEta returns the transverse Mercator coordinates of a point on the sphere of radius A,
+// lambda is the longitude in degrees from the central meridian.
+func (tm TransverseMercator) xiEta(lat, lambda float64) (xi, eta float64) {
+	_, alpha, _ := tm.series()
+	e := math.Sqrt(tm.Ellipsoid.F * (2 - tm.Ellipsoid.F))
+
+	// Conformal latitude and spherical transverse Mercator
+	tau := math.Tan(toRadians(lat))
+	sigma := math.Sinh(e * math.Atanh(e*tau/math.Sqrt(1+tau*tau)))
+	tau1 := tau*math.Sqrt(1+sigma*sigma) - sigma*math.Sqrt(1+tau*tau)
+	sinLambda, cosLambda := math.Sincos(toRadians(lambda))
+	xi1 := math.Atan2(tau1, cosLambda)
+	eta1 := math.Asinh(sinLambda / math.Sqrt(tau1*tau1+cosLambda*cosLambda))
+	if math.Abs(lat) == 90 {
+		xi1, eta1 = math.Copysign(math.Pi/2, lat), 0
+	}
+
+	xi, eta = xi1, eta1
+	for j := 1; j <= 6; j++ {
+		xi += alpha[j-1] * math.Sin(2*float64(j)*xi1) * math.Cosh(2*float64(j)*eta1)
+		eta += alpha[j-1] * math.Cos(2*float64(j)*xi1) * math.Sinh(2*float64(j)*eta1)
+	}
+	return
+}
+
+// originXi returns the xi coordinate of the latitude of origin on the central meridian.
+func (tm TransverseMercator) originXi() float64 {
+	if tm.LatitudeOfOrigin == 0 {
+		return 0
+	}
+	xi, _ := tm.xiEta(tm.LatitudeOfOrigin, 0)
+	return xi
+}
+
+// UTMZone returns the UTM zone of the point, with the exceptions of southwest Norway and Svalbard.
+func UTMZone(p Point) int {
+	lon := normalizeLongitude(p.Lon)
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 1
+	}
+	switch {
+	case p.Lat >= 56 && p.Lat < 64 && lon >= 3 && lon < 12:
+		zone = 32
+	case p.Lat >= 72 && lon >= 0 && lon < 42:
+		zone = 31 + 2*int((lon+3)/12)
+	}
+	return zone
+}
+
+// UTM returns the UTM coordinates of the point in its zone.
+//  u, err := geo.Point{Lat: 13.7563, Lon: 100.5018}.UTM()
+//  fmt.Println(u) // 47N 662366.598 1521280.655
+func (p Point) UTM() (UTM, error) {
+	return p.UTMInZone(UTMZone(p))
+}
+
+// UTMInZone returns the UTM coordinates of the point in a zone that may not be its own,
+// such as a neighbor zone to work with a single grid across a zone boundary.
+// Scale distortion grows far from the central meridian of the zone.
+//  u, err := geo.Point{Lat: 13.7563, Lon: 102.5}.UTMInZone(47)
+func (p Point) UTMInZone(zone int) (u UTM, err error) {
+	if err = p.Validate(); err != nil {
+		return
+	}
+	if p.Lat < -80 || p.Lat > 84 {
+		return u, ErrOutsideUTM
+	}
+	if zone < 1 || zone > 60 {
+		return u, ErrInvalidUTM
+	}
+	u = UTM{Zone: zone, North: p.Lat >= 0}
+	u.Easting, u.Northing = UTMProjection(zone, u.North).Forward(p)
+	return
+}
+
+// Point returns the point at the UTM coordinates.
+//  p, err := geo.UTM{Zone: 47, North: true, Easting: 662366.598, Northing: 1521280.655}.Point()
+func (u UTM) Point() (Point, error) {
+	if err := u.validate(); err != nil {
+		return Point{}, err
+	}
+	return UTMProjection(u.Zone, u.North).Inverse(u.Easting, u.Northing), nil
+}
+
+// String returns the UTM coordinates with a millimeter precision and N or S for the hemisphere,
+// such as 47N 662366.598 1521280.655.
+func (u UTM) String() string {
+	hemisphere := "S"
+	if u.North {
+		hemisphere = "N"
+	}
+	return strconv.Itoa(u.Zone) + hemisphere + " " +
+		strconv.FormatFloat(u.Easting, 'f', 3, 64) + " " + strconv.FormatFloat(u.Northing, 'f', 3, 64)
+}
+
+// ParseUTM parses UTM coordinates as written by String: zone, hemisphere letter N or S, easting and northing.
+// Spaces between zone and hemisphere are optional. Latitude band letters are not accepted,
+// as band S is in the northern hemisphere.
+//  u, err := geo.ParseUTM("47N 662366.598 1521280.655")
+func ParseUTM(s string) (u UTM, err error) {
+	fields := strings.Fields(strings.ToUpper(s))
+	if len(fields) == 4 {
+		fields = []string{fields[0] + fields[1], fields[2], fields[3]}
+	}
+	if len(fields) != 3 || len(fields[0]) < 2 {
+		return u, ErrInvalidUTM
+	}
+	zone := fields[0]
+	switch zone[len(zone)-1] {
+	case 'N':
+		u.North = true
+	case 'S':
+	default:
+		return u, ErrInvalidUTM
+	}
+	if u.Zone, err = strconv.Atoi(zone[:len(zone)-1]); err != nil {
+		return u, ErrInvalidUTM
+	}
+	if u.Easting, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return u, ErrInvalidUTM
+	}
+	if u.Northing, err = strconv.ParseFloat(fields[2], 64); err != nil {
+		return u, ErrInvalidUTM
+	}
+	return u, u.validate()
+}
+
+// validate checks the zone and that the coordinates are not too far from the zone.
+func (u UTM) validate() error {
+	if u.Zone < 1 || u.Zone > 60 || math.IsNaN(u.Easting) || u.Easting < 0 || u.Easting > 1000000 ||
+		math.IsNaN(u.Northing) || u.Northing < 0 || u.Northing > 10000000 {
+		return ErrInvalidUTM
+	}
+	return nil
+}
+
+// MGRS returns the Military Grid Reference System reference of the point with precision digits
+// for easting and northing, from 0 for 100 km squares to 5 for 1 m squares.
+// Coordinates are truncated, so the reference is the square holding the point.
+// Details https://en.wikipedia.org/wiki/Military_Grid_Reference_System
+//  ref, err := geo.Point{Lat: 13.7563, Lon: 100.5018}.MGRS(5) // 47PPR6236621280
+func (p Point) MGRS(precision int) (string, error) {
+	if precision < 0 || precision > 5 {
+		return "", ErrInvalidMGRS
+	}
+	u, err := p.UTM()
+	if err != nil {
+		return "", err
+	}
+	band := utmBands[int(math.Min(19, math.Floor((p.Lat+80)/8)))]
+
+	// Rounding to 1 micrometer avoids 99999.999999 becoming 99999 instead of 100000
+	easting := int(math.Floor(math.Round(u.Easting*1e6) / 1e6))
+	northing := int(math.Floor(math.Round(u.Northing*1e6) / 1e6))
+	column := mgrsColumns[(u.Zone-1)%3*8+easting/100000-1]
+	row := mgrsRows[(northing/100000+(u.Zone+1)%2*5)%20]
+
+	ref := strconv.Itoa(u.Zone) + string(band) + string(column) + string(row)
+	if precision > 0 {
+		div := int(math.Pow10(5 - precision))
+		ref += fmt.Sprintf("%0*d%0*d", precision, easting%100000/div, precision, northing%100000/div)
+	}
+	return ref, nil
+}
+
+// ParseMGRS parses a MGRS reference, with or without spaces. It returns the UTM coordinates of the
+// southwest corner of the square and the size in meters of the square, from 1 m to 100 km.
+// Add half of the size to easting and northing to get the center of the square.
+//  u, size, err := geo.ParseMGRS("47P PR 62366 21280")
+//  p, err := u.Point()
+func ParseMGRS(s string) (u UTM, size float64, err error) {
+	ref := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+
+	i := 0
+	for i < len(ref) && i < 2 && ref[i] >= '0' && ref[i] <= '9' {
+		i++
+	}
+	if i == 0 || len(ref) < i+3 {
+		return u, 0, ErrInvalidMGRS
+	}
+	u.Zone, _ = strconv.Atoi(ref[:i])
+	bandIndex := strings.IndexByte(utmBands, ref[i])
+	columnIndex := strings.IndexByte(mgrsColumns, ref[i+1])
+	rowIndex := strings.IndexByte(mgrsRows, ref[i+2])
+	digits := ref[i+3:]
+	if u.Zone < 1 || u.Zone > 60 || bandIndex < 0 || columnIndex < 0 || rowIndex < 0 || len(digits)%2 == 1 || len(digits) > 10 {
+		return u, 0, ErrInvalidMGRS
+	}
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return u, 0, ErrInvalidMGRS
+		}
+	}
+
+	// Column letters of the zone
+	column := columnIndex - (u.Zone-1)%3*8 + 1
+	if column < 1 || column > 8 {
+		return u, 0, ErrInvalidMGRS
+	}
+	u.North = bandIndex >= 10
+	precision := len(digits) / 2
+	size = math.Pow10(5 - precision)
+	var e, n float64
+	if precision > 0 {
+		e, _ = strconv.ParseFloat(digits[:precision], 64)
+		n, _ = strconv.ParseFloat(digits[precision:], 64)
+	}
+	u.Easting = float64(column)*100000 + e*size
+
+	// Row letters repeat every 2000 km, the band tells which cycle it is.
+	// The southern edge of the band is lowest on the central meridian in the north, at the zone edges in the south.
+	row := (rowIndex - (u.Zone+1)%2*5 + 20) % 20
+	bandLat := float64(bandIndex*8 - 80)
+	tm := UTMProjection(u.Zone, u.North)
+	minNorthing := math.Inf(1)
+	for _, dLon := range []float64{-3, 0, 3} {
+		_, y := tm.Forward(Point{Lat: bandLat, Lon: float64(u.Zone*6-183) + dLon})
+		minNorthing = math.Min(minNorthing, y)
+	}
+	minNorthing = math.Floor(minNorthing/100000) * 100000
+	northing := float64(row) * 100000
+	for northing < minNorthing {
+		northing += 2000000
+	}
+	u.Northing = northing + n*size
+	return u, size, u.validate()
+}
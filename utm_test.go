@@ -0,0 +1,255 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPoint_UTM(t *testing.T) {
+	// The first and the equator values match GeographicLib GeoConvert
+	tests := []struct {
+		p    Point
+		want UTM
+	}{
+		{Point{33.3, 44.4}, UTM{38, true, 444140.545, 3684706.356}},
+		{Point{13.7563, 100.5018}, UTM{47, true, 662366.598, 1521280.655}},
+		{Point{-33.8688, 151.2093}, UTM{56, false, 334368.634, 6250948.345}},
+		{Point{0, 0}, UTM{31, true, 166021.443, 0}},
+		{Point{84, 0}, UTM{31, true, 465005.345, 9329005.182}},
+		{Point{-79.9, -179.9}, UTM{1, false, 443247.872, 1128161.373}},
+		// Norway and Svalbard
+		{Point{60.39, 5.32}, UTM{32, true, 297230.220, 6700510.175}},
+		{Point{78.22, 15.65}, UTM{33, true, 514813.527, 8683004.153}},
+	}
+	for _, tt := range tests {
+		got, err := tt.p.UTM()
+		if err != nil || got.Zone != tt.want.Zone || got.North != tt.want.North ||
+			math.Abs(got.Easting-tt.want.Easting) > 1e-3 || math.Abs(got.Northing-tt.want.Northing) > 1e-3 {
+			t.Errorf("%v.UTM() = %v, %v, want %v", tt.p, got, err, tt.want)
+		}
+		p, err := got.Point()
+		if err != nil || p.Distance(tt.p) > 1e-6 {
+			t.Errorf("%v.Point() = %v, %v, want %v", got, p, err, tt.p)
+		}
+	}
+
+	for _, p := range []Point{{84.1, 0}, {-80.1, 0}, {91, 0}} {
+		if _, err := p.UTM(); err == nil {
+			t.Errorf("%v.UTM() error = nil", p)
+		}
+	}
+	if _, err := (Point{13.7563, 100.5018}).UTMInZone(61); err != ErrInvalidUTM {
+		t.Errorf("UTMInZone(61) error = %v", err)
+	}
+}
+
+func TestUTM_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		p := Point{Lat: r.Float64()*164 - 80, Lon: r.Float64()*360 - 180}
+		u, err := p.UTM()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q, _ := u.Point(); q.Distance(p) > 1e-6 {
+			t.Fatalf("%v.UTM().Point() = %v", p, q)
+		}
+	}
+
+	// Thailand spans zones 47 and 48, points of zone 48 can use zone 47
+	p := Point{13.7, 102.6}
+	u, err := p.UTMInZone(47)
+	if err != nil || u.Zone != 47 || u.Easting < 833978 {
+		t.Errorf("UTMInZone(47) = %v, %v", u, err)
+	}
+	if q, _ := u.Point(); q.Distance(p) > 1e-6 {
+		t.Errorf("UTMInZone(47).Point() = %v, want %v", q, p)
+	}
+}
+
+func TestUTMZone(t *testing.T) {
+	tests := []struct {
+		p    Point
+		want int
+	}{
+		{Point{13.7, 100.5}, 47},
+		{Point{13.7, 102.5}, 48},
+		{Point{0, -180}, 1},
+		{Point{0, 180}, 1},
+		{Point{0, 179.9}, 60},
+		{Point{58, 2}, 31},
+		{Point{56, 3}, 32},
+		{Point{63.9, 11.9}, 32},
+		{Point{64, 5}, 31},
+		{Point{78, 8.9}, 31},
+		{Point{78, 9}, 33},
+		{Point{78, 25}, 35},
+		{Point{78, 40}, 37},
+		{Point{78, 42}, 38},
+		{Point{71.9, 8}, 32},
+	}
+	for _, tt := range tests {
+		if got := UTMZone(tt.p); got != tt.want {
+			t.Errorf("UTMZone(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestParseUTM(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    UTM
+		wantErr bool
+	}{
+		{"47N 662366.598 1521280.655", UTM{47, true, 662366.598, 1521280.655}, false},
+		{"56 s 334368 6250948", UTM{56, false, 334368, 6250948}, false},
+		{"1N 500000 0", UTM{1, true, 500000, 0}, false},
+		{"47P 662366 1521280", UTM{}, true},
+		{"61N 500000 0", UTM{}, true},
+		{"47N 662366", UTM{}, true},
+		{"47N 662366 -1", UTM{}, true},
+		{"47N abc 1521280", UTM{}, true},
+		{"N 662366 1521280", UTM{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUTM(tt.s)
+		if (err != nil) != tt.wantErr || !tt.wantErr && got != tt.want {
+			t.Errorf("ParseUTM(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+	u := UTM{47, true, 662366.598, 1521280.655}
+	if got, _ := ParseUTM(u.String()); got != u {
+		t.Errorf("ParseUTM(%q) = %v", u.String(), got)
+	}
+}
+
+func TestPoint_MGRS(t *testing.T) {
+	tests := []struct {
+		p         Point
+		precision int
+		want      string
+	}{
+		{Point{13.7563, 100.5018}, 5, "47PPR6236621280"},
+		{Point{13.7563, 100.5018}, 3, "47PPR623212"},
+		{Point{13.7563, 100.5018}, 0, "47PPR"},
+		{Point{33.333333333333, 44.4}, 2, "38SMB4488"},
+		{Point{dms(38, 53, 22.08), -dms(77, 2, 6.86)}, 4, "18SUJ23480647"},
+		{Point{48.24949, 16.41450}, 1, "33UXP04"},
+		{Point{-33.8688, 151.2093}, 5, "56HLH3436850948"},
+		{Point{0, 0}, 5, "31NAA6602100000"},
+		{Point{60.39, 5.32}, 5, "32VKN9723000510"},
+		{Point{78.22, 15.65}, 5, "33XWG1481383004"},
+		{Point{84, 0}, 5, "31XDP6500529005"},
+		{Point{-79.9, -179.9}, 5, "1CDM4324728161"},
+	}
+	for _, tt := range tests {
+		got, err := tt.p.MGRS(tt.precision)
+		if err != nil || got != tt.want {
+			t.Errorf("%v.MGRS(%v) = %v, %v, want %v", tt.p, tt.precision, got, err, tt.want)
+		}
+	}
+	if _, err := (Point{13.7563, 100.5018}).MGRS(6); err != ErrInvalidMGRS {
+		t.Errorf("MGRS(6) error = %v", err)
+	}
+	if _, err := (Point{85, 0}).MGRS(5); err != ErrOutsideUTM {
+		t.Errorf("MGRS() beyond 84°N error = %v", err)
+	}
+}
+
+func TestParseMGRS(t *testing.T) {
+	// Centers of squares from the MGRS reference implementations
+	tests := []struct {
+		s    string
+		size float64
+		want Point
+	}{
+		{"38SMB4488", 1000, Point{33.33424, 44.40363}},
+		{"33UXP04", 10000, Point{48.24951, 16.41444}},
+		{"24XWT783908", 100, Point{83.62778, -32.66434}},
+		{"11SPA7234911844", 1, Point{36.23613, -115.08209}},
+		{"18S UJ 23483 06479", 1, Point{38.88947, -77.03524}},
+		{"47ppr6236621280", 1, Point{13.7563, 100.5018}},
+		{"56HLH3436850948", 1, Point{-33.8688, 151.2093}},
+		{"1CDM4324728161", 1, Point{-79.9, -179.9}},
+	}
+	for _, tt := range tests {
+		u, size, err := ParseMGRS(tt.s)
+		if err != nil || size != tt.size {
+			t.Errorf("ParseMGRS(%q) = %v, %v, %v", tt.s, u, size, err)
+			continue
+		}
+		u.Easting += size / 2
+		u.Northing += size / 2
+		p, _ := u.Point()
+		if p.Distance(tt.want) > 2 {
+			t.Errorf("ParseMGRS(%q) center = %v, want %v", tt.s, p, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "47P", "47PPR123", "47PIR", "47PPW", "47IPR", "61PPR", "47PPR12345678901", "47PPR12a4", "47PAR"} {
+		if _, _, err := ParseMGRS(s); err == nil {
+			t.Errorf("ParseMGRS(%q) error = nil", s)
+		}
+	}
+
+	// Points near the edges of bands and zones, in both hemispheres
+	for _, lat := range []float64{-79.999, -72.001, -64.001, -63.999, -8.001, -0.001, 0.001, 7.999, 8.001, 55.999, 71.999, 72.001, 83.999} {
+		for _, lon := range []float64{-179.999, -89.96, -87.001, -86.999, -0.001, 0.001, 2.999, 3.001, 11.999, 179.999} {
+			p := Point{lat, lon}
+			ref, err := p.MGRS(5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, size, err := ParseMGRS(ref)
+			if err != nil {
+				t.Fatalf("ParseMGRS(%q) error = %v", ref, err)
+			}
+			u.Easting += size / 2
+			u.Northing += size / 2
+			if got, _ := u.Point(); got.Distance(p) > 2 {
+				t.Errorf("ParseMGRS(%v.MGRS(5) = %q) = %v", p, ref, got)
+			}
+		}
+	}
+
+	// References of random points hold the points
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		p := Point{Lat: r.Float64()*164 - 80, Lon: r.Float64()*360 - 180}
+		precision := i % 6
+		ref, err := p.MGRS(precision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, size, err := ParseMGRS(ref)
+		if err != nil {
+			t.Fatalf("ParseMGRS(%q) error = %v", ref, err)
+		}
+		pu, _ := p.UTM()
+		if pu.Zone != u.Zone || pu.Easting < u.Easting || pu.Easting >= u.Easting+size ||
+			pu.Northing < u.Northing || pu.Northing >= u.Northing+size {
+			t.Fatalf("ParseMGRS(%q) = %v, %v does not hold %v", ref, u, size, pu)
+		}
+	}
+}
+
+func TestTransverseMercator(t *testing.T) {
+	// Example of the Ordnance Survey guide to coordinate systems in Great Britain
+	tm := TransverseMercator{
+		Ellipsoid:        Airy1830,
+		CentralMeridian:  -2,
+		LatitudeOfOrigin: 49,
+		Scale:            0.9996012717,
+		FalseEasting:     400000,
+		FalseNorthing:    -100000,
+	}
+	p := Point{dms(52, 39, 27.2531), dms(1, 43, 4.5177)}
+	e, n := tm.Forward(p)
+	if math.Abs(e-651409.903) > 1e-3 || math.Abs(n-313177.270) > 1e-3 {
+		t.Errorf("Forward() = %v, %v, want 651409.903, 313177.270", e, n)
+	}
+	if q := tm.Inverse(e, n); q.Distance(p) > 1e-6 {
+		t.Errorf("Inverse() = %v, want %v", q, p)
+	}
+}
@@ -0,0 +1,375 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+)
+
+// WKB geometry type codes
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// PostGIS EWKB flags
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// ErrInvalidWKB is returned when parsing malformed WKB data.
+var ErrInvalidWKB = errors.New("Invalid WKB")
+
+// wkbReader reads WKB data and checks we never read after the end.
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+	depth int
+}
+
+// MarshalWKB returns the Well Known Binary representation of the geometry with the given byte order.
+// Coordinates are written longitude first.
+//  data, err := geo.MarshalWKB(geo.Point{Lat: 13.76, Lon: 100.5}, binary.LittleEndian)
+func MarshalWKB(g Geometry, order binary.ByteOrder) ([]byte, error) {
+	var b bytes.Buffer
+	err := writeWKB(&b, g, order, 0)
+	return b.Bytes(), err
+}
+
+// MarshalEWKB returns the PostGIS Extended Well Known Binary representation of the geometry with the SRID.
+//  data, err := geo.MarshalEWKB(geo.Point{Lat: 13.76, Lon: 100.5}, 4326, binary.LittleEndian)
+func MarshalEWKB(g Geometry, srid int, order binary.ByteOrder) ([]byte, error) {
+	var b bytes.Buffer
+	err := writeWKB(&b, g, order, srid)
+	return b.Bytes(), err
+}
+
+// UnmarshalWKB parses WKB or PostGIS EWKB data. It returns the SRID of EWKB data, or 0.
+// Z and M coordinates, in EWKB or ISO WKB flavors, are ignored.
+// Empty points, written with NaN coordinates, are returned as empty MultiPoints as UnmarshalWKT does.
+//  g, srid, err := geo.UnmarshalWKB(data)
+func UnmarshalWKB(data []byte) (g Geometry, srid int, err error) {
+	r := &wkbReader{data: data}
+	g, srid, err = r.geometry()
+	if err == nil && r.pos != len(data) {
+		err = ErrInvalidWKB
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return
+}
+
+// UnmarshalWKBHex parses WKB or EWKB data encoded in hexadecimal, as returned by PostGIS for geometry columns.
+//  g, srid, err := geo.UnmarshalWKBHex("0101000020E6100000000000000020594085EB51B81E852B40")
+func UnmarshalWKBHex(s string) (g Geometry, srid int, err error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, 0, ErrInvalidWKB
+	}
+	return UnmarshalWKB(data)
+}
+
+// writeWKB writes the geometry. SRID is written in the header if not 0.
+func writeWKB(b *bytes.Buffer, g Geometry, order binary.ByteOrder, srid int) error {
+	if order == binary.BigEndian {
+		b.WriteByte(0)
+	} else {
+		b.WriteByte(1)
+	}
+	var code uint32
+	switch g.(type) {
+	case Point:
+		code = wkbPoint
+	case LineString:
+		code = wkbLineString
+	case Polygon:
+		code = wkbPolygon
+	case MultiPoint:
+		code = wkbMultiPoint
+	case MultiLineString:
+		code = wkbMultiLineString
+	case MultiPolygon:
+		code = wkbMultiPolygon
+	case GeometryCollection:
+		code = wkbGeometryCollection
+	default:
+		return ErrInvalidWKB
+	}
+	if srid != 0 {
+		code |= ewkbSRID
+	}
+	writeUint32(b, order, code)
+	if srid != 0 {
+		writeUint32(b, order, uint32(srid))
+	}
+
+	switch geom := g.(type) {
+	case Point:
+		writeWKBPoint(b, order, geom)
+	case LineString:
+		writeWKBPoints(b, order, geom, false)
+	case Polygon:
+		writeWKBPolygon(b, order, geom)
+	case MultiPoint:
+		writeUint32(b, order, uint32(len(geom)))
+		for _, p := range geom {
+			writeWKB(b, p, order, 0)
+		}
+	case MultiLineString:
+		writeUint32(b, order, uint32(len(geom)))
+		for _, l := range geom {
+			writeWKB(b, l, order, 0)
+		}
+	case MultiPolygon:
+		writeUint32(b, order, uint32(len(geom)))
+		for _, p := range geom {
+			writeWKB(b, p, order, 0)
+		}
+	case GeometryCollection:
+		writeUint32(b, order, uint32(len(geom)))
+		for _, child := range geom {
+			if err := writeWKB(b, child, order, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeWKBPoint writes the coordinates of a point.
+func writeWKBPoint(b *bytes.Buffer, order binary.ByteOrder, p Point) {
+	var buf [16]byte
+	order.PutUint64(buf[:8], math.Float64bits(p.Lon))
+	order.PutUint64(buf[8:], math.Float64bits(p.Lat))
+	b.Write(buf[:])
+}
+
+// writeWKBPoints writes the number of points then the points, closing the ring if asked.
+func writeWKBPoints(b *bytes.Buffer, order binary.ByteOrder, points []Point, closed bool) {
+	closing := closed && len(points) > 0 && points[0] != points[len(points)-1]
+	n := len(points)
+	if closing {
+		n++
+	}
+	writeUint32(b, order, uint32(n))
+	for _, p := range points {
+		writeWKBPoint(b, order, p)
+	}
+	if closing {
+		writeWKBPoint(b, order, points[0])
+	}
+}
+
+// writeWKBPolygon writes the rings of the polygon.
+func writeWKBPolygon(b *bytes.Buffer, order binary.ByteOrder, p Polygon) {
+	writeUint32(b, order, uint32(len(p)))
+	for _, r := range p {
+		writeWKBPoints(b, order, r, true)
+	}
+}
+
+// writeUint32 writes an integer with the byte order.
+func writeUint32(b *bytes.Buffer, order binary.ByteOrder, v uint32) {
+	var buf [4]byte
+	order.PutUint32(buf[:], v)
+	b.Write(buf[:])
+}
+
+// geometry reads a geometry with its header.
+func (r *wkbReader) geometry() (g Geometry, srid int, err error) {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > wktMaxDepth || r.pos >= len(r.data) {
+		return nil, 0, ErrInvalidWKB
+	}
+	switch r.data[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, 0, ErrInvalidWKB
+	}
+	r.pos++
+
+	code, err := r.uint32()
+	if err != nil {
+		return
+	}
+	dims := 2
+	if code&ewkbZ != 0 {
+		dims++
+	}
+	if code&ewkbM != 0 {
+		dims++
+	}
+	if code&ewkbSRID != 0 {
+		var s uint32
+		if s, err = r.uint32(); err != nil {
+			return
+		}
+		srid = int(int32(s))
+	}
+	code &^= ewkbZ | ewkbM | ewkbSRID
+	// ISO WKB adds 1000 for Z, 2000 for M and 3000 for ZM
+	switch code / 1000 {
+	case 1, 2:
+		dims++
+	case 3:
+		dims += 2
+	}
+	code %= 1000
+
+	switch code {
+	case wkbPoint:
+		var p Point
+		p, err = r.point(dims)
+		g = p
+		if math.IsNaN(p.Lat) && math.IsNaN(p.Lon) {
+			g = MultiPoint{}
+		}
+	case wkbLineString:
+		var points []Point
+		points, err = r.points(dims)
+		g = LineString(points)
+	case wkbPolygon:
+		g, err = r.polygon(dims)
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		g, err = r.collection(code)
+	default:
+		err = ErrInvalidWKB
+	}
+	return
+}
+
+// collection reads the geometries of a multi geometry or a collection.
+func (r *wkbReader) collection(code uint32) (g Geometry, err error) {
+	// each geometry has at least a byte order and a type
+	n, err := r.count(5)
+	if err != nil {
+		return
+	}
+	var (
+		points   MultiPoint
+		lines    MultiLineString
+		polygons MultiPolygon
+		children GeometryCollection
+	)
+	for i := 0; i < n; i++ {
+		var child Geometry
+		if child, _, err = r.geometry(); err != nil {
+			return
+		}
+		ok := true
+		switch code {
+		case wkbMultiPoint:
+			if m, empty := child.(MultiPoint); empty && len(m) == 0 {
+				// Empty points are left out
+				continue
+			}
+			var p Point
+			p, ok = child.(Point)
+			points = append(points, p)
+		case wkbMultiLineString:
+			var l LineString
+			l, ok = child.(LineString)
+			lines = append(lines, l)
+		case wkbMultiPolygon:
+			var p Polygon
+			p, ok = child.(Polygon)
+			polygons = append(polygons, p)
+		default:
+			children = append(children, child)
+		}
+		if !ok {
+			return nil, ErrInvalidWKB
+		}
+	}
+	switch code {
+	case wkbMultiPoint:
+		g = append(MultiPoint{}, points...)
+	case wkbMultiLineString:
+		g = append(MultiLineString{}, lines...)
+	case wkbMultiPolygon:
+		g = append(MultiPolygon{}, polygons...)
+	default:
+		g = append(GeometryCollection{}, children...)
+	}
+	return
+}
+
+// polygon reads the rings of a polygon.
+func (r *wkbReader) polygon(dims int) (p Polygon, err error) {
+	n, err := r.count(4)
+	if err != nil {
+		return
+	}
+	p = make(Polygon, n)
+	for i := range p {
+		var points []Point
+		if points, err = r.points(dims); err != nil {
+			return
+		}
+		p[i] = points
+	}
+	return
+}
+
+// points reads the number of points then the points.
+func (r *wkbReader) points(dims int) (points []Point, err error) {
+	n, err := r.count(8 * dims)
+	if err != nil {
+		return
+	}
+	points = make([]Point, n)
+	for i := range points {
+		if points[i], err = r.point(dims); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// point reads the coordinates of a point. Z and M are skipped.
+func (r *wkbReader) point(dims int) (p Point, err error) {
+	if len(r.data)-r.pos < 8*dims {
+		return p, ErrInvalidWKB
+	}
+	p.Lon = math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	p.Lat = math.Float64frombits(r.order.Uint64(r.data[r.pos+8:]))
+	r.pos += 8 * dims
+	return
+}
+
+// count reads a number of items and checks there is enough data left for items of at least size bytes.
+func (r *wkbReader) count(size int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(size) > uint64(len(r.data)-r.pos) {
+		return 0, ErrInvalidWKB
+	}
+	return int(n), nil
+}
+
+// uint32 reads an integer.
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.data)-r.pos < 4 {
+		return 0, ErrInvalidWKB
+	}
+	v := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
@@ -0,0 +1,199 @@
+package geo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Fixtures produced by PostGIS ST_AsBinary and ST_AsEWKB
+func TestWKB(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry Geometry
+		srid     int
+		order    binary.ByteOrder
+		hex      string
+	}{
+		{
+			name:     "Point",
+			geometry: Point{2, 1},
+			order:    binary.LittleEndian,
+			hex:      "0101000000000000000000F03F0000000000000040",
+		},
+		{
+			name:     "Point big endian",
+			geometry: Point{2, 1},
+			order:    binary.BigEndian,
+			hex:      "00000000013FF00000000000004000000000000000",
+		},
+		{
+			name:     "Point SRID",
+			geometry: Point{13.76, 100.5},
+			srid:     4326,
+			order:    binary.LittleEndian,
+			hex:      "0101000020E6100000000000000020594085EB51B81E852B40",
+		},
+		{
+			name:     "LineString SRID",
+			geometry: LineString{{2, 1}, {4, 3}},
+			srid:     4326,
+			order:    binary.LittleEndian,
+			hex:      "0102000020E610000002000000000000000000F03F000000000000004000000000000008400000000000001040",
+		},
+		{
+			name:     "Polygon",
+			geometry: Polygon{Ring{{0, 0}, {0, 1}, {1, 1}, {0, 0}}},
+			order:    binary.LittleEndian,
+			hex: "0103000000010000000400000000000000000000000000000000000000000000000000F03F0000000000000000" +
+				"000000000000F03F000000000000F03F00000000000000000000000000000000",
+		},
+		{
+			name:     "MultiPoint",
+			geometry: MultiPoint{{2, 1}, {4, 3}},
+			order:    binary.LittleEndian,
+			hex: "0104000000020000000101000000000000000000F03F0000000000000040" +
+				"010100000000000000000008400000000000001040",
+		},
+		{
+			name:     "GeometryCollection",
+			geometry: GeometryCollection{Point{2, 1}, LineString{{2, 1}, {4, 3}}},
+			order:    binary.LittleEndian,
+			hex: "0107000000020000000101000000000000000000F03F0000000000000040" +
+				"010200000002000000000000000000F03F000000000000004000000000000008400000000000001040",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalEWKB(tt.geometry, tt.srid, tt.order)
+			if err != nil {
+				t.Fatalf("MarshalEWKB() error = %v", err)
+			}
+			if h := strings.ToUpper(hex.EncodeToString(got)); h != tt.hex {
+				t.Errorf("MarshalEWKB() = %v, want %v", h, tt.hex)
+			}
+			g, srid, err := UnmarshalWKBHex(tt.hex)
+			if err != nil {
+				t.Fatalf("UnmarshalWKBHex() error = %v", err)
+			}
+			if !reflect.DeepEqual(g, tt.geometry) || srid != tt.srid {
+				t.Errorf("UnmarshalWKBHex() = %v, %v, want %v, %v", g, srid, tt.geometry, tt.srid)
+			}
+		})
+	}
+}
+
+func TestUnmarshalWKB_Dimensions(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+	}{
+		{name: "EWKB Z", hex: "01010000A0E6100000000000000000F03F00000000000000400000000000000840"},
+		{name: "ISO Z", hex: "01E9030000000000000000F03F00000000000000400000000000000840"},
+		{name: "ISO ZM", hex: "01B90B0000000000000000F03F000000000000004000000000000008400000000000001040"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, _, err := UnmarshalWKBHex(tt.hex)
+			if err != nil {
+				t.Fatalf("UnmarshalWKBHex() error = %v", err)
+			}
+			if g != (Point{2, 1}) {
+				t.Errorf("UnmarshalWKBHex() = %v", g)
+			}
+		})
+	}
+}
+
+func TestUnmarshalWKB_EmptyPoint(t *testing.T) {
+	// PostGIS ST_AsBinary('POINT EMPTY') and ST_AsBinary('MULTIPOINT(EMPTY, 1 2)')
+	tests := []struct {
+		hex  string
+		want Geometry
+	}{
+		{"0101000000000000000000F87F000000000000F87F", MultiPoint{}},
+		{"0104000000020000000101000000000000000000F87F000000000000F87F0101000000000000000000F03F0000000000000040", MultiPoint{{2, 1}}},
+	}
+	for _, tt := range tests {
+		g, _, err := UnmarshalWKBHex(tt.hex)
+		if err != nil || !reflect.DeepEqual(g, tt.want) {
+			t.Errorf("UnmarshalWKBHex(%v) = %v, %v, want %v", tt.hex, g, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalWKB_Malformed(t *testing.T) {
+	nested := strings.Repeat("010700000001000000", wktMaxDepth+1) + "0101000000000000000000F03F0000000000000040"
+	tests := []struct {
+		name string
+		hex  string
+	}{
+		{"truncated header", "01010000"},
+		{"truncated SRID", "0101000020E610"},
+		{"truncated point", "0101000000000000000000F03F00000000"},
+		{"truncated ring", "01030000000100000004000000000000000000000000000000000000000000000000000000"},
+		{"truncated collection", "0107000000020000000101000000000000000000F03F0000000000000040"},
+		{"deeply nested", nested},
+		{"huge ring count", "0103000000FFFFFFFF"},
+		{"huge point count", "0102000000FFFFFF7F000000000000F03F0000000000000040"},
+		{"huge collection count", "0107000000FFFFFFFF0101000000000000000000F03F0000000000000040"},
+		{"huge count of Z points", "01020000A0E610000002000000000000000000F03F000000000000004000000000000008400000000000001040"},
+		{"unknown ISO type", "01D10F0000000000000000F03F0000000000000040"},
+	}
+	for _, tt := range tests {
+		if g, _, err := UnmarshalWKBHex(tt.hex); err != ErrInvalidWKB {
+			t.Errorf("UnmarshalWKBHex(%v) = %v, %v, want %v", tt.name, g, err, ErrInvalidWKB)
+		}
+	}
+	// Go 1.16 has no fuzzing, random changes of valid WKB must not panic
+	data, _ := MarshalEWKB(GeometryCollection{Point{1, 2}, MultiPolygon{{square(0, 0, 1, 1)}}, MultiPoint{{3, 4}}}, 4326, binary.LittleEndian)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		b := append([]byte{}, data...)
+		for j := 0; j < 1+r.Intn(3); j++ {
+			k := r.Intn(len(b))
+			switch r.Intn(3) {
+			case 0:
+				b[k] = byte(r.Intn(256))
+			case 1:
+				b = b[:k]
+			default:
+				b = append(b[:k], append([]byte{byte(r.Intn(256))}, b[k:]...)...)
+			}
+			if len(b) == 0 {
+				break
+			}
+		}
+		if g, _, err := UnmarshalWKB(b); err == nil && g == nil {
+			t.Errorf("UnmarshalWKB(%x) = nil, nil", b)
+		}
+	}
+}
+
+func TestUnmarshalWKB_Invalid(t *testing.T) {
+	for _, h := range []string{
+		"", "02", "0108000000", "zz",
+		// LineString claiming 2^32-1 points
+		"0102000000FFFFFFFF000000000000F03F0000000000000040",
+		// MultiPoint containing a LineString
+		"010400000001000000010200000001000000000000000000F03F0000000000000040",
+		// Trailing data
+		"0101000000000000000000F03F000000000000004000",
+	} {
+		if _, _, err := UnmarshalWKBHex(h); err != ErrInvalidWKB {
+			t.Errorf("UnmarshalWKBHex(%q) error = %v, want %v", h, err, ErrInvalidWKB)
+		}
+	}
+
+	// Parsing any truncated or altered WKB must not panic
+	data, _ := MarshalEWKB(GeometryCollection{MultiPolygon{{square(0, 0, 1, 1)}}, MultiLineString{{{1, 2}, {3, 4}}}}, 4326, binary.BigEndian)
+	for i := range data {
+		UnmarshalWKB(data[:i])
+		altered := append([]byte{}, data...)
+		altered[i] ^= 0xff
+		UnmarshalWKB(altered)
+	}
+}
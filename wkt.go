@@ -0,0 +1,418 @@
+package geo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// wktMaxDepth is the maximum nesting of geometry collections we accept when parsing WKT or WKB.
+const wktMaxDepth = 32
+
+// ErrInvalidWKT is returned when parsing a malformed WKT string.
+var ErrInvalidWKT = errors.New("Invalid WKT")
+
+// wktParser reads a WKT string token by token.
+type wktParser struct {
+	s     string
+	pos   int
+	depth int
+}
+
+// MarshalWKT returns the Well Known Text representation of the geometry.
+// Coordinates are written longitude first, as PostGIS and MySQL expect.
+//  geo.MarshalWKT(geo.Point{Lat: 13.76, Lon: 100.5}) // POINT (100.5 13.76)
+func MarshalWKT(g Geometry) string {
+	var b strings.Builder
+	writeWKT(&b, g)
+	return b.String()
+}
+
+// MarshalEWKT returns the PostGIS Extended Well Known Text representation of the geometry with the SRID.
+//  geo.MarshalEWKT(geo.Point{Lat: 13.76, Lon: 100.5}, 4326) // SRID=4326;POINT (100.5 13.76)
+func MarshalEWKT(g Geometry, srid int) string {
+	return "SRID=" + strconv.Itoa(srid) + ";" + MarshalWKT(g)
+}
+
+// UnmarshalWKT parses a Well Known Text geometry. Extended WKT from PostGIS is accepted:
+// the SRID is returned, or 0 if there is none. Z and M coordinates are ignored.
+// POINT EMPTY is returned as an empty MultiPoint, Point having no empty value.
+//  g, srid, err := geo.UnmarshalWKT("SRID=4326;POINT(100.5 13.76)")
+func UnmarshalWKT(s string) (g Geometry, srid int, err error) {
+	p := &wktParser{s: s}
+	p.skipSpaces()
+	if strings.HasPrefix(strings.ToUpper(p.s[p.pos:]), "SRID=") {
+		end := strings.IndexByte(p.s[p.pos:], ';')
+		if end < 0 {
+			return nil, 0, ErrInvalidWKT
+		}
+		if srid, err = strconv.Atoi(strings.TrimSpace(p.s[p.pos+5 : p.pos+end])); err != nil {
+			return nil, 0, ErrInvalidWKT
+		}
+		p.pos += end + 1
+	}
+	if g, err = p.geometry(); err != nil {
+		return nil, 0, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, 0, ErrInvalidWKT
+	}
+	return
+}
+
+// writeWKT writes the WKT of the geometry.
+func writeWKT(b *strings.Builder, g Geometry) {
+	switch geom := g.(type) {
+	case Point:
+		b.WriteString("POINT (")
+		writeWKTPoint(b, geom)
+		b.WriteByte(')')
+	case MultiPoint:
+		b.WriteString("MULTIPOINT")
+		if len(geom) == 0 {
+			b.WriteString(" EMPTY")
+			return
+		}
+		b.WriteString(" (")
+		for i, p := range geom {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('(')
+			writeWKTPoint(b, p)
+			b.WriteByte(')')
+		}
+		b.WriteByte(')')
+	case LineString:
+		b.WriteString("LINESTRING ")
+		writeWKTPoints(b, geom, false)
+	case MultiLineString:
+		b.WriteString("MULTILINESTRING")
+		if len(geom) == 0 {
+			b.WriteString(" EMPTY")
+			return
+		}
+		b.WriteString(" (")
+		for i, l := range geom {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeWKTPoints(b, l, false)
+		}
+		b.WriteByte(')')
+	case Polygon:
+		b.WriteString("POLYGON ")
+		writeWKTPolygon(b, geom)
+	case MultiPolygon:
+		b.WriteString("MULTIPOLYGON")
+		if len(geom) == 0 {
+			b.WriteString(" EMPTY")
+			return
+		}
+		b.WriteString(" (")
+		for i, p := range geom {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeWKTPolygon(b, p)
+		}
+		b.WriteByte(')')
+	case GeometryCollection:
+		b.WriteString("GEOMETRYCOLLECTION")
+		if len(geom) == 0 {
+			b.WriteString(" EMPTY")
+			return
+		}
+		b.WriteString(" (")
+		for i, child := range geom {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeWKT(b, child)
+		}
+		b.WriteByte(')')
+	}
+}
+
+// writeWKTPoint writes the coordinates of a point.
+func writeWKTPoint(b *strings.Builder, p Point) {
+	b.WriteString(formatFloat(p.Lon))
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(p.Lat))
+}
+
+// writeWKTPoints writes a list of points between parentheses, closing the ring if asked.
+func writeWKTPoints(b *strings.Builder, points []Point, closed bool) {
+	if len(points) == 0 {
+		b.WriteString("EMPTY")
+		return
+	}
+	b.WriteByte('(')
+	for i, p := range points {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeWKTPoint(b, p)
+	}
+	if closed && points[0] != points[len(points)-1] {
+		b.WriteString(", ")
+		writeWKTPoint(b, points[0])
+	}
+	b.WriteByte(')')
+}
+
+// writeWKTPolygon writes the rings of a polygon.
+func writeWKTPolygon(b *strings.Builder, p Polygon) {
+	if len(p) == 0 {
+		b.WriteString("EMPTY")
+		return
+	}
+	b.WriteByte('(')
+	for i, r := range p {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeWKTPoints(b, r, true)
+	}
+	b.WriteByte(')')
+}
+
+// geometry parses a tagged geometry.
+func (p *wktParser) geometry() (g Geometry, err error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > wktMaxDepth {
+		return nil, ErrInvalidWKT
+	}
+
+	tag := strings.ToUpper(p.word())
+	// Dimension can be a separate word (POINT Z) or glued to the type (POINTZ)
+	dims := 2
+	for _, suffix := range []string{"ZM", "Z", "M"} {
+		if strings.HasSuffix(tag, suffix) {
+			if _, ok := wktTypes[strings.TrimSuffix(tag, suffix)]; ok {
+				tag = strings.TrimSuffix(tag, suffix)
+				dims = 2 + len(suffix)
+				break
+			}
+		}
+	}
+	if _, ok := wktTypes[tag]; !ok {
+		return nil, ErrInvalidWKT
+	}
+	if dims == 2 {
+		save := p.pos
+		switch strings.ToUpper(p.word()) {
+		case "Z", "M":
+			dims = 3
+		case "ZM":
+			dims = 4
+		default:
+			p.pos = save
+		}
+	}
+
+	empty := p.empty()
+	switch wktTypes[tag] {
+	case TypePoint:
+		if empty {
+			return MultiPoint{}, nil
+		}
+		var points []Point
+		if points, err = p.points(dims); err != nil {
+			return
+		}
+		if len(points) != 1 {
+			return nil, ErrInvalidWKT
+		}
+		return points[0], nil
+	case TypeMultiPoint:
+		if empty {
+			return MultiPoint{}, nil
+		}
+		points, err := p.multiPoint(dims)
+		return MultiPoint(points), err
+	case TypeLineString:
+		if empty {
+			return LineString{}, nil
+		}
+		points, err := p.points(dims)
+		return LineString(points), err
+	case TypeMultiLineString:
+		if empty {
+			return MultiLineString{}, nil
+		}
+		var lines MultiLineString
+		err = p.list(func() error {
+			points, err := p.points(dims)
+			lines = append(lines, points)
+			return err
+		})
+		return lines, err
+	case TypePolygon:
+		if empty {
+			return Polygon{}, nil
+		}
+		return p.polygon(dims)
+	case TypeMultiPolygon:
+		if empty {
+			return MultiPolygon{}, nil
+		}
+		var polygons MultiPolygon
+		err = p.list(func() error {
+			polygon, err := p.polygon(dims)
+			polygons = append(polygons, polygon)
+			return err
+		})
+		return polygons, err
+	default:
+		if empty {
+			return GeometryCollection{}, nil
+		}
+		var collection GeometryCollection
+		err = p.list(func() error {
+			child, err := p.geometry()
+			collection = append(collection, child)
+			return err
+		})
+		return collection, err
+	}
+}
+
+// wktTypes maps WKT tags to geometry types.
+var wktTypes = map[string]string{
+	"POINT":              TypePoint,
+	"MULTIPOINT":         TypeMultiPoint,
+	"LINESTRING":         TypeLineString,
+	"MULTILINESTRING":    TypeMultiLineString,
+	"POLYGON":            TypePolygon,
+	"MULTIPOLYGON":       TypeMultiPolygon,
+	"GEOMETRYCOLLECTION": TypeGeometryCollection,
+}
+
+// polygon parses a list of rings.
+func (p *wktParser) polygon(dims int) (polygon Polygon, err error) {
+	err = p.list(func() error {
+		points, err := p.points(dims)
+		polygon = append(polygon, points)
+		return err
+	})
+	return
+}
+
+// multiPoint parses points of a multipoint, with or without parentheses around each point.
+func (p *wktParser) multiPoint(dims int) (points []Point, err error) {
+	err = p.list(func() error {
+		p.skipSpaces()
+		if p.pos < len(p.s) && p.s[p.pos] == '(' {
+			pts, err := p.points(dims)
+			if err == nil && len(pts) != 1 {
+				err = ErrInvalidWKT
+			}
+			points = append(points, pts...)
+			return err
+		}
+		pt, err := p.coordinates(dims)
+		points = append(points, pt)
+		return err
+	})
+	return
+}
+
+// points parses a list of coordinates between parentheses, or EMPTY.
+func (p *wktParser) points(dims int) (points []Point, err error) {
+	if p.empty() {
+		return
+	}
+	err = p.list(func() error {
+		pt, err := p.coordinates(dims)
+		points = append(points, pt)
+		return err
+	})
+	return
+}
+
+// list parses a list of items separated by commas between parentheses.
+func (p *wktParser) list(item func() error) error {
+	if !p.consume('(') {
+		return ErrInvalidWKT
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.consume(')') {
+			return nil
+		}
+		if !p.consume(',') {
+			return ErrInvalidWKT
+		}
+	}
+}
+
+// coordinates parses the coordinates of a point: longitude, latitude then optional Z and M.
+func (p *wktParser) coordinates(dims int) (pt Point, err error) {
+	values := make([]float64, dims)
+	for i := range values {
+		word := p.word()
+		if values[i], err = strconv.ParseFloat(word, 64); err != nil {
+			return pt, ErrInvalidWKT
+		}
+	}
+	// Some writers omit the Z or M keyword but write more coordinates, we skip them
+	for extra := dims; extra < 4; extra++ {
+		save := p.pos
+		if _, err := strconv.ParseFloat(p.word(), 64); err != nil {
+			p.pos = save
+			break
+		}
+	}
+	return Point{Lat: values[1], Lon: values[0]}, nil
+}
+
+// empty returns true and consumes the EMPTY keyword if it is next.
+func (p *wktParser) empty() bool {
+	save := p.pos
+	if strings.ToUpper(p.word()) == "EMPTY" {
+		return true
+	}
+	p.pos = save
+	return false
+}
+
+// consume skips spaces and the given character, it returns false if the next character is different.
+func (p *wktParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// word returns the next word: letters, digits, signs and dots.
+func (p *wktParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '(' || c == ')' || c == ',' || c == ';' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// skipSpaces moves after white spaces.
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
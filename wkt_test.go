@@ -0,0 +1,147 @@
+package geo
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalWKT(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry Geometry
+		want     string
+	}{
+		{name: "Point", geometry: Point{13.76, 100.5}, want: "POINT (100.5 13.76)"},
+		{name: "LineString", geometry: LineString{{13.76, 100.5}, {13.89, 101.12}}, want: "LINESTRING (100.5 13.76, 101.12 13.89)"},
+		{name: "Empty LineString", geometry: LineString{}, want: "LINESTRING EMPTY"},
+		{name: "Polygon", geometry: Polygon{square(0, 0, 4, 4), Ring{{1, 1}, {1, 2}, {2, 2}}},
+			want: "POLYGON ((0 0, 4 0, 4 4, 0 4, 0 0), (1 1, 2 1, 2 2, 1 1))"},
+		{name: "MultiPoint", geometry: MultiPoint{{1, 2}, {3, 4}}, want: "MULTIPOINT ((2 1), (4 3))"},
+		{name: "MultiLineString", geometry: MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+			want: "MULTILINESTRING ((2 1, 4 3), (6 5, 8 7))"},
+		{name: "MultiPolygon", geometry: MultiPolygon{{Ring{{0, 0}, {0, 1}, {1, 1}, {0, 0}}}},
+			want: "MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))"},
+		{name: "GeometryCollection", geometry: GeometryCollection{Point{1, 2}, LineString{{1, 2}, {3, 4}}},
+			want: "GEOMETRYCOLLECTION (POINT (2 1), LINESTRING (2 1, 4 3))"},
+		{name: "Empty GeometryCollection", geometry: GeometryCollection{}, want: "GEOMETRYCOLLECTION EMPTY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarshalWKT(tt.geometry)
+			if got != tt.want {
+				t.Errorf("MarshalWKT() = %v, want %v", got, tt.want)
+			}
+			back, srid, err := UnmarshalWKT(got)
+			if err != nil || srid != 0 {
+				t.Fatalf("UnmarshalWKT() error = %v, srid = %v", err, srid)
+			}
+			if again := MarshalWKT(back); again != tt.want {
+				t.Errorf("UnmarshalWKT() = %v", again)
+			}
+		})
+	}
+}
+
+func TestUnmarshalWKT(t *testing.T) {
+	tests := []struct {
+		name string
+		wkt  string
+		want Geometry
+		srid int
+	}{
+		{name: "Compact", wkt: "POINT(100.5 13.76)", want: Point{13.76, 100.5}},
+		{name: "Lower case", wkt: " point ( 100.5   13.76 ) ", want: Point{13.76, 100.5}},
+		{name: "EWKT", wkt: "SRID=4326;POINT(100.5 13.76)", want: Point{13.76, 100.5}, srid: 4326},
+		{name: "Z", wkt: "POINT Z (100.5 13.76 12)", want: Point{13.76, 100.5}},
+		{name: "ZM glued", wkt: "LINESTRINGZM(1 2 3 4,5 6 7 8)", want: LineString{{2, 1}, {6, 5}}},
+		{name: "Implicit Z", wkt: "LINESTRING(1 2 3,5 6 7)", want: LineString{{2, 1}, {6, 5}}},
+		{name: "MultiPoint without parentheses", wkt: "MULTIPOINT(1 2, 3 4)", want: MultiPoint{{2, 1}, {4, 3}}},
+		{name: "Exponent", wkt: "POINT(1e2 -1.5E-1)", want: Point{-0.15, 100}},
+		{name: "Empty point", wkt: "POINT EMPTY", want: MultiPoint{}},
+		{name: "Empty point Z", wkt: "SRID=4326;POINT Z EMPTY", want: MultiPoint{}, srid: 4326},
+		{name: "Empty point in collection", wkt: "GEOMETRYCOLLECTION(POINT EMPTY, POINT(1 2))", want: GeometryCollection{MultiPoint{}, Point{2, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, srid, err := UnmarshalWKT(tt.wkt)
+			if err != nil {
+				t.Fatalf("UnmarshalWKT() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || srid != tt.srid {
+				t.Errorf("UnmarshalWKT() = %v, %v, want %v, %v", got, srid, tt.want, tt.srid)
+			}
+		})
+	}
+}
+
+func TestUnmarshalWKT_Invalid(t *testing.T) {
+	for _, wkt := range []string{
+		"", "POINT", "POINT(1)", "POINT(1 2", "POINT(1 2))", "POINT(1 2, 3 4)", "POINT(a b)",
+		"CIRCLE(1 2)", "LINESTRING(1 2,)", "POLYGON((1 2, 3 4)", "SRID=abc;POINT(1 2)", "SRID=4326POINT(1 2)",
+		"GEOMETRYCOLLECTION(" + repeat("GEOMETRYCOLLECTION(", 100) + "POINT(1 2)" + repeat(")", 101),
+	} {
+		if _, _, err := UnmarshalWKT(wkt); err != ErrInvalidWKT {
+			t.Errorf("UnmarshalWKT(%q) error = %v, want %v", wkt, err, ErrInvalidWKT)
+		}
+	}
+	// Parsing any prefix of a valid WKT must not panic
+	valid := "SRID=4326;GEOMETRYCOLLECTION (POINT Z (2 1 0), MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0))), MULTIPOINT (1 2, 3 4))"
+	for i := range valid {
+		UnmarshalWKT(valid[:i])
+	}
+}
+
+func TestUnmarshalWKT_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		wkt  string
+	}{
+		{"truncated tag", "POLYG"},
+		{"truncated coordinates", "LINESTRING(1 2, 3"},
+		{"truncated collection", "GEOMETRYCOLLECTION(POINT(1 2), LINESTRING(1 2, 3 4)"},
+		{"missing list", "MULTIPOLYGON((1 2, 3 4))"},
+		{"deeply nested", repeat("GEOMETRYCOLLECTION(", wktMaxDepth+1) + "POINT(1 2)" + repeat(")", wktMaxDepth+1)},
+		{"deeply nested unclosed", strings.Repeat("GEOMETRYCOLLECTION(", 100000)},
+		{"huge number", "POINT(1e999 2)"},
+		{"huge SRID", "SRID=99999999999999999999;POINT(1 2)"},
+		{"many coordinates", "POINT(" + strings.Repeat("1 ", 10000) + ")"},
+		{"unclosed long list", "LINESTRING(" + strings.Repeat("1 2,", 10000)},
+		{"binary", "POINT(\x00\xff)"},
+	}
+	for _, tt := range tests {
+		if g, _, err := UnmarshalWKT(tt.wkt); err != ErrInvalidWKT {
+			t.Errorf("UnmarshalWKT(%v) = %v, %v, want %v", tt.name, g, err, ErrInvalidWKT)
+		}
+	}
+	// Go 1.16 has no fuzzing, random changes of a valid WKT must not panic
+	valid := "SRID=4326;GEOMETRYCOLLECTION (POINT Z (2 1 0), POINT EMPTY, MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), EMPTY), MULTIPOINT (1 2, 3 4))"
+	r := rand.New(rand.NewSource(1))
+	chars := "()., -+eE0123456789POINTEMPTYZ;="
+	for i := 0; i < 10000; i++ {
+		b := []byte(valid)
+		for j := 0; j < 1+r.Intn(3); j++ {
+			k := r.Intn(len(b))
+			switch r.Intn(3) {
+			case 0:
+				b[k] = chars[r.Intn(len(chars))]
+			case 1:
+				b = append(b[:k], b[k+1:]...)
+			default:
+				b = append(b[:k], append([]byte{chars[r.Intn(len(chars))]}, b[k:]...)...)
+			}
+		}
+		if g, _, err := UnmarshalWKT(string(b)); err == nil && g == nil {
+			t.Errorf("UnmarshalWKT(%q) = nil, nil", b)
+		}
+	}
+}
+
+// repeat returns s repeated n times.
+func repeat(s string, n int) (r string) {
+	for i := 0; i < n; i++ {
+		r += s
+	}
+	return
+}